@@ -45,9 +45,36 @@ const (
 	// schema is applied to the consumer cluster.
 	APIServiceBindingConditionSchemaInSync conditionsapi.ConditionType = "SchemaInSync"
 
+	// APIServiceBindingConditionProviderReachable is set to true while the API server
+	// of every service provider cluster of the binding is reachable.
+	APIServiceBindingConditionProviderReachable conditionsapi.ConditionType = "ProviderReachable"
+
 	// DownstreamFinalizer is put on downstream objects to block their deletion until
 	// the upstream object has been deleted.
 	DownstreamFinalizer = "kubebind.io/syncer"
+
+	// DownstreamConditionFieldConflict is set on downstream objects when fields set
+	// by the consumer are owned by another field manager in the service provider cluster.
+	DownstreamConditionFieldConflict conditionsapi.ConditionType = "kube-bind.appscode.com/FieldConflict"
+
+	// DownstreamConditionSynced is set on downstream objects by the konnector. It is
+	// true if the object has been synced to its upstream object, with the upstream
+	// namespace/name and the last sync time in the message, and false with the last
+	// error otherwise.
+	DownstreamConditionSynced conditionsapi.ConditionType = "kube-bind.appscode.com/Synced"
+
+	// APIServiceBindingConditionPaused is set to true while spec.paused of the
+	// APIServiceBinding is true.
+	APIServiceBindingConditionPaused conditionsapi.ConditionType = "Paused"
+
+	// DownstreamPausedAnnotation set to "true" on a downstream object pauses its sync
+	// in both directions until the annotation is removed. The deletion of the object
+	// is not paused.
+	DownstreamPausedAnnotation = "kube-bind.appscode.com/paused"
+
+	// DownstreamConditionPaused is set on downstream objects whose sync is paused
+	// through the DownstreamPausedAnnotation.
+	DownstreamConditionPaused conditionsapi.ConditionType = "kube-bind.appscode.com/Paused"
 )
 
 // APIServiceBinding binds an API service represented by a APIServiceExport
@@ -89,6 +116,54 @@ type APIServiceBindingSpec struct {
 	// +kubebuilder:validation:Required
 	// Providers contains the provider ClusterIdentity and KubeconfigSecretRef of the provider cluster
 	Providers []Provider `json:"providers,omitempty"`
+
+	// paused stops syncing the objects of the bound resource in both directions
+	// while true. Nothing is deleted. When unpaused, all objects are resynced.
+	//
+	// +optional
+	Paused bool `json:"paused,omitempty"`
+
+	// sync overrides the konnector defaults for the controllers syncing the
+	// objects of the bound resource. Changes restart the controllers.
+	//
+	// +optional
+	Sync *SyncTuning `json:"sync,omitempty"`
+}
+
+// SyncTuning tunes the controllers syncing the objects of a bound resource.
+// Unset fields keep the konnector defaults.
+type SyncTuning struct {
+	// workers is the number of objects reconciled in parallel by each sync
+	// controller.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	Workers int32 `json:"workers,omitempty"`
+
+	// providerQPS is the maximum number of requests per second of the sync
+	// controllers towards each provider cluster.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ProviderQPS int32 `json:"providerQPS,omitempty"`
+
+	// providerBurst is the maximum burst of requests of the sync controllers
+	// towards each provider cluster.
+	//
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	ProviderBurst int32 `json:"providerBurst,omitempty"`
+
+	// retryBaseDelay is the delay of the first retry of a failed object. It
+	// doubles with every further failure up to retryMaxDelay.
+	//
+	// +optional
+	RetryBaseDelay *metav1.Duration `json:"retryBaseDelay,omitempty"`
+
+	// retryMaxDelay is the maximum delay between retries of a failed object.
+	//
+	// +optional
+	RetryMaxDelay *metav1.Duration `json:"retryMaxDelay,omitempty"`
 }
 
 type Provider struct {
@@ -106,6 +181,63 @@ type ClusterIdentity struct {
 type APIServiceBindingStatus struct {
 	// conditions is a list of conditions that apply to the APIServiceBinding.
 	Conditions conditionsapi.Conditions `json:"conditions,omitempty"`
+
+	// drift summarizes the differences between the consumer and the provider
+	// objects of the bound resource, per provider cluster. It is computed
+	// periodically by the konnector.
+	//
+	// +optional
+	Drift []ProviderDrift `json:"drift,omitempty"`
+}
+
+// ProviderDrift is the drift between the consumer objects and the objects of one
+// provider cluster.
+type ProviderDrift struct {
+	// clusterUID is the UID of the provider cluster.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	ClusterUID string `json:"clusterUID"`
+
+	// lastCheckTime is the time of the last drift check with this result. It is
+	// refreshed at least every 10 minutes.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	LastCheckTime metav1.Time `json:"lastCheckTime"`
+
+	// missingUpstream are consumer objects without provider object.
+	//
+	// +optional
+	MissingUpstream DriftedObjects `json:"missingUpstream,omitempty"`
+
+	// missingDownstream are provider objects without consumer object.
+	//
+	// +optional
+	MissingDownstream DriftedObjects `json:"missingDownstream,omitempty"`
+
+	// specMismatch are objects whose consumer and provider spec differ.
+	//
+	// +optional
+	SpecMismatch DriftedObjects `json:"specMismatch,omitempty"`
+
+	// staleFinalizers are consumer objects deleting for more than 5 minutes
+	// which still have the kube-bind finalizer.
+	//
+	// +optional
+	StaleFinalizers DriftedObjects `json:"staleFinalizers,omitempty"`
+}
+
+// DriftedObjects are the objects that drifted in one way.
+type DriftedObjects struct {
+	// count is the number of drifted objects.
+	Count int32 `json:"count,omitempty"`
+
+	// objects lists up to 10 of the drifted objects by their consumer side
+	// namespace/name, or name if cluster-scoped.
+	//
+	// +optional
+	Objects []string `json:"objects,omitempty"`
 }
 
 // APIServiceBindingList is a list of APIServiceBindings.
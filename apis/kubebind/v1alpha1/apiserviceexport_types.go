@@ -31,6 +31,31 @@ const (
 
 const (
 	SourceSpecHashAnnotationKey = "kube-bind.appscode.com/source-spec-hash"
+
+	// DeletionPolicyAnnotation on a downstream object overrides the deletionPolicy
+	// of the APIServiceExport for that object.
+	DeletionPolicyAnnotation = "kube-bind.appscode.com/deletion-policy"
+
+	// OrphanedLabel is set to "true" on upstream objects orphaned by the Orphan
+	// deletion policy.
+	OrphanedLabel = "kube-bind.appscode.com/orphaned"
+
+	// OrphanedAtAnnotation holds the time an upstream object was orphaned.
+	OrphanedAtAnnotation = "kube-bind.appscode.com/orphaned-at"
+
+	// AdoptLabel is set to "true" by the service provider on upstream objects it
+	// created itself and which should be adopted into the consumer cluster.
+	AdoptLabel = "kube-bind.appscode.com/adopt"
+
+	// ConsumerScopeAnnotation set to "Cluster" on a namespaced CRD of the service
+	// provider exports the resource as cluster-scoped API. The objects of each
+	// consumer are kept in its cluster namespace, i.e. with Namespaced isolation.
+	ConsumerScopeAnnotation = "kube-bind.appscode.com/consumer-scope"
+
+	// ProviderConversionAnnotation on a consumer CRD holds the JSON encoded
+	// conversion of the APIServiceExport, which the conversion webhook of the
+	// konnector delegates to.
+	ProviderConversionAnnotation = "kube-bind.appscode.com/provider-conversion"
 )
 
 const (
@@ -81,7 +106,7 @@ func (in *APIServiceExport) SetConditions(conditions conditionsapi.Conditions) {
 
 // APIServiceExportSpec defines the desired state of APIServiceExport.
 //
-// +kubebuilder:validation:XValidation:rule=`self.scope == "Namespaced" || self.informerScope == "Cluster"`,message="informerScope must be Cluster for cluster-scoped resources"
+// +kubebuilder:validation:XValidation:rule=`self.scope == "Namespaced" || self.informerScope == "Cluster" || (has(self.clusterScopedIsolation) && self.clusterScopedIsolation == "Namespaced")`,message="informerScope must be Cluster for cluster-scoped resources not isolated in the cluster namespace"
 // +kubebuilder:validation:XValidation:rule=`self.scope == "Namespaced" || has(self.clusterScopedIsolation)`,message="clusterScopedIsolation must be defined for cluster-scoped resources"
 // +kubebuilder:validation:XValidation:rule=`self.scope == "Cluster" || !has(self.clusterScopedIsolation)`,message="clusterScopedIsolation is not relevant for namespaced resources"
 type APIServiceExportSpec struct {
@@ -102,8 +127,186 @@ type APIServiceExportSpec struct {
 	// ClusterScopedIsolation specifies how cluster scoped service objects are isolated between multiple consumers on the provider side.
 	// It can be "Prefixed", "Namespaced", or "None".
 	ClusterScopedIsolation Isolation `json:"clusterScopedIsolation,omitempty"`
+
+	// fieldSync overrides the direction in which individual fields of the service
+	// objects are synced. By default, spec flows from the consumer to the provider,
+	// status flows from the provider to the consumer, and all other fields are not
+	// synced. The rule with the longest matching path wins.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=path
+	FieldSync []FieldSyncRule `json:"fieldSync,omitempty"`
+
+	// metadataSync selects the labels and annotations that are synced between
+	// consumer and provider objects. Unset filters default to the configuration
+	// of the konnector.
+	//
+	// +optional
+	MetadataSync *MetadataSync `json:"metadataSync,omitempty"`
+
+	// relatedResources lists the objects referenced by the service objects that
+	// are synced along with them, e.g. Secrets with credentials referenced in
+	// spec.credentialsRef.name and copied into the service namespace of the
+	// provider. Copies are owned by the referencing object, kept up to date and
+	// deleted when not referenced anymore. They carry the name suffix of their
+	// direction, and the reference is rewritten to the copy.
+	//
+	// If empty, the Secret named in status.secretRef.name is synced to the consumer.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=path
+	RelatedResources []RelatedResource `json:"relatedResources,omitempty"`
+
+	// deletionPolicy specifies what happens to the provider object when its consumer
+	// object is deleted, or is missing. It can be overridden per consumer object with
+	// the kube-bind.appscode.com/deletion-policy annotation.
+	//
+	// Delete:  the provider object is deleted.
+	// Orphan:  the provider object is kept, but detached from the consumer and tagged
+	//          with the kube-bind.appscode.com/orphaned label until a consumer object
+	//          of the same name adopts it again.
+	// Retain:  the provider object is kept as is, and is synced again when a consumer
+	//          object of the same name is created.
+	//
+	// +optional
+	// +kubebuilder:default=Delete
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
+
+	// adopt enables the adoption of provider objects, e.g. default instances or
+	// imported legacy ones. Provider objects with the kube-bind.appscode.com/adopt
+	// label which have never been synced are created on the consumer side, in the
+	// consumer namespace of their APIServiceNamespace, and are synced normally
+	// from then on.
+	//
+	// +optional
+	Adopt bool `json:"adopt,omitempty"`
+}
+
+// RelatedResource specifies a reference of service objects to another object.
+type RelatedResource struct {
+	// path is the JSON path in dot notation of the string field holding the name
+	// of the referenced object in the same namespace, e.g. "status.secretRef.name".
+	// A field name suffixed with "[]" selects all items of a list, e.g.
+	// "status.configMaps[].name".
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^\.?[^.]+(\.[^.]+)*$`
+	Path string `json:"path"`
+
+	// kind is the kind of the referenced object. It can be "Secret" or "ConfigMap".
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=Secret;ConfigMap
+	Kind string `json:"kind"`
+
+	// direction is the direction in which the referenced object is synced. It
+	// must match the direction the field at path is synced in.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:XValidation:rule="self != 'ignore'",message="direction must be toProvider or toConsumer"
+	Direction SyncDirection `json:"direction"`
+}
+
+// MetadataSync selects the labels and annotations synced in each direction.
+type MetadataSync struct {
+	// toProvider selects the labels and annotations of consumer objects that are
+	// synced to provider objects.
+	//
+	// +optional
+	ToProvider *MetadataFilter `json:"toProvider,omitempty"`
+
+	// toConsumer selects the labels and annotations of provider objects that are
+	// synced to consumer objects. Keys selected in both directions are owned by
+	// the provider.
+	//
+	// +optional
+	ToConsumer *MetadataFilter `json:"toConsumer,omitempty"`
+}
+
+// MetadataFilter selects labels and annotations by key prefix.
+type MetadataFilter struct {
+	// labels selects the labels.
+	//
+	// +optional
+	Labels *PrefixFilter `json:"labels,omitempty"`
+
+	// annotations selects the annotations.
+	//
+	// +optional
+	Annotations *PrefixFilter `json:"annotations,omitempty"`
+}
+
+// PrefixFilter selects keys by prefix. A key is selected if it starts with one
+// of the included prefixes and with none of the excluded prefixes. The prefix
+// "*" matches all keys.
+type PrefixFilter struct {
+	// include lists the prefixes of selected keys.
+	//
+	// +optional
+	Include []string `json:"include,omitempty"`
+
+	// exclude lists the prefixes of keys that are not selected, even if included.
+	//
+	// +optional
+	Exclude []string `json:"exclude,omitempty"`
+}
+
+// FieldSyncRule specifies the sync direction of a field and its children.
+type FieldSyncRule struct {
+	// path is the JSON path of the field in dot notation, e.g. "spec.endpoint" or
+	// ".status.note". Fields below metadata cannot be selected.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^\.?[^.]+(\.[^.]+)*$`
+	Path string `json:"path"`
+
+	// direction is the direction in which the field is synced. It can be
+	// "toProvider", "toConsumer", or "ignore".
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Direction SyncDirection `json:"direction"`
 }
 
+// SyncDirection is an enum defining the directions a field can be synced in.
+//
+// +kubebuilder:validation:Enum=toProvider;toConsumer;ignore
+type SyncDirection string
+
+const (
+	// SyncDirectionToProvider syncs the field from the consumer object to the provider object.
+	SyncDirectionToProvider SyncDirection = "toProvider"
+
+	// SyncDirectionToConsumer syncs the field from the provider object to the consumer object.
+	SyncDirectionToConsumer SyncDirection = "toConsumer"
+
+	// SyncDirectionIgnore does not sync the field in either direction.
+	SyncDirectionIgnore SyncDirection = "ignore"
+)
+
+// DeletionPolicy is an enum defining what happens to provider objects when
+// their consumer objects are deleted.
+//
+// +kubebuilder:validation:Enum=Delete;Orphan;Retain
+type DeletionPolicy string
+
+const (
+	// DeletionPolicyDelete deletes the provider object with the consumer object.
+	DeletionPolicyDelete DeletionPolicy = "Delete"
+
+	// DeletionPolicyOrphan keeps the provider object, detaches and tags it as orphaned.
+	DeletionPolicyOrphan DeletionPolicy = "Orphan"
+
+	// DeletionPolicyRetain keeps the provider object as is.
+	DeletionPolicyRetain DeletionPolicy = "Retain"
+)
+
 // Isolation is an enum defining the different ways to isolate cluster scoped objects
 //
 // +kubebuilder:validation:Enum=Prefixed;Namespaced;None
@@ -114,6 +317,7 @@ const (
 	IsolationPrefixed Isolation = "Prefixed"
 
 	// Maps a consumer side object into a namespaced object inside the corresponding cluster namespace.
+	// The resource is namespaced on the provider side, see ConsumerScopeAnnotation.
 	IsolationNamespaced Isolation = "Namespaced"
 
 	// Used for the case of a dedicated provider where isolation is not necessary.
@@ -141,6 +345,10 @@ type APIServiceExportCRDSpec struct {
 
 	// versions is the API version of the defined custom resource.
 	//
+	// Objects are synced through the storage version, or through the first
+	// served version if the storage version is not served. The service provider
+	// converts between the versions.
+	//
 	// Note: the OpenAPI v3 schemas must be equal for all versions until CEL
 	//       version migration is supported.
 	//
@@ -149,6 +357,15 @@ type APIServiceExportCRDSpec struct {
 	// +listMapKey=name
 	// +kubebuilder:validation:MinItems=1
 	Versions []APIServiceExportVersion `json:"versions"`
+
+	// conversion is the conversion between the versions of the CRD of the
+	// service provider. The konnector serves all versions on the consumer side
+	// and delegates their conversion to it: None conversions only change the
+	// apiVersion, webhook conversions are sent to the webhook of the service
+	// provider.
+	//
+	// +optional
+	Conversion *apiextensionsv1.CustomResourceConversion `json:"conversion,omitempty"`
 }
 
 // APIServiceExportVersion describes one API version of a resource.
@@ -41,12 +41,20 @@ type BindingProvider struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	AuthenticationMethods []AuthenticationMethod `json:"authenticationMethods,omitempty"`
+
+	// catalogURL is the url of the ExportCatalog listing the resources that can
+	// be bound, if the provider serves one.
+	//
+	// +optional
+	CatalogURL string `json:"catalogURL,omitempty"`
 }
 
 type AuthenticationMethod struct {
 	// method is the name of the authentication method. The follow methods are supported:
 	//
 	// - "OAuth2CodeGrant"
+	// - "OAuth2DeviceGrant"
+	// - "Token"
 	//
 	// The list is ordered by preference by the service provider. The consumer should
 	// try to use the first method in the list that matches the capabilities of the
@@ -55,11 +63,19 @@ type AuthenticationMethod struct {
 	//
 	// +required
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=OAuth2CodeGrant
+	// +kubebuilder:validation:Enum=OAuth2CodeGrant;OAuth2DeviceGrant;Token
 	Method string `json:"method,omitempty"`
 
 	// OAuth2CodeGrant is the configuration for the OAuth2 code grant flow.
 	OAuth2CodeGrant *OAuth2CodeGrant `json:"oauth2CodeGrant,omitempty"`
+
+	// OAuth2DeviceGrant is the configuration for the OAuth2 device authorization
+	// grant flow (RFC 8628). It does not need a web browser on the consumer side.
+	OAuth2DeviceGrant *OAuth2DeviceGrant `json:"oauth2DeviceGrant,omitempty"`
+
+	// token is the configuration for exchanging a bind token minted by the service
+	// provider for a BindingResponse, without interactive login.
+	Token *TokenExchange `json:"token,omitempty"`
 }
 
 type OAuth2CodeGrant struct {
@@ -71,3 +87,62 @@ type OAuth2CodeGrant struct {
 	// +kubebuilder:validation:MinLength=1
 	AuthenticatedURL string `json:"authenticatedURL"`
 }
+
+type OAuth2DeviceGrant struct {
+	// deviceAuthorizationURL is the service provider url the consumer posts to in order
+	// to start the flow. It returns an OAuth2DeviceAuthorization.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	DeviceAuthorizationURL string `json:"deviceAuthorizationURL"`
+
+	// tokenURL is the service provider url the consumer polls with the device code
+	// until the BindingResponse is returned.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	TokenURL string `json:"tokenURL"`
+}
+
+// OAuth2DeviceAuthorization is returned by the device authorization url of the
+// OAuth2DeviceGrant method. The field names follow RFC 8628.
+//
+// +k8s:deepcopy-gen=false
+type OAuth2DeviceAuthorization struct {
+	// deviceCode is the code the consumer polls the token url with.
+	DeviceCode string `json:"device_code"`
+	// userCode is the code the user enters at the verification uri.
+	UserCode string `json:"user_code"`
+	// verificationURI is the url the user visits in a web browser on any device.
+	VerificationURI string `json:"verification_uri"`
+	// verificationURIComplete is the verification uri including the user code.
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	// expiresIn is the lifetime in seconds of the device and user codes.
+	ExpiresIn int `json:"expires_in"`
+	// interval is the minimal number of seconds between polls of the token url.
+	Interval int `json:"interval,omitempty"`
+}
+
+// OAuth2DeviceTokenError is returned by the token url of the OAuth2DeviceGrant
+// method, with status code 400, as long as there is no BindingResponse.
+//
+// +k8s:deepcopy-gen=false
+type OAuth2DeviceTokenError struct {
+	// error is one of "authorization_pending", "slow_down", "access_denied" or
+	// "expired_token".
+	Error string `json:"error"`
+	// errorDescription is a human readable description of the error.
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
+type TokenExchange struct {
+	// exchangeURL is the service provider url the consumer posts to with the bind
+	// token as bearer token. It returns the BindingResponse.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	ExchangeURL string `json:"exchangeURL"`
+}
@@ -0,0 +1,168 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	conditionsapi "kmodules.xyz/client-go/api/v1"
+)
+
+const (
+	ResourceKindBindingRequest = "BindingRequest"
+	ResourceBindingRequest     = "bindingrequest"
+	ResourceBindingRequests    = "bindingrequests"
+)
+
+const (
+	// BindingRequestConditionAuthenticated is set to true when the konnector has
+	// authenticated to the service provider and stored the kubeconfig of the
+	// provider cluster.
+	BindingRequestConditionAuthenticated conditionsapi.ConditionType = "Authenticated"
+
+	// BindingRequestConditionBound is set to true when all APIServiceExportRequests
+	// have succeeded and the APIServiceBindings have been created.
+	BindingRequestConditionBound conditionsapi.ConditionType = "Bound"
+)
+
+// BindingRequest binds the resources of a service provider declaratively, like
+// kubectl bind does interactively. The konnector authenticates with the given
+// credentials, stores the kubeconfig of the provider cluster, creates the
+// APIServiceExportRequests in the provider cluster and then the
+// APIServiceBindings. This object lives in the consumer cluster.
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kube-bindings
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="URL",type="string",JSONPath=`.spec.url`,priority=1
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].status`,priority=0
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=`.status.conditions[?(@.type=="Ready")].message`,priority=0
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`,priority=0
+type BindingRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec specifies the service provider and the resources to bind.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec BindingRequestSpec `json:"spec"`
+
+	// status contains reconciliation information for the binding request.
+	Status BindingRequestStatus `json:"status,omitempty"`
+}
+
+func (in *BindingRequest) GetConditions() conditionsapi.Conditions {
+	return in.Status.Conditions
+}
+
+func (in *BindingRequest) SetConditions(conditions conditionsapi.Conditions) {
+	in.Status.Conditions = conditions
+}
+
+type BindingRequestSpec struct {
+	// url is the export URL of the service provider as passed to kubectl bind,
+	// including the user and cluster query parameters.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// credentials authenticate the konnector to the service provider.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Credentials BindingRequestCredentials `json:"credentials"`
+
+	// resources restricts the bound resources. If empty, all resources offered
+	// by the service provider for the credentials are bound.
+	//
+	// +optional
+	Resources []GroupResource `json:"resources,omitempty"`
+}
+
+// BindingRequestCredentials are the credentials of a BindingRequest.
+type BindingRequestCredentials struct {
+	// tokenSecretRef references a bind token minted by the service provider.
+	// The secret must live in the konnector namespace.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	TokenSecretRef TokenSecretKeyRef `json:"tokenSecretRef"`
+}
+
+type TokenSecretKeyRef struct {
+	// Name of the referent.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// The key of the secret to select from.
+	//
+	// +optional
+	// +kubebuilder:default=token
+	Key string `json:"key,omitempty"`
+}
+
+type BindingRequestStatus struct {
+	// observedGeneration is the generation of the spec the service provider
+	// has last been authenticated to.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// kubeconfigSecretName is the name of the secret in the konnector namespace
+	// holding the kubeconfig of the service provider cluster.
+	//
+	// +optional
+	KubeconfigSecretName string `json:"kubeconfigSecretName,omitempty"`
+
+	// remoteNamespace is the namespace of the consumer in the service provider
+	// cluster.
+	//
+	// +optional
+	RemoteNamespace string `json:"remoteNamespace,omitempty"`
+
+	// exportRequests are the names of the APIServiceExportRequests in the
+	// remote namespace which have not succeeded yet.
+	//
+	// +optional
+	ExportRequests []string `json:"exportRequests,omitempty"`
+
+	// bindings are the names of the APIServiceBindings of the request.
+	//
+	// +optional
+	Bindings []string `json:"bindings,omitempty"`
+
+	// conditions is a list of conditions that apply to the BindingRequest.
+	Conditions conditionsapi.Conditions `json:"conditions,omitempty"`
+}
+
+// BindingRequestList is a list of BindingRequests.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BindingRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []BindingRequest `json:"items"`
+}
@@ -59,6 +59,18 @@ type BindingResponseAuthentication struct {
 	// +optional
 	// +kubebuilder:validation:Optional
 	OAuth2CodeGrant *BindingResponseAuthenticationOAuth2CodeGrant `json:"oauth2CodeGrant,omitempty"`
+
+	// oauth2DeviceGrant is the data returned by the OAuth2 device authorization grant flow.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	OAuth2DeviceGrant *BindingResponseAuthenticationOAuth2DeviceGrant `json:"oauth2DeviceGrant,omitempty"`
+
+	// token is the data returned by the bind token exchange.
+	//
+	// +optional
+	// +kubebuilder:validation:Optional
+	Token *BindingResponseAuthenticationToken `json:"token,omitempty"`
 }
 
 // BindingResponseAuthenticationOAuth2CodeGrant contains the authentication data which is passed back to
@@ -72,3 +84,26 @@ type BindingResponseAuthenticationOAuth2CodeGrant struct {
 	// id is the ID of the authenticated user. It is for informational purposes only.
 	ID string `json:"id"`
 }
+
+// BindingResponseAuthenticationOAuth2DeviceGrant contains the authentication data of
+// the OAuth2 device authorization grant flow which is passed back to the consumer as
+// BindingResponse.Authentication.
+type BindingResponseAuthenticationOAuth2DeviceGrant struct {
+	// sessionID is the session ID that was originally passed from the consumer to
+	// the service provider. It must be checked to equal the original value.
+	SessionID string `json:"sid"`
+
+	// id is the ID of the authenticated user. It is for informational purposes only.
+	ID string `json:"id"`
+}
+
+// BindingResponseAuthenticationToken contains the authentication data of the bind
+// token exchange which is passed back to the consumer as BindingResponse.Authentication.
+type BindingResponseAuthenticationToken struct {
+	// sessionID is the session ID that was originally passed from the consumer to
+	// the service provider. It must be checked to equal the original value.
+	SessionID string `json:"sid"`
+
+	// id is the name of the BindToken. It is for informational purposes only.
+	ID string `json:"id"`
+}
@@ -0,0 +1,106 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	ResourceKindBindToken = "BindToken"
+	ResourceBindToken     = "bindtoken"
+	ResourceBindTokens    = "bindtokens"
+)
+
+// BindToken records a token minted by the service provider backend to bind
+// without interactive login, e.g. from a GitOps-managed consumer cluster. It
+// lives in the service provider cluster. The token itself is only handed out
+// once on creation, and has the form "<name>.<secret>".
+//
+// +crd
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:scope=Cluster,categories=kube-bindings
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Subject",type="string",JSONPath=`.spec.subject`,priority=0
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=`.spec.expirationTime`,priority=0
+// +kubebuilder:printcolumn:name="Last Used",type="date",JSONPath=`.status.lastUsedTime`,priority=0
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`,priority=0
+type BindToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// spec specifies what the token allows to bind.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	Spec BindTokenSpec `json:"spec"`
+
+	// status contains information about the usage of the token.
+	Status BindTokenStatus `json:"status,omitempty"`
+}
+
+type BindTokenSpec struct {
+	// subject identifies the consumer binding with the token, in place of the
+	// user authenticated in the interactive flow.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="subject is immutable"
+	Subject string `json:"subject"`
+
+	// resources are the resources bound with the token.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="resources are immutable"
+	Resources []GroupResource `json:"resources"`
+
+	// expirationTime is the time after which the token is rejected.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	ExpirationTime metav1.Time `json:"expirationTime"`
+
+	// tokenHash is the hex encoded SHA-256 hash of the secret part of the token.
+	//
+	// +required
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="tokenHash is immutable"
+	TokenHash string `json:"tokenHash"`
+}
+
+type BindTokenStatus struct {
+	// lastUsedTime is the last time the token was exchanged for a BindingResponse.
+	//
+	// +optional
+	LastUsedTime *metav1.Time `json:"lastUsedTime,omitempty"`
+}
+
+// BindTokenList is a list of BindTokens.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type BindTokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []BindToken `json:"items"`
+}
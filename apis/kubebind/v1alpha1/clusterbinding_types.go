@@ -37,6 +37,15 @@ const (
 
 	// ClusterBindingConditionHealthy is set when the cluster binding is healthy.
 	ClusterBindingConditionHealthy = "Healthy"
+
+	// ClusterBindingConditionProviderReachable is set when the konnector can reach
+	// the API server of the service provider cluster.
+	ClusterBindingConditionProviderReachable = "ProviderReachable"
+
+	// ClusterBindingConditionKubeconfigRenewable is set when the service provider
+	// still renews the credentials in the kubeconfig secret, i.e. the renewal
+	// deadline has not passed.
+	ClusterBindingConditionKubeconfigRenewable = "KubeconfigRenewable"
 )
 
 // ClusterBinding represents a bound consumer class. It lives in a service provider cluster
@@ -117,6 +126,13 @@ type ClusterBindingSpec struct {
 	// binding request. The service providers decide what they need and what to configure based on what then include in
 	// this field, such as service region, type, tiers, etc...
 	ServiceProviderSpec runtime.RawExtension `json:"serviceProviderSpec,omitempty"`
+
+	// kubeconfigRenewalDeadline is the time after which the service provider
+	// does not renew the credentials anymore, and the consumer has to bind
+	// again. It is set by the service provider when binding, and bounds how
+	// long a leaked kubeconfig can keep renewing itself. It is part of the
+	// spec because the konnector may only update the status.
+	KubeconfigRenewalDeadline *metav1.Time `json:"kubeconfigRenewalDeadline,omitempty"`
 }
 
 // ClusterBindingStatus stores status information about a service binding. It is
@@ -138,6 +154,20 @@ type ClusterBindingStatus struct {
 	// consumer cluster.
 	KonnectorVersion string `json:"konnectorVersion,omitempty"`
 
+	// kubeconfigExpirationTime is the time the credentials in the kubeconfig
+	// secret expire. It is set by the service provider and unset for
+	// non-expiring credentials.
+	KubeconfigExpirationTime *metav1.Time `json:"kubeconfigExpirationTime,omitempty"`
+
+	// kubeconfigRenewalTime is the time from which on the konnector should
+	// request new credentials. It is set by the service provider.
+	KubeconfigRenewalTime *metav1.Time `json:"kubeconfigRenewalTime,omitempty"`
+
+	// kubeconfigRenewalRequestTime is set by the konnector to request new
+	// credentials. The service provider issues new credentials and updates the
+	// kubeconfig secret if it is not before kubeconfigRenewalTime.
+	KubeconfigRenewalRequestTime *metav1.Time `json:"kubeconfigRenewalRequestTime,omitempty"`
+
 	// conditions is a list of conditions that apply to the ClusterBinding. It is
 	// updated by the konnector and the service provider.
 	Conditions conditionsapi.Conditions `json:"conditions,omitempty"`
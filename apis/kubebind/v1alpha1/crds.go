@@ -41,3 +41,11 @@ func (_ APIServiceNamespace) CustomResourceDefinition() *apiextensions.CustomRes
 func (_ ClusterBinding) CustomResourceDefinition() *apiextensions.CustomResourceDefinition {
 	return crds.MustCustomResourceDefinition(SchemeGroupVersion.WithResource(ResourceClusterBindings))
 }
+
+func (_ BindToken) CustomResourceDefinition() *apiextensions.CustomResourceDefinition {
+	return crds.MustCustomResourceDefinition(SchemeGroupVersion.WithResource(ResourceBindTokens))
+}
+
+func (_ BindingRequest) CustomResourceDefinition() *apiextensions.CustomResourceDefinition {
+	return crds.MustCustomResourceDefinition(SchemeGroupVersion.WithResource(ResourceBindingRequests))
+}
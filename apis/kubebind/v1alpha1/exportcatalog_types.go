@@ -0,0 +1,69 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// DescriptionAnnotation on an exported CRD describes the resource in the
+	// ExportCatalog. It defaults to the description of the schema of the
+	// storage version.
+	DescriptionAnnotation = "kube-bind.appscode.com/description"
+
+	// ParametersSchemaAnnotation on an exported CRD holds the JSON schema of
+	// the parameters of APIServiceExportRequests for the resource.
+	ParametersSchemaAnnotation = "kube-bind.appscode.com/parameters-schema"
+)
+
+// ExportCatalog is a non-CRUD resource that is returned by the server before
+// authentication. It lists the resources that can be bound.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type ExportCatalog struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// resources are the resources that can be bound, ordered by name.
+	Resources []ExportCatalogResource `json:"resources"`
+}
+
+// ExportCatalogResource is a resource that can be bound.
+type ExportCatalogResource struct {
+	GroupResource `json:",inline"`
+
+	// kind is the kind of the resource.
+	Kind string `json:"kind"`
+
+	// scope is the scope of the resource, either Cluster or Namespaced.
+	Scope apiextensionsv1.ResourceScope `json:"scope"`
+
+	// versions are the served versions of the resource.
+	Versions []string `json:"versions"`
+
+	// description describes the resource for humans.
+	//
+	// +optional
+	Description string `json:"description,omitempty"`
+
+	// parameters is the JSON schema of the parameters of APIServiceExportRequests
+	// for the resource, if it takes any.
+	//
+	// +optional
+	Parameters *apiextensionsv1.JSONSchemaProps `json:"parameters,omitempty"`
+}
@@ -21,17 +21,29 @@ import (
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strings"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime2 "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/yaml"
 )
 
+// ConversionWebhookPathPrefix is the path prefix of the conversion webhook of
+// the konnector. It is followed by the name of the CRD.
+const ConversionWebhookPathPrefix = "/convert/"
+
 // ServiceExportToCRD converts a APIServiceExport to a CRD.
-func ServiceExportToCRD(export *kubebindv1alpha1.APIServiceExport) (*apiextensionsv1.CustomResourceDefinition, error) {
+//
+// With a conversion webhook of the konnector, the CRD serves all exported
+// versions, stores the sync version and converts through the webhook, which
+// delegates to the conversion of the service provider. Without, the CRD
+// carries only the sync version, because a NoneConverter would silently drop
+// fields of writes in other versions.
+func ServiceExportToCRD(export *kubebindv1alpha1.APIServiceExport, conversionWebhook *apiextensionsv1.WebhookClientConfig) (*apiextensionsv1.CustomResourceDefinition, error) {
 	crd := &apiextensionsv1.CustomResourceDefinition{
 		ObjectMeta: metav1.ObjectMeta{
 			Name: export.Name,
@@ -43,13 +55,21 @@ func ServiceExportToCRD(export *kubebindv1alpha1.APIServiceExport) (*apiextensio
 		},
 	}
 
+	syncVersion, err := SyncVersion(&export.Spec.APIServiceExportCRDSpec)
+	if err != nil {
+		return nil, err
+	}
+
 	for i := range export.Spec.Versions {
 		resourceVersion := export.Spec.Versions[i]
+		if !resourceVersion.Served || (conversionWebhook == nil && resourceVersion.Name != syncVersion) {
+			continue
+		}
 
 		crdVersion := apiextensionsv1.CustomResourceDefinitionVersion{
 			Name:                     resourceVersion.Name,
-			Served:                   resourceVersion.Served,
-			Storage:                  resourceVersion.Storage,
+			Served:                   true,
+			Storage:                  resourceVersion.Name == syncVersion,
 			Deprecated:               resourceVersion.Deprecated,
 			DeprecationWarning:       resourceVersion.DeprecationWarning,
 			AdditionalPrinterColumns: resourceVersion.AdditionalPrinterColumns,
@@ -70,9 +90,107 @@ func ServiceExportToCRD(export *kubebindv1alpha1.APIServiceExport) (*apiextensio
 		crd.Spec.Versions = append(crd.Spec.Versions, crdVersion)
 	}
 
+	SetConversionWebhook(crd, conversionWebhook)
+
+	if export.Spec.Conversion != nil {
+		bs, err := json.Marshal(export.Spec.Conversion)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal conversion: %w", err)
+		}
+		crd.Annotations = map[string]string{
+			kubebindv1alpha1.ProviderConversionAnnotation: string(bs),
+		}
+	}
+
 	return crd, nil
 }
 
+// SetConversionWebhook lets the given conversion webhook of the konnector
+// convert between the versions of the CRD, if it has several.
+func SetConversionWebhook(crd *apiextensionsv1.CustomResourceDefinition, conversionWebhook *apiextensionsv1.WebhookClientConfig) {
+	if conversionWebhook == nil || len(crd.Spec.Versions) < 2 {
+		return
+	}
+
+	clientConfig := conversionWebhook.DeepCopy()
+	if clientConfig.Service != nil {
+		clientConfig.Service.Path = ptr.To(ConversionWebhookPathPrefix + crd.Name)
+	} else if clientConfig.URL != nil {
+		clientConfig.URL = ptr.To(strings.TrimSuffix(*clientConfig.URL, "/") + ConversionWebhookPathPrefix + crd.Name)
+	}
+	crd.Spec.Conversion = &apiextensionsv1.CustomResourceConversion{
+		Strategy: apiextensionsv1.WebhookConverter,
+		Webhook: &apiextensionsv1.WebhookConversion{
+			ClientConfig:             clientConfig,
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+}
+
+// ProviderConversion returns the conversion of the service provider recorded
+// on the given consumer CRD, or nil if there is none.
+func ProviderConversion(crd *apiextensionsv1.CustomResourceDefinition) (*apiextensionsv1.CustomResourceConversion, error) {
+	value, found := crd.Annotations[kubebindv1alpha1.ProviderConversionAnnotation]
+	if !found {
+		return nil, nil
+	}
+	var conversion apiextensionsv1.CustomResourceConversion
+	if err := json.Unmarshal([]byte(value), &conversion); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s annotation of CRD %s: %w", kubebindv1alpha1.ProviderConversionAnnotation, crd.Name, err)
+	}
+	return &conversion, nil
+}
+
+// KeepStoredVersions adds the versions of existing that are still listed in
+// its stored versions, but are missing in crd, as non-served versions. The API
+// server rejects removing a stored version, and objects stored in it can still
+// be read through the storage version.
+func KeepStoredVersions(crd, existing *apiextensionsv1.CustomResourceDefinition) {
+	for _, name := range existing.Status.StoredVersions {
+		found := false
+		for _, v := range crd.Spec.Versions {
+			if v.Name == name {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		for _, v := range existing.Spec.Versions {
+			if v.Name != name {
+				continue
+			}
+			v.Served = false
+			v.Storage = false
+			crd.Spec.Versions = append(crd.Spec.Versions, v)
+			break
+		}
+	}
+}
+
+// SyncVersion returns the version objects of the given APIServiceExport are
+// synced through. This is the storage version if it is served, and the first
+// served version otherwise.
+func SyncVersion(spec *kubebindv1alpha1.APIServiceExportCRDSpec) (string, error) {
+	var firstServed string
+	for _, v := range spec.Versions {
+		if !v.Served {
+			continue
+		}
+		if v.Storage {
+			return v.Name, nil
+		}
+		if firstServed == "" {
+			firstServed = v.Name
+		}
+	}
+	if firstServed == "" {
+		return "", fmt.Errorf("no served version found for %s.%s", spec.Names.Plural, spec.Group)
+	}
+	return firstServed, nil
+}
+
 // CRDToServiceExport converts a CRD to a APIServiceExport.
 func CRDToServiceExport(crd *apiextensionsv1.CustomResourceDefinition) (*kubebindv1alpha1.APIServiceExportCRDSpec, error) {
 	spec := &kubebindv1alpha1.APIServiceExportCRDSpec{
@@ -80,8 +198,10 @@ func CRDToServiceExport(crd *apiextensionsv1.CustomResourceDefinition) (*kubebin
 		Names: crd.Spec.Names,
 		Scope: crd.Spec.Scope,
 	}
+	if crd.Spec.Scope == apiextensionsv1.NamespaceScoped && crd.Annotations[kubebindv1alpha1.ConsumerScopeAnnotation] == string(apiextensionsv1.ClusterScoped) {
+		spec.Scope = apiextensionsv1.ClusterScoped
+	}
 
-	onlyFirstServingVersion := crd.Spec.Conversion != nil && crd.Spec.Conversion.Strategy == apiextensionsv1.WebhookConverter
 	// TODO: come up with an API to select versions
 	for i := range crd.Spec.Versions {
 		crdVersion := crd.Spec.Versions[i]
@@ -113,16 +233,36 @@ func CRDToServiceExport(crd *apiextensionsv1.CustomResourceDefinition) (*kubebin
 		}
 
 		spec.Versions = append(spec.Versions, apiResourceVersion)
+	}
 
-		if onlyFirstServingVersion {
-			spec.Versions[0].Storage = true
-			break
+	// the konnector delegates the conversion between the versions to the service
+	// provider.
+	spec.Conversion = crd.Spec.Conversion.DeepCopy()
+
+	// the storage version might not be served. Then the first served version is
+	// the one the konnector syncs through.
+	if syncVersion, err := SyncVersion(spec); err == nil {
+		for i := range spec.Versions {
+			spec.Versions[i].Storage = spec.Versions[i].Name == syncVersion
 		}
 	}
 
 	return spec, nil
 }
 
+// ClusterScopedIsolation returns the isolation of the cluster-scoped API
+// exported from the given CRD. Namespaced CRDs can only be isolated in the
+// cluster namespace, and cluster-scoped CRDs cannot be.
+func ClusterScopedIsolation(crd *apiextensionsv1.CustomResourceDefinition, isolation kubebindv1alpha1.Isolation) kubebindv1alpha1.Isolation {
+	if crd.Spec.Scope == apiextensionsv1.NamespaceScoped {
+		return kubebindv1alpha1.IsolationNamespaced
+	}
+	if isolation == kubebindv1alpha1.IsolationNamespaced || isolation == "" {
+		return kubebindv1alpha1.IsolationPrefixed
+	}
+	return isolation
+}
+
 func APIServiceExportCRDSpecHash(obj *kubebindv1alpha1.APIServiceExportCRDSpec) string {
 	bs, err := json.Marshal(obj)
 	if err != nil {
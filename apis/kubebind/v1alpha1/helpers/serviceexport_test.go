@@ -19,8 +19,11 @@ package helpers
 import (
 	"testing"
 
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 )
 
@@ -63,3 +66,143 @@ func TestWebhookCRDStorageVersion(t *testing.T) {
 		t.Fatal("returned ResourceExport has no storage version", output)
 	}
 }
+
+func TestWebhookCRDExportsAllServedVersions(t *testing.T) {
+	input := v1.CustomResourceDefinition{
+		Spec: v1.CustomResourceDefinitionSpec{
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{Served: true, Name: "v1beta1"},
+				{Served: true, Name: "v1", Storage: true},
+				{Served: false, Name: "v1alpha1"},
+			},
+			Conversion: &v1.CustomResourceConversion{
+				Strategy: v1.WebhookConverter,
+			},
+		},
+	}
+
+	output, err := CRDToServiceExport(&input)
+	require.NoError(t, err)
+	require.Len(t, output.Versions, 2)
+	require.Equal(t, input.Spec.Conversion, output.Conversion)
+
+	syncVersion, err := SyncVersion(output)
+	require.NoError(t, err)
+	require.Equal(t, "v1", syncVersion)
+}
+
+func TestServiceExportToCRDExportsSyncVersion(t *testing.T) {
+	export := &kubebindv1alpha1.APIServiceExport{
+		Spec: kubebindv1alpha1.APIServiceExportSpec{
+			APIServiceExportCRDSpec: kubebindv1alpha1.APIServiceExportCRDSpec{
+				Versions: []kubebindv1alpha1.APIServiceExportVersion{
+					{Served: true, Name: "v1beta1"},
+					{Served: true, Name: "v1"},
+					{Served: false, Name: "v1alpha1", Storage: true},
+				},
+			},
+		},
+	}
+
+	crd, err := ServiceExportToCRD(export, nil)
+	require.NoError(t, err)
+	require.Len(t, crd.Spec.Versions, 1)
+	require.Equal(t, "v1beta1", crd.Spec.Versions[0].Name)
+	require.True(t, crd.Spec.Versions[0].Served)
+	require.True(t, crd.Spec.Versions[0].Storage)
+	require.Nil(t, crd.Spec.Conversion)
+
+	existing := &v1.CustomResourceDefinition{
+		Spec: v1.CustomResourceDefinitionSpec{
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{Served: true, Name: "v1beta1"},
+				{Served: true, Name: "v1", Storage: true},
+			},
+		},
+		Status: v1.CustomResourceDefinitionStatus{
+			StoredVersions: []string{"v1", "v1beta1"},
+		},
+	}
+	KeepStoredVersions(crd, existing)
+	require.Len(t, crd.Spec.Versions, 2)
+	require.Equal(t, "v1", crd.Spec.Versions[1].Name)
+	require.False(t, crd.Spec.Versions[1].Served)
+	require.False(t, crd.Spec.Versions[1].Storage)
+}
+
+func TestServiceExportToCRDConvertsThroughWebhook(t *testing.T) {
+	conversion := &v1.CustomResourceConversion{
+		Strategy: v1.WebhookConverter,
+		Webhook: &v1.WebhookConversion{
+			ClientConfig: &v1.WebhookClientConfig{
+				Service: &v1.ServiceReference{Namespace: "mangodb", Name: "conversion"},
+			},
+			ConversionReviewVersions: []string{"v1"},
+		},
+	}
+	export := &kubebindv1alpha1.APIServiceExport{
+		ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com"},
+		Spec: kubebindv1alpha1.APIServiceExportSpec{
+			APIServiceExportCRDSpec: kubebindv1alpha1.APIServiceExportCRDSpec{
+				Versions: []kubebindv1alpha1.APIServiceExportVersion{
+					{Served: true, Name: "v1beta1"},
+					{Served: true, Name: "v1", Storage: true},
+				},
+				Conversion: conversion,
+			},
+		},
+	}
+	webhook := &v1.WebhookClientConfig{
+		Service:  &v1.ServiceReference{Namespace: "ace", Name: "konnector", Port: ptr.To[int32](443)},
+		CABundle: []byte("ca"),
+	}
+
+	crd, err := ServiceExportToCRD(export, webhook)
+	require.NoError(t, err)
+	require.Len(t, crd.Spec.Versions, 2)
+	require.True(t, crd.Spec.Versions[0].Served)
+	require.False(t, crd.Spec.Versions[0].Storage)
+	require.True(t, crd.Spec.Versions[1].Served)
+	require.True(t, crd.Spec.Versions[1].Storage)
+
+	require.Equal(t, v1.WebhookConverter, crd.Spec.Conversion.Strategy)
+	require.Equal(t, "/convert/mangodbs.mangodb.com", *crd.Spec.Conversion.Webhook.ClientConfig.Service.Path)
+	require.Equal(t, []byte("ca"), crd.Spec.Conversion.Webhook.ClientConfig.CABundle)
+	require.Nil(t, webhook.Service.Path, "the given client config is not modified")
+
+	providerConversion, err := ProviderConversion(crd)
+	require.NoError(t, err)
+	require.Equal(t, conversion, providerConversion)
+
+	// a single version needs no conversion
+	export.Spec.Versions = export.Spec.Versions[1:]
+	crd, err = ServiceExportToCRD(export, webhook)
+	require.NoError(t, err)
+	require.Len(t, crd.Spec.Versions, 1)
+	require.Nil(t, crd.Spec.Conversion)
+}
+
+func TestCRDToServiceExportConsumerScope(t *testing.T) {
+	crd := &v1.CustomResourceDefinition{
+		Spec: v1.CustomResourceDefinitionSpec{
+			Scope: v1.NamespaceScoped,
+			Versions: []v1.CustomResourceDefinitionVersion{
+				{Served: true, Name: "v1", Storage: true},
+			},
+		},
+	}
+
+	output, err := CRDToServiceExport(crd)
+	require.NoError(t, err)
+	require.Equal(t, v1.NamespaceScoped, output.Scope)
+
+	crd.ObjectMeta = metav1.ObjectMeta{Annotations: map[string]string{kubebindv1alpha1.ConsumerScopeAnnotation: "Cluster"}}
+	output, err = CRDToServiceExport(crd)
+	require.NoError(t, err)
+	require.Equal(t, v1.ClusterScoped, output.Scope)
+	require.Equal(t, kubebindv1alpha1.IsolationNamespaced, ClusterScopedIsolation(crd, kubebindv1alpha1.IsolationNone))
+
+	crd.Spec.Scope = v1.ClusterScoped
+	require.Equal(t, kubebindv1alpha1.IsolationNone, ClusterScopedIsolation(crd, kubebindv1alpha1.IsolationNone))
+	require.Equal(t, kubebindv1alpha1.IsolationPrefixed, ClusterScopedIsolation(crd, kubebindv1alpha1.IsolationNamespaced))
+}
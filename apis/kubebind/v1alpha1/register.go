@@ -55,9 +55,14 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&APIServiceNamespace{},
 		&APIServiceNamespaceList{},
 		&BindingProvider{},
+		&BindingRequest{},
+		&BindingRequestList{},
+		&BindToken{},
+		&BindTokenList{},
 		&BindingResponse{},
 		&ClusterBinding{},
 		&ClusterBindingList{},
+		&ExportCatalog{},
 	)
 
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
@@ -23,6 +23,7 @@ package v1alpha1
 
 import (
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	v1 "kmodules.xyz/client-go/api/v1"
 )
@@ -96,6 +97,11 @@ func (in *APIServiceBindingSpec) DeepCopyInto(out *APIServiceBindingSpec) {
 		*out = make([]Provider, len(*in))
 		copy(*out, *in)
 	}
+	if in.Sync != nil {
+		in, out := &in.Sync, &out.Sync
+		*out = new(SyncTuning)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -119,6 +125,13 @@ func (in *APIServiceBindingStatus) DeepCopyInto(out *APIServiceBindingStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]ProviderDrift, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -171,6 +184,11 @@ func (in *APIServiceExportCRDSpec) DeepCopyInto(out *APIServiceExportCRDSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Conversion != nil {
+		in, out := &in.Conversion, &out.Conversion
+		*out = new(apiextensionsv1.CustomResourceConversion)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -400,6 +418,21 @@ func (in *APIServiceExportSchema) DeepCopy() *APIServiceExportSchema {
 func (in *APIServiceExportSpec) DeepCopyInto(out *APIServiceExportSpec) {
 	*out = *in
 	in.APIServiceExportCRDSpec.DeepCopyInto(&out.APIServiceExportCRDSpec)
+	if in.FieldSync != nil {
+		in, out := &in.FieldSync, &out.FieldSync
+		*out = make([]FieldSyncRule, len(*in))
+		copy(*out, *in)
+	}
+	if in.MetadataSync != nil {
+		in, out := &in.MetadataSync, &out.MetadataSync
+		*out = new(MetadataSync)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RelatedResources != nil {
+		in, out := &in.RelatedResources, &out.RelatedResources
+		*out = make([]RelatedResource, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -571,6 +604,16 @@ func (in *AuthenticationMethod) DeepCopyInto(out *AuthenticationMethod) {
 		*out = new(OAuth2CodeGrant)
 		**out = **in
 	}
+	if in.OAuth2DeviceGrant != nil {
+		in, out := &in.OAuth2DeviceGrant, &out.OAuth2DeviceGrant
+		*out = new(OAuth2DeviceGrant)
+		**out = **in
+	}
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(TokenExchange)
+		**out = **in
+	}
 	return
 }
 
@@ -584,6 +627,109 @@ func (in *AuthenticationMethod) DeepCopy() *AuthenticationMethod {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindToken) DeepCopyInto(out *BindToken) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindToken.
+func (in *BindToken) DeepCopy() *BindToken {
+	if in == nil {
+		return nil
+	}
+	out := new(BindToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BindToken) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindTokenList) DeepCopyInto(out *BindTokenList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BindToken, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindTokenList.
+func (in *BindTokenList) DeepCopy() *BindTokenList {
+	if in == nil {
+		return nil
+	}
+	out := new(BindTokenList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BindTokenList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindTokenSpec) DeepCopyInto(out *BindTokenSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]GroupResource, len(*in))
+		copy(*out, *in)
+	}
+	in.ExpirationTime.DeepCopyInto(&out.ExpirationTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindTokenSpec.
+func (in *BindTokenSpec) DeepCopy() *BindTokenSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BindTokenSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindTokenStatus) DeepCopyInto(out *BindTokenStatus) {
+	*out = *in
+	if in.LastUsedTime != nil {
+		in, out := &in.LastUsedTime, &out.LastUsedTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindTokenStatus.
+func (in *BindTokenStatus) DeepCopy() *BindTokenStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BindTokenStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BindingProvider) DeepCopyInto(out *BindingProvider) {
 	*out = *in
@@ -616,6 +762,139 @@ func (in *BindingProvider) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingRequest) DeepCopyInto(out *BindingRequest) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingRequest.
+func (in *BindingRequest) DeepCopy() *BindingRequest {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingRequest)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BindingRequest) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingRequestCredentials) DeepCopyInto(out *BindingRequestCredentials) {
+	*out = *in
+	out.TokenSecretRef = in.TokenSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingRequestCredentials.
+func (in *BindingRequestCredentials) DeepCopy() *BindingRequestCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingRequestCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingRequestList) DeepCopyInto(out *BindingRequestList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]BindingRequest, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingRequestList.
+func (in *BindingRequestList) DeepCopy() *BindingRequestList {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingRequestList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *BindingRequestList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingRequestSpec) DeepCopyInto(out *BindingRequestSpec) {
+	*out = *in
+	out.Credentials = in.Credentials
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]GroupResource, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingRequestSpec.
+func (in *BindingRequestSpec) DeepCopy() *BindingRequestSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingRequestSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingRequestStatus) DeepCopyInto(out *BindingRequestStatus) {
+	*out = *in
+	if in.ExportRequests != nil {
+		in, out := &in.ExportRequests, &out.ExportRequests
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bindings != nil {
+		in, out := &in.Bindings, &out.Bindings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make(v1.Conditions, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingRequestStatus.
+func (in *BindingRequestStatus) DeepCopy() *BindingRequestStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingRequestStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BindingResponse) DeepCopyInto(out *BindingResponse) {
 	*out = *in
@@ -662,6 +941,16 @@ func (in *BindingResponseAuthentication) DeepCopyInto(out *BindingResponseAuthen
 		*out = new(BindingResponseAuthenticationOAuth2CodeGrant)
 		**out = **in
 	}
+	if in.OAuth2DeviceGrant != nil {
+		in, out := &in.OAuth2DeviceGrant, &out.OAuth2DeviceGrant
+		*out = new(BindingResponseAuthenticationOAuth2DeviceGrant)
+		**out = **in
+	}
+	if in.Token != nil {
+		in, out := &in.Token, &out.Token
+		*out = new(BindingResponseAuthenticationToken)
+		**out = **in
+	}
 	return
 }
 
@@ -691,6 +980,38 @@ func (in *BindingResponseAuthenticationOAuth2CodeGrant) DeepCopy() *BindingRespo
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingResponseAuthenticationOAuth2DeviceGrant) DeepCopyInto(out *BindingResponseAuthenticationOAuth2DeviceGrant) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingResponseAuthenticationOAuth2DeviceGrant.
+func (in *BindingResponseAuthenticationOAuth2DeviceGrant) DeepCopy() *BindingResponseAuthenticationOAuth2DeviceGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingResponseAuthenticationOAuth2DeviceGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BindingResponseAuthenticationToken) DeepCopyInto(out *BindingResponseAuthenticationToken) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BindingResponseAuthenticationToken.
+func (in *BindingResponseAuthenticationToken) DeepCopy() *BindingResponseAuthenticationToken {
+	if in == nil {
+		return nil
+	}
+	out := new(BindingResponseAuthenticationToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterBinding) DeepCopyInto(out *ClusterBinding) {
 	*out = *in
@@ -757,6 +1078,10 @@ func (in *ClusterBindingSpec) DeepCopyInto(out *ClusterBindingSpec) {
 	*out = *in
 	out.KubeconfigSecretRef = in.KubeconfigSecretRef
 	in.ServiceProviderSpec.DeepCopyInto(&out.ServiceProviderSpec)
+	if in.KubeconfigRenewalDeadline != nil {
+		in, out := &in.KubeconfigRenewalDeadline, &out.KubeconfigRenewalDeadline
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -780,6 +1105,18 @@ func (in *ClusterBindingStatus) DeepCopyInto(out *ClusterBindingStatus) {
 	}
 	in.LastHeartbeatTime.DeepCopyInto(&out.LastHeartbeatTime)
 	out.HeartbeatInterval = in.HeartbeatInterval
+	if in.KubeconfigExpirationTime != nil {
+		in, out := &in.KubeconfigExpirationTime, &out.KubeconfigExpirationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.KubeconfigRenewalTime != nil {
+		in, out := &in.KubeconfigRenewalTime, &out.KubeconfigRenewalTime
+		*out = (*in).DeepCopy()
+	}
+	if in.KubeconfigRenewalRequestTime != nil {
+		in, out := &in.KubeconfigRenewalRequestTime, &out.KubeconfigRenewalRequestTime
+		*out = (*in).DeepCopy()
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(v1.Conditions, len(*in))
@@ -833,6 +1170,102 @@ func (in *ClusterSecretKeyRef) DeepCopy() *ClusterSecretKeyRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DriftedObjects) DeepCopyInto(out *DriftedObjects) {
+	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DriftedObjects.
+func (in *DriftedObjects) DeepCopy() *DriftedObjects {
+	if in == nil {
+		return nil
+	}
+	out := new(DriftedObjects)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportCatalog) DeepCopyInto(out *ExportCatalog) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]ExportCatalogResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportCatalog.
+func (in *ExportCatalog) DeepCopy() *ExportCatalog {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportCatalog)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ExportCatalog) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExportCatalogResource) DeepCopyInto(out *ExportCatalogResource) {
+	*out = *in
+	out.GroupResource = in.GroupResource
+	if in.Versions != nil {
+		in, out := &in.Versions, &out.Versions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = new(apiextensionsv1.JSONSchemaProps)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExportCatalogResource.
+func (in *ExportCatalogResource) DeepCopy() *ExportCatalogResource {
+	if in == nil {
+		return nil
+	}
+	out := new(ExportCatalogResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldSyncRule) DeepCopyInto(out *FieldSyncRule) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldSyncRule.
+func (in *FieldSyncRule) DeepCopy() *FieldSyncRule {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldSyncRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GroupResource) DeepCopyInto(out *GroupResource) {
 	*out = *in
@@ -865,6 +1298,58 @@ func (in *LocalSecretKeyRef) DeepCopy() *LocalSecretKeyRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataFilter) DeepCopyInto(out *MetadataFilter) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = new(PrefixFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = new(PrefixFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataFilter.
+func (in *MetadataFilter) DeepCopy() *MetadataFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MetadataSync) DeepCopyInto(out *MetadataSync) {
+	*out = *in
+	if in.ToProvider != nil {
+		in, out := &in.ToProvider, &out.ToProvider
+		*out = new(MetadataFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ToConsumer != nil {
+		in, out := &in.ToConsumer, &out.ToConsumer
+		*out = new(MetadataFilter)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MetadataSync.
+func (in *MetadataSync) DeepCopy() *MetadataSync {
+	if in == nil {
+		return nil
+	}
+	out := new(MetadataSync)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NameObjectMeta) DeepCopyInto(out *NameObjectMeta) {
 	*out = *in
@@ -897,6 +1382,48 @@ func (in *OAuth2CodeGrant) DeepCopy() *OAuth2CodeGrant {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OAuth2DeviceGrant) DeepCopyInto(out *OAuth2DeviceGrant) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OAuth2DeviceGrant.
+func (in *OAuth2DeviceGrant) DeepCopy() *OAuth2DeviceGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(OAuth2DeviceGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrefixFilter) DeepCopyInto(out *PrefixFilter) {
+	*out = *in
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrefixFilter.
+func (in *PrefixFilter) DeepCopy() *PrefixFilter {
+	if in == nil {
+		return nil
+	}
+	out := new(PrefixFilter)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Provider) DeepCopyInto(out *Provider) {
 	*out = *in
@@ -914,3 +1441,98 @@ func (in *Provider) DeepCopy() *Provider {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProviderDrift) DeepCopyInto(out *ProviderDrift) {
+	*out = *in
+	in.LastCheckTime.DeepCopyInto(&out.LastCheckTime)
+	in.MissingUpstream.DeepCopyInto(&out.MissingUpstream)
+	in.MissingDownstream.DeepCopyInto(&out.MissingDownstream)
+	in.SpecMismatch.DeepCopyInto(&out.SpecMismatch)
+	in.StaleFinalizers.DeepCopyInto(&out.StaleFinalizers)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProviderDrift.
+func (in *ProviderDrift) DeepCopy() *ProviderDrift {
+	if in == nil {
+		return nil
+	}
+	out := new(ProviderDrift)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RelatedResource) DeepCopyInto(out *RelatedResource) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RelatedResource.
+func (in *RelatedResource) DeepCopy() *RelatedResource {
+	if in == nil {
+		return nil
+	}
+	out := new(RelatedResource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SyncTuning) DeepCopyInto(out *SyncTuning) {
+	*out = *in
+	if in.RetryBaseDelay != nil {
+		in, out := &in.RetryBaseDelay, &out.RetryBaseDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RetryMaxDelay != nil {
+		in, out := &in.RetryMaxDelay, &out.RetryMaxDelay
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SyncTuning.
+func (in *SyncTuning) DeepCopy() *SyncTuning {
+	if in == nil {
+		return nil
+	}
+	out := new(SyncTuning)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenExchange) DeepCopyInto(out *TokenExchange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenExchange.
+func (in *TokenExchange) DeepCopy() *TokenExchange {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenExchange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenSecretKeyRef) DeepCopyInto(out *TokenSecretKeyRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenSecretKeyRef.
+func (in *TokenSecretKeyRef) DeepCopy() *TokenSecretKeyRef {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenSecretKeyRef)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -45,6 +45,14 @@ func (c *FakeKubeBindV1alpha1) APIServiceNamespaces(namespace string) v1alpha1.A
 	return &FakeAPIServiceNamespaces{c, namespace}
 }
 
+func (c *FakeKubeBindV1alpha1) BindingRequests() v1alpha1.BindingRequestInterface {
+	return &FakeBindingRequests{c}
+}
+
+func (c *FakeKubeBindV1alpha1) BindTokens() v1alpha1.BindTokenInterface {
+	return &FakeBindTokens{c}
+}
+
 func (c *FakeKubeBindV1alpha1) ClusterBindings(namespace string) v1alpha1.ClusterBindingInterface {
 	return &FakeClusterBindings{c, namespace}
 }
@@ -26,4 +26,8 @@ type APIServiceExportRequestExpansion interface{}
 
 type APIServiceNamespaceExpansion interface{}
 
+type BindingRequestExpansion interface{}
+
+type BindTokenExpansion interface{}
+
 type ClusterBindingExpansion interface{}
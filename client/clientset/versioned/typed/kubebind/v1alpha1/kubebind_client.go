@@ -33,6 +33,8 @@ type KubeBindV1alpha1Interface interface {
 	APIServiceExportsGetter
 	APIServiceExportRequestsGetter
 	APIServiceNamespacesGetter
+	BindingRequestsGetter
+	BindTokensGetter
 	ClusterBindingsGetter
 }
 
@@ -57,6 +59,14 @@ func (c *KubeBindV1alpha1Client) APIServiceNamespaces(namespace string) APIServi
 	return newAPIServiceNamespaces(c, namespace)
 }
 
+func (c *KubeBindV1alpha1Client) BindingRequests() BindingRequestInterface {
+	return newBindingRequests(c)
+}
+
+func (c *KubeBindV1alpha1Client) BindTokens() BindTokenInterface {
+	return newBindTokens(c)
+}
+
 func (c *KubeBindV1alpha1Client) ClusterBindings(namespace string) ClusterBindingInterface {
 	return newClusterBindings(c, namespace)
 }
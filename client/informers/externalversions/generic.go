@@ -62,6 +62,10 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.KubeBind().V1alpha1().APIServiceExportRequests().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("apiservicenamespaces"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.KubeBind().V1alpha1().APIServiceNamespaces().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("bindingrequests"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.KubeBind().V1alpha1().BindingRequests().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("bindtokens"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.KubeBind().V1alpha1().BindTokens().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("clusterbindings"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.KubeBind().V1alpha1().ClusterBindings().Informer()}, nil
 
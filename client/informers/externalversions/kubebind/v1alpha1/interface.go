@@ -32,6 +32,10 @@ type Interface interface {
 	APIServiceExportRequests() APIServiceExportRequestInformer
 	// APIServiceNamespaces returns a APIServiceNamespaceInformer.
 	APIServiceNamespaces() APIServiceNamespaceInformer
+	// BindingRequests returns a BindingRequestInformer.
+	BindingRequests() BindingRequestInformer
+	// BindTokens returns a BindTokenInformer.
+	BindTokens() BindTokenInformer
 	// ClusterBindings returns a ClusterBindingInformer.
 	ClusterBindings() ClusterBindingInformer
 }
@@ -67,6 +71,16 @@ func (v *version) APIServiceNamespaces() APIServiceNamespaceInformer {
 	return &aPIServiceNamespaceInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// BindingRequests returns a BindingRequestInformer.
+func (v *version) BindingRequests() BindingRequestInformer {
+	return &bindingRequestInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// BindTokens returns a BindTokenInformer.
+func (v *version) BindTokens() BindTokenInformer {
+	return &bindTokenInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // ClusterBindings returns a ClusterBindingInformer.
 func (v *version) ClusterBindings() ClusterBindingInformer {
 	return &clusterBindingInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -46,6 +46,14 @@ type APIServiceNamespaceListerExpansion interface{}
 // APIServiceNamespaceNamespaceLister.
 type APIServiceNamespaceNamespaceListerExpansion interface{}
 
+// BindingRequestListerExpansion allows custom methods to be added to
+// BindingRequestLister.
+type BindingRequestListerExpansion interface{}
+
+// BindTokenListerExpansion allows custom methods to be added to
+// BindTokenLister.
+type BindTokenListerExpansion interface{}
+
 // ClusterBindingListerExpansion allows custom methods to be added to
 // ClusterBindingLister.
 type ClusterBindingListerExpansion interface{}
@@ -58,7 +58,7 @@ func New(ctx context.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			server, err := konnector.NewServer(config)
+			server, err := konnector.NewServer(ctx, config)
 			if err != nil {
 				return err
 			}
@@ -66,11 +66,27 @@ func New(ctx context.Context) *cobra.Command {
 			if err != nil {
 				return err
 			}
+			if err := prepared.OptionallyStartMetricsServer(ctx); err != nil {
+				return err
+			}
+			if err := prepared.OptionallyStartHealthProbeServer(ctx); err != nil {
+				return err
+			}
 			prepared.OptionallyStartInformers(ctx)
+			if err := prepared.OptionallyStartConversionWebhookServer(ctx); err != nil {
+				return err
+			}
+
+			if config.Sharder != nil {
+				// every replica works on the APIServiceBindings of its shards
+				logger.Info("starting konnector controller in sharded mode", "shards", options.Shards)
+				go config.Sharder.Run(ctx)
+				return prepared.Run(ctx)
+			}
 
 			logger.Info("trying to acquire the lock")
 			lock := NewLock(config.KubeClient, options.LeaseLockNamespace, options.LeaseLockName, options.LeaseLockIdentity)
-			runLeaderElection(ctx, lock, options.LeaseLockIdentity, func(ctx context.Context) {
+			runLeaderElection(ctx, lock, options.LeaseLockIdentity, prepared.LeaderElection, func(ctx context.Context) {
 				logger.Info("starting konnector controller")
 				err = prepared.Run(ctx)
 			})
@@ -40,7 +40,7 @@ func NewLock(client kubeclient.Interface, namespace, lockName, podName string) *
 	}
 }
 
-func runLeaderElection(ctx context.Context, lock *resourcelock.LeaseLock, id string, run func(ctx context.Context)) {
+func runLeaderElection(ctx context.Context, lock *resourcelock.LeaseLock, id string, watchDog *leaderelection.HealthzAdaptor, run func(ctx context.Context)) {
 	logger := klog.FromContext(ctx)
 
 	leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
@@ -49,6 +49,7 @@ func runLeaderElection(ctx context.Context, lock *resourcelock.LeaseLock, id str
 		LeaseDuration:   15 * time.Second,
 		RenewDeadline:   10 * time.Second,
 		RetryPeriod:     2 * time.Second,
+		WatchDog:        watchDog,
 		Callbacks: leaderelection.LeaderCallbacks{
 			OnStartedLeading: func(c context.Context) {
 				logger.Info("started leading", "id", id)
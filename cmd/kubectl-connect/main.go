@@ -22,6 +22,7 @@ import (
 
 	apiservicecmd "go.bytebuilders.dev/kube-bind/pkg/kubectl/bind-apiservice/cmd"
 	bindcmd "go.bytebuilders.dev/kube-bind/pkg/kubectl/bind/cmd"
+	driftcmd "go.bytebuilders.dev/kube-bind/pkg/kubectl/drift/cmd"
 
 	"github.com/spf13/pflag"
 	v "gomodules.xyz/x/version"
@@ -43,7 +44,13 @@ func main() {
 		fmt.Fprintf(os.Stderr, "error: %v", err)
 		os.Exit(1)
 	}
+	driftCmd, err := driftcmd.New(genericiooptions.IOStreams{In: os.Stdin, Out: os.Stdout, ErrOut: os.Stderr})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v", err)
+		os.Exit(1)
+	}
 	bindCmd.AddCommand(apiserviceCmd)
+	bindCmd.AddCommand(driftCmd)
 	bindCmd.AddCommand(v.NewCmdVersion())
 
 	if err := bindCmd.Execute(); err != nil {
@@ -35,9 +35,10 @@ type SessionState struct {
 	IDToken      string `msgpack:"it,omitempty"`
 	RefreshToken string `msgpack:"rt,omitempty"`
 
-	RedirectURL string `msgpack:"ru,omitempty"`
-	SessionID   string `msgpack:"si,omitempty"`
-	ClusterID   string `msgpack:"ci,omitempty"`
+	RedirectURL string   `msgpack:"ru,omitempty"`
+	SessionID   string   `msgpack:"si,omitempty"`
+	ClusterID   string   `msgpack:"ci,omitempty"`
+	Resources   []string `msgpack:"r,omitempty"`
 }
 
 func (s *SessionState) Encode() ([]byte, error) {
@@ -0,0 +1,241 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/contrib/example-backend/cookie"
+	"go.bytebuilders.dev/kube-bind/pkg/provider"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/securecookie"
+	"k8s.io/klog/v2"
+)
+
+// authenticator authenticates users through OIDC, keeping the session in a
+// secure cookie.
+type authenticator struct {
+	oidc *OIDCServiceProvider
+
+	oidcAuthorizeURL string
+
+	cookieEncryptionKey []byte
+	cookieSigningKey    []byte
+}
+
+var _ provider.Authenticator = &authenticator{}
+
+// NewAuthenticator returns an authenticator using the OAuth2 code grant flow
+// of the OIDC provider.
+func NewAuthenticator(
+	oidc *OIDCServiceProvider,
+	oidcAuthorizeURL string,
+	cookieSigningKey, cookieEncryptionKey []byte,
+) provider.Authenticator {
+	return &authenticator{
+		oidc:                oidc,
+		oidcAuthorizeURL:    oidcAuthorizeURL,
+		cookieSigningKey:    cookieSigningKey,
+		cookieEncryptionKey: cookieEncryptionKey,
+	}
+}
+
+func (a *authenticator) AuthenticationMethods(r *http.Request) []v1alpha1.AuthenticationMethod {
+	oidcAuthorizeURL := a.oidcAuthorizeURL
+	if oidcAuthorizeURL == "" {
+		oidcAuthorizeURL = fmt.Sprintf("http://%s/authorize", r.Host)
+	}
+
+	return []v1alpha1.AuthenticationMethod{
+		{
+			Method: "OAuth2CodeGrant",
+			OAuth2CodeGrant: &v1alpha1.OAuth2CodeGrant{
+				AuthenticatedURL: oidcAuthorizeURL,
+			},
+		},
+	}
+}
+
+func (a *authenticator) AddRoutes(mux *mux.Router) {
+	mux.HandleFunc("/authorize", a.handleAuthorize).Methods("GET")
+	mux.HandleFunc("/callback", a.handleCallback).Methods("GET")
+}
+
+func (a *authenticator) Session(r *http.Request) (*provider.Session, error) {
+	cookieName := "kube-bind-" + r.URL.Query().Get("s")
+	ck, err := r.Cookie(cookieName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session cookie: %w", err)
+	}
+
+	state := cookie.SessionState{}
+	s := securecookie.New(a.cookieSigningKey, a.cookieEncryptionKey)
+	if err := s.Decode(cookieName, ck.Value, &state); err != nil {
+		return nil, fmt.Errorf("failed to decode session cookie: %w", err)
+	}
+
+	var idToken struct {
+		Subject string `json:"sub"`
+		Issuer  string `json:"iss"`
+	}
+	if err := json.Unmarshal([]byte(state.IDToken), &idToken); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal id token: %w", err)
+	}
+
+	return &provider.Session{
+		SessionID:   state.SessionID,
+		ClusterID:   state.ClusterID,
+		RedirectURL: state.RedirectURL,
+		Subject:     idToken.Subject,
+		Issuer:      idToken.Issuer,
+		Resources:   provider.ParseResources(state.Resources),
+	}, nil
+}
+
+func (a *authenticator) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	scopes := []string{"openid", "profile", "email", "offline_access"}
+	code := &AuthCode{
+		RedirectURL: r.URL.Query().Get("u"),
+		SessionID:   r.URL.Query().Get("s"),
+		ClusterID:   r.URL.Query().Get("c"),
+		Resources:   r.URL.Query()["r"],
+	}
+	if p := r.URL.Query().Get("p"); p != "" && code.RedirectURL == "" {
+		code.RedirectURL = fmt.Sprintf("http://localhost:%s/callback", p)
+	}
+	if code.RedirectURL == "" || code.SessionID == "" || code.ClusterID == "" {
+		logger.Error(errors.New("missing redirect url or session id or cluster id"), "failed to authorize")
+		http.Error(w, "missing redirect_url or session_id", http.StatusBadRequest)
+		return
+	}
+
+	dataCode, err := json.Marshal(code)
+	if err != nil {
+		logger.Info("failed to marshal auth code", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	encoded := base64.URLEncoding.EncodeToString(dataCode)
+	authURL := a.oidc.OIDCProviderConfig(scopes).AuthCodeURL(encoded)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+func parseJWT(p string) ([]byte, error) {
+	parts := strings.Split(p, ".")
+	if len(parts) < 2 {
+		return nil, fmt.Errorf("oidc: malformed jwt, expected 3 parts got %d", len(parts))
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("oidc: malformed jwt payload: %v", err)
+	}
+	return payload, nil
+}
+
+// handleCallback handle the authorization redirect callback from OAuth2 auth flow.
+func (a *authenticator) handleCallback(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	if errMsg := r.Form.Get("error"); errMsg != "" {
+		logger.Info("failed to authorize", "error", errMsg)
+		http.Error(w, errMsg+": "+r.Form.Get("error_description"), http.StatusBadRequest)
+		return
+	}
+	code := r.Form.Get("code")
+	if code == "" {
+		code = r.URL.Query().Get("code")
+	}
+	if code == "" {
+		logger.Info("no code in request", "error", "missing code")
+		http.Error(w, fmt.Sprintf("no code in request: %q", r.Form), http.StatusBadRequest)
+		return
+	}
+
+	state := r.Form.Get("state")
+	if state == "" {
+		state = r.URL.Query().Get("state")
+	}
+	decoded, err := base64.StdEncoding.DecodeString(state)
+	if err != nil {
+		logger.Info("failed to decode state", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	authCode := &AuthCode{}
+	if err := json.Unmarshal(decoded, authCode); err != nil {
+		logger.Info("faile to unmarshal authCode", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// TODO: sign state and verify that it is not faked by the oauth provider
+
+	token, err := a.oidc.OIDCProviderConfig(nil).Exchange(r.Context(), code)
+	if err != nil {
+		logger.Info("failed to exchange token", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	jwtStr, ok := token.Extra("id_token").(string)
+	if !ok {
+		logger.Info("failed to get id_token from token", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	jwt, err := parseJWT(jwtStr)
+	if err != nil {
+		logger.Info("failed to parse jwt", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	sessionCookie := cookie.SessionState{
+		CreatedAt:    time.Now(),
+		ExpiresOn:    token.Expiry,
+		AccessToken:  token.AccessToken,
+		IDToken:      string(jwt),
+		RefreshToken: token.RefreshToken,
+		RedirectURL:  authCode.RedirectURL,
+		SessionID:    authCode.SessionID,
+		ClusterID:    authCode.ClusterID,
+		Resources:    authCode.Resources,
+	}
+
+	cookieName := "kube-bind-" + authCode.SessionID
+	s := securecookie.New(a.cookieSigningKey, a.cookieEncryptionKey)
+	encoded, err := s.Encode(cookieName, sessionCookie)
+	if err != nil {
+		logger.Info("failed to encode secure session cookie", "error", err)
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	http.SetCookie(w, cookie.MakeCookie(r, cookieName, encoded, time.Duration(1)*time.Hour))
+	http.Redirect(w, r, "/resources?s="+authCode.SessionID, http.StatusFound)
+}
@@ -21,6 +21,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 
@@ -48,6 +49,8 @@ type ExtraOptions struct {
 	ExternalCAFile         string
 	ExternalCA             []byte
 	TLSExternalServerName  string
+	TokenExpiration        time.Duration
+	MaxCredentialLifetime  time.Duration
 
 	TestingAutoSelect string
 }
@@ -81,6 +84,8 @@ func NewOptions() *Options {
 			PrettyName:             "Example Backend",
 			ConsumerScope:          string(v1alpha1.NamespacedScope),
 			ClusterScopedIsolation: string(v1alpha1.IsolationPrefixed),
+			TokenExpiration:        time.Hour,
+			MaxCredentialLifetime:  30 * 24 * time.Hour,
 		},
 	}
 }
@@ -95,10 +100,12 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&options.NamespacePrefix, "namespace-prefix", options.NamespacePrefix, "The prefix to use for cluster namespaces")
 	fs.StringVar(&options.PrettyName, "pretty-name", options.PrettyName, "Pretty name for the backend")
 	fs.StringVar(&options.ConsumerScope, "consumer-scope", options.ConsumerScope, "How consumers access the service provider cluster. In Kubernetes, \"namespaced\" allows namespace isolation. In kcp, \"cluster\" allows workspace isolation, and with that allows cluster-scoped resources to bind and it is generally more performant.")
-	fs.StringVar(&options.ClusterScopedIsolation, "cluster-scoped-isolation", options.ClusterScopedIsolation, "How cluster scoped service objects are isolated between multiple consumers on the provider side. Among the choices, \"prefixed\" prepends the name of the cluster namespace to an object's name; \"namespaced\" maps a consumer side object into a namespaced object inside the corresponding cluster namespace; \"none\" is used for the case of a dedicated provider where isolation is not necessary.")
+	fs.StringVar(&options.ClusterScopedIsolation, "cluster-scoped-isolation", options.ClusterScopedIsolation, "How cluster scoped service objects are isolated between multiple consumers on the provider side. Among the choices, \"prefixed\" prepends the name of the cluster namespace to an object's name; \"namespaced\" maps a consumer side object into a namespaced object inside the corresponding cluster namespace, which requires a namespaced CRD with the kube-bind.appscode.com/consumer-scope: Cluster annotation and is always used for those, cluster-scoped CRDs are then prefixed; \"none\" is used for the case of a dedicated provider where isolation is not necessary.")
 	fs.StringVar(&options.ExternalAddress, "external-address", options.ExternalAddress, "The external address for the service provider cluster, including https:// and port. If not specified, service account's hosts are used.")
 	fs.StringVar(&options.ExternalCAFile, "external-ca-file", options.ExternalCAFile, "The external CA file for the service provider cluster. If not specified, service account's CA is used.")
 	fs.StringVar(&options.TLSExternalServerName, "external-server-name", options.TLSExternalServerName, "The external (TLS) server name used by consumers to talk to the service provider cluster. This can be useful to select the right certificate via SNI.")
+	fs.DurationVar(&options.TokenExpiration, "token-expiration", options.TokenExpiration, "The lifetime of the service account tokens in the kubeconfigs handed out to consumers. The konnector requests new tokens before they expire.")
+	fs.DurationVar(&options.MaxCredentialLifetime, "max-credential-lifetime", options.MaxCredentialLifetime, "The time after binding from which on tokens are not renewed anymore, and the consumer has to bind again. This bounds how long a leaked kubeconfig stays usable, as it can request new tokens itself.")
 
 	fs.StringVar(&options.TestingAutoSelect, "testing-auto-select", options.TestingAutoSelect, "<resource>.<group> that is automatically selected on th bind screen for testing")
 	fs.MarkHidden("testing-auto-select") // nolint: errcheck
@@ -171,6 +178,13 @@ func (options *CompletedOptions) Validate() error {
 		return fmt.Errorf("consumer scope must be either %q or %q", v1alpha1.NamespacedScope, v1alpha1.ClusterScope)
 	}
 
+	if options.TokenExpiration < 10*time.Minute {
+		return fmt.Errorf("token expiration must be at least 10m")
+	}
+	if options.MaxCredentialLifetime < options.TokenExpiration {
+		return fmt.Errorf("max credential lifetime must not be shorter than the token expiration")
+	}
+
 	if options.ExternalAddress != "" {
 		if !strings.HasPrefix(options.ExternalAddress, "https://") {
 			return fmt.Errorf("external hostname must start with https://")
@@ -23,13 +23,10 @@ import (
 	"net"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
-	"go.bytebuilders.dev/kube-bind/contrib/example-backend/controllers/clusterbinding"
-	"go.bytebuilders.dev/kube-bind/contrib/example-backend/controllers/serviceexport"
-	"go.bytebuilders.dev/kube-bind/contrib/example-backend/controllers/serviceexportrequest"
-	"go.bytebuilders.dev/kube-bind/contrib/example-backend/controllers/servicenamespace"
 	"go.bytebuilders.dev/kube-bind/contrib/example-backend/deploy"
 	examplehttp "go.bytebuilders.dev/kube-bind/contrib/example-backend/http"
-	examplekube "go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes"
+	"go.bytebuilders.dev/kube-bind/pkg/provider"
+	providerkube "go.bytebuilders.dev/kube-bind/pkg/provider/kubernetes"
 
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/dynamic"
@@ -40,17 +37,10 @@ type Server struct {
 	Config *Config
 
 	OIDC       *examplehttp.OIDCServiceProvider
-	Kubernetes *examplekube.Manager
+	Kubernetes *providerkube.Manager
 	WebServer  *examplehttp.Server
 
-	Controllers
-}
-
-type Controllers struct {
-	ClusterBinding       *clusterbinding.Controller
-	ServiceNamespace     *servicenamespace.Controller
-	ServiceExport        *serviceexport.Controller
-	ServiceExportRequest *serviceexportrequest.Controller
+	*provider.Controllers
 }
 
 func NewServer(config *Config) (*Server, error) {
@@ -78,13 +68,15 @@ func NewServer(config *Config) (*Server, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error setting up OIDC: %w", err)
 	}
-	s.Kubernetes, err = examplekube.NewKubernetesManager(
+	s.Kubernetes, err = providerkube.NewKubernetesManager(
 		config.Options.NamespacePrefix,
 		config.Options.PrettyName,
 		config.ClientConfig,
 		config.Options.ExternalAddress,
 		config.Options.ExternalCA,
 		config.Options.TLSExternalServerName,
+		config.Options.TokenExpiration,
+		config.Options.MaxCredentialLifetime,
 		config.KubeInformers.Core().V1().Namespaces(),
 		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
 	)
@@ -106,17 +98,21 @@ func NewServer(config *Config) (*Server, error) {
 		}
 	}
 
-	handler, err := examplehttp.NewHandler(
-		s.OIDC,
-		config.Options.OIDC.AuthorizeURL,
-		callback,
-		config.Options.PrettyName,
-		config.Options.TestingAutoSelect,
-		signingKey,
-		encryptionKey,
-		v1alpha1.Scope(config.Options.ConsumerScope),
+	handler, err := provider.NewHandler(
+		examplehttp.NewAuthenticator(
+			s.OIDC,
+			config.Options.OIDC.AuthorizeURL,
+			signingKey,
+			encryptionKey,
+		),
+		provider.NewCRDCatalog(
+			v1alpha1.Scope(config.Options.ConsumerScope),
+			config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions().Lister(),
+		),
 		s.Kubernetes,
-		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions().Lister(),
+		provider.NewBindTokens(config.KubeClient, config.BindClient),
+		nil,
+		config.Options.TestingAutoSelect,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("error setting up HTTP Handler: %w", err)
@@ -124,50 +120,18 @@ func NewServer(config *Config) (*Server, error) {
 	handler.AddRoutes(s.WebServer.Router)
 
 	// construct controllers
-	s.ClusterBinding, err = clusterbinding.NewController(
-		config.ClientConfig,
-		v1alpha1.Scope(config.Options.ConsumerScope),
-		config.BindInformers.KubeBind().V1alpha1().ClusterBindings(),
-		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
-		config.KubeInformers.Rbac().V1().ClusterRoles(),
-		config.KubeInformers.Rbac().V1().ClusterRoleBindings(),
-		config.KubeInformers.Rbac().V1().RoleBindings(),
-		config.KubeInformers.Core().V1().Namespaces(),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error setting up ClusterBinding Controller: %v", err)
-	}
-	s.ServiceNamespace, err = servicenamespace.NewController(
-		config.ClientConfig,
-		v1alpha1.Scope(config.Options.ConsumerScope),
-		config.BindInformers.KubeBind().V1alpha1().APIServiceNamespaces(),
-		config.BindInformers.KubeBind().V1alpha1().ClusterBindings(),
-		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
-		config.KubeInformers.Core().V1().Namespaces(),
-		config.KubeInformers.Rbac().V1().Roles(),
-		config.KubeInformers.Rbac().V1().RoleBindings(),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error setting up APIServiceNamespace Controller: %w", err)
-	}
-	s.ServiceExport, err = serviceexport.NewController(
-		config.ClientConfig,
-		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
-		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("error setting up APIServiceExport Controller: %w", err)
-	}
-	s.ServiceExportRequest, err = serviceexportrequest.NewController(
+	s.Controllers, err = provider.NewControllers(
 		config.ClientConfig,
 		v1alpha1.Scope(config.Options.ConsumerScope),
 		v1alpha1.Isolation(config.Options.ClusterScopedIsolation),
-		config.BindInformers.KubeBind().V1alpha1().APIServiceExportRequests(),
-		config.BindInformers.KubeBind().V1alpha1().APIServiceExports(),
-		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
+		config.Options.TokenExpiration,
+		config.Options.MaxCredentialLifetime,
+		config.KubeInformers,
+		config.BindInformers,
+		config.ApiextensionsInformers,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("error setting up ServiceExportRequest Controller: %w", err)
+		return nil, err
 	}
 
 	return s, nil
@@ -207,10 +171,7 @@ func (s *Server) Run(ctx context.Context) error {
 	}
 
 	// start controllers
-	go s.Controllers.ServiceExport.Start(ctx, 1)
-	go s.Controllers.ServiceNamespace.Start(ctx, 1)
-	go s.Controllers.ClusterBinding.Start(ctx, 1)
-	go s.Controllers.ServiceExportRequest.Start(ctx, 1)
+	s.Controllers.Start(ctx)
 
 	go func() {
 		<-ctx.Done()
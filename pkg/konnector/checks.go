@@ -0,0 +1,87 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/healthz"
+
+	"k8s.io/apimachinery/pkg/labels"
+	conditionsapi "kmodules.xyz/client-go/api/v1"
+	"kmodules.xyz/client-go/conditions"
+)
+
+type cacheSyncWaiter interface {
+	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
+}
+
+// informersSyncedCheck fails as long as the consumer informers are not synced.
+func (s *Server) informersSyncedCheck() healthz.Checker {
+	return healthz.NamedCheck("informers-synced", func(_ *http.Request) error {
+		// a closed channel makes WaitForCacheSync return the current state
+		stopCh := make(chan struct{})
+		close(stopCh)
+
+		var unsynced []string
+		for _, factory := range []cacheSyncWaiter{s.Config.KubeInformers, s.Config.BindInformers, s.Config.ApiextensionsInformers} {
+			for typ, synced := range factory.WaitForCacheSync(stopCh) {
+				if !synced {
+					unsynced = append(unsynced, typ.String())
+				}
+			}
+		}
+		if len(unsynced) > 0 {
+			sort.Strings(unsynced)
+			return fmt.Errorf("informers not synced: %s", strings.Join(unsynced, ", "))
+		}
+		return nil
+	})
+}
+
+// providersCheck fails if the provider of any APIServiceBinding cannot be
+// reached, i.e. it is not heartbeating or its informers do not sync.
+func (s *Server) providersCheck() healthz.Checker {
+	return healthz.NamedCheck("providers", func(_ *http.Request) error {
+		bindings, err := s.Config.BindInformers.KubeBind().V1alpha1().APIServiceBindings().Lister().List(labels.Everything())
+		if err != nil {
+			return err
+		}
+
+		var failing []string
+		for _, binding := range bindings {
+			for _, t := range []conditionsapi.ConditionType{
+				kubebindv1alpha1.APIServiceBindingConditionHeartbeating,
+				kubebindv1alpha1.APIServiceBindingConditionInformersSynced,
+			} {
+				if c := conditions.Get(binding, t); c != nil && conditions.IsFalse(binding, t) {
+					failing = append(failing, fmt.Sprintf("APIServiceBinding %s: %s: %s", binding.Name, t, c.Message))
+				}
+			}
+		}
+		if len(failing) > 0 {
+			sort.Strings(failing)
+			return fmt.Errorf("%s", strings.Join(failing, "; "))
+		}
+		return nil
+	})
+}
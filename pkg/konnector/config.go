@@ -19,9 +19,11 @@ package konnector
 import (
 	"time"
 
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/options"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
 
 	apiextensionsclient "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset"
 	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
@@ -32,6 +34,20 @@ import (
 )
 
 type Config struct {
+	MetadataSync *kubebindv1alpha1.MetadataSync
+	SyncTuning   *kubebindv1alpha1.SyncTuning
+
+	MetricsBindAddress     string
+	HealthProbeBindAddress string
+
+	ConversionWebhookBindAddress string
+	ConversionWebhookService     string
+	ConversionWebhookNamespace   string
+
+	// Sharder splits the APIServiceBindings between replicas. It is nil
+	// without sharding.
+	Sharder *sharding.Sharder
+
 	ClientConfig        *rest.Config
 	BindClient          *bindclient.Clientset
 	KubeClient          *kubernetesclient.Clientset
@@ -43,7 +59,16 @@ type Config struct {
 }
 
 func NewConfig(options *options.CompletedOptions) (*Config, error) {
-	config := &Config{}
+	config := &Config{
+		MetadataSync:           options.MetadataSync,
+		SyncTuning:             options.SyncTuning,
+		MetricsBindAddress:     options.MetricsBindAddress,
+		HealthProbeBindAddress: options.HealthProbeBindAddress,
+
+		ConversionWebhookBindAddress: options.ConversionWebhookBindAddress,
+		ConversionWebhookService:     options.ConversionWebhookService,
+		ConversionWebhookNamespace:   options.ConversionWebhookNamespace,
+	}
 
 	// create clients
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
@@ -66,6 +91,10 @@ func NewConfig(options *options.CompletedOptions) (*Config, error) {
 		return nil, err
 	}
 
+	if options.Shards > 0 {
+		config.Sharder = sharding.New(config.KubeClient.CoordinationV1(), options.LeaseLockNamespace, options.LeaseLockName, options.LeaseLockIdentity, options.Shards)
+	}
+
 	// construct informer factories
 	config.KubeInformers = kubeinformers.NewSharedInformerFactory(config.KubeClient, time.Minute*30)
 	config.BindInformers = bindinformers.NewSharedInformerFactory(config.BindClient, time.Minute*30)
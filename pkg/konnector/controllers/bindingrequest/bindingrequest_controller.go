@@ -0,0 +1,398 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindingrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions/kubebind/v1alpha1"
+	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/committer"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
+	"go.bytebuilders.dev/kube-bind/pkg/kubectl/bind/authenticator"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	kubernetesclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	controllerName = "kube-bind-konnector-bindingrequest"
+)
+
+// NewController returns a new controller for BindingRequests. Only the
+// BindingRequests owned by the sharder are reconciled.
+func NewController(
+	consumerConfig *rest.Config,
+	bindingRequestInformer bindinformers.BindingRequestInformer,
+	serviceBindingInformer bindinformers.APIServiceBindingInformer,
+	consumerSecretInformer coreinformers.SecretInformer,
+	namespaceInformer coreinformers.NamespaceInformer,
+	sharder *sharding.Sharder,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+
+	logger := klog.Background().WithValues("controller", controllerName)
+
+	consumerConfig = rest.CopyConfig(consumerConfig)
+	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+
+	consumerBindClient, err := bindclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+	consumerKubeClient, err := kubernetesclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{Timeout: 30 * time.Second}
+
+	c := &controller{
+		queue: queue,
+
+		bindingRequestLister: bindingRequestInformer.Lister(),
+
+		sharder: sharder,
+
+		reconciler: reconciler{
+			getSecret: func(ns, name string) (*corev1.Secret, error) {
+				return consumerSecretInformer.Lister().Secrets(ns).Get(name)
+			},
+			getNamespace: func(name string) (*corev1.Namespace, error) {
+				return namespaceInformer.Lister().Get(name)
+			},
+			getBindingProvider: func(ctx context.Context, url string) (*kubebindv1alpha1.BindingProvider, error) {
+				return getBindingProvider(ctx, httpClient, url)
+			},
+			exchangeToken: func(ctx context.Context, exchange *kubebindv1alpha1.TokenExchange, token, sessionID, clusterID, clusterName, user string, resources []string) (k8sruntime.Object, *schema.GroupVersionKind, error) {
+				return authenticator.NewTokenAuthenticator(exchange, token).Exchange(ctx, sessionID, clusterID, clusterName, user, resources)
+			},
+			ensureKubeconfigSecret: func(ctx context.Context, secret *corev1.Secret) error {
+				existing, err := consumerSecretInformer.Lister().Secrets(secret.Namespace).Get(secret.Name)
+				if err != nil && !errors.IsNotFound(err) {
+					return err
+				} else if errors.IsNotFound(err) {
+					_, err := consumerKubeClient.CoreV1().Secrets(secret.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+					return err
+				}
+				existing = existing.DeepCopy()
+				existing.Data = secret.Data
+				existing.OwnerReferences = secret.OwnerReferences
+				_, err = consumerKubeClient.CoreV1().Secrets(secret.Namespace).Update(ctx, existing, metav1.UpdateOptions{})
+				return err
+			},
+			createServiceExportRequest: func(ctx context.Context, kubeconfig []byte, ns string, request *kubebindv1alpha1.APIServiceExportRequest) (*kubebindv1alpha1.APIServiceExportRequest, error) {
+				client, err := newRemoteBindClient(kubeconfig)
+				if err != nil {
+					return nil, err
+				}
+				if request.Name == "" {
+					request.GenerateName = "export-"
+				}
+				return client.KubeBindV1alpha1().APIServiceExportRequests(ns).Create(ctx, request, metav1.CreateOptions{})
+			},
+			getServiceExportRequest: func(ctx context.Context, kubeconfig []byte, ns, name string) (*kubebindv1alpha1.APIServiceExportRequest, error) {
+				client, err := newRemoteBindClient(kubeconfig)
+				if err != nil {
+					return nil, err
+				}
+				return client.KubeBindV1alpha1().APIServiceExportRequests(ns).Get(ctx, name, metav1.GetOptions{})
+			},
+			deleteServiceExportRequest: func(ctx context.Context, kubeconfig []byte, ns, name string) error {
+				client, err := newRemoteBindClient(kubeconfig)
+				if err != nil {
+					return err
+				}
+				return client.KubeBindV1alpha1().APIServiceExportRequests(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+			getServiceBinding: func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
+				return serviceBindingInformer.Lister().Get(name)
+			},
+			createServiceBinding: func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
+				_, err := consumerBindClient.KubeBindV1alpha1().APIServiceBindings().Create(ctx, binding, metav1.CreateOptions{})
+				return err
+			},
+			updateServiceBinding: func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
+				_, err := consumerBindClient.KubeBindV1alpha1().APIServiceBindings().Update(ctx, binding, metav1.UpdateOptions{})
+				return err
+			},
+			deleteServiceBinding: func(ctx context.Context, name string) error {
+				return consumerBindClient.KubeBindV1alpha1().APIServiceBindings().Delete(ctx, name, metav1.DeleteOptions{})
+			},
+			requeueAfter: func(request *kubebindv1alpha1.BindingRequest, duration time.Duration) {
+				queue.AddAfter(request.Name, duration)
+			},
+		},
+
+		commit: committer.NewCommitter[*kubebindv1alpha1.BindingRequest, *kubebindv1alpha1.BindingRequestSpec, *kubebindv1alpha1.BindingRequestStatus](
+			func(ns string) committer.Patcher[*kubebindv1alpha1.BindingRequest] {
+				return consumerBindClient.KubeBindV1alpha1().BindingRequests()
+			},
+		),
+	}
+
+	_, err = bindingRequestInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueBindingRequest(logger, obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueBindingRequest(logger, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueBindingRequest(logger, obj)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = consumerSecretInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueConsumerSecret(logger, obj)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			c.enqueueConsumerSecret(logger, newObj)
+		},
+		DeleteFunc: func(obj interface{}) {
+			c.enqueueConsumerSecret(logger, obj)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+type (
+	Resource   = committer.Resource[*kubebindv1alpha1.BindingRequestSpec, *kubebindv1alpha1.BindingRequestStatus]
+	CommitFunc = func(context.Context, *Resource, *Resource) error
+)
+
+// controller reconciles BindingRequests by authenticating to the service
+// provider, and creating the APIServiceExportRequests upstream and the
+// APIServiceBindings downstream.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	bindingRequestLister bindlisters.BindingRequestLister
+
+	sharder *sharding.Sharder
+
+	reconciler
+
+	commit CommitFunc
+}
+
+func (c *controller) enqueueBindingRequest(logger klog.Logger, obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	logger.V(2).Info("queueing BindingRequest", "key", key)
+	c.queue.Add(key)
+}
+
+func (c *controller) enqueueConsumerSecret(logger klog.Logger, obj interface{}) {
+	secretKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	ns, name, err := cache.SplitMetaNamespaceKey(secretKey)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	if ns != models.KonnectorNamespace {
+		return // credentials and kubeconfigs only live in the konnector namespace
+	}
+
+	requests, err := c.bindingRequestLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, request := range requests {
+		if request.Spec.Credentials.TokenSecretRef.Name != name && request.Status.KubeconfigSecretName != name {
+			continue
+		}
+		logger.V(2).Info("queueing BindingRequest", "key", request.Name, "reason", "Secret", "SecretKey", secretKey)
+		c.queue.Add(request.Name)
+	}
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	c.sharder.Subscribe(ctx, func() {
+		requests, err := c.bindingRequestLister.List(labels.Everything())
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		for _, request := range requests {
+			c.enqueueBindingRequest(logger, request)
+		}
+	})
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(2).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	_, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil // we cannot do anything
+	}
+
+	logger := klog.FromContext(ctx)
+
+	obj, err := c.bindingRequestLister.Get(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		logger.V(2).Info("BindingRequest disappeared")
+		return nil // the kubeconfig secret and bindings are garbage collected
+	}
+	if !c.sharder.Owns(sharding.SecretKey(models.KonnectorNamespace, kubeconfigSecretName(obj))) {
+		return nil // another replica handles it and the bindings using its kubeconfig secret
+	}
+
+	old := obj
+	obj = obj.DeepCopy()
+
+	var errs []error
+	if err := c.reconcile(ctx, obj); err != nil {
+		errs = append(errs, err)
+	}
+
+	// Regardless of whether reconcile returned an error or not, always try to patch status if needed. Return the
+	// reconciliation error at the end.
+
+	// If the object being reconciled changed as a result, update it.
+	oldResource := &Resource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
+	newResource := &Resource{ObjectMeta: obj.ObjectMeta, Spec: &obj.Spec, Status: &obj.Status}
+	if err := c.commit(ctx, oldResource, newResource); err != nil {
+		errs = append(errs, err)
+	}
+
+	return utilerrors.NewAggregate(errs)
+}
+
+// getBindingProvider fetches the BindingProvider served at the export URL.
+func getBindingProvider(ctx context.Context, client *http.Client, url string) (*kubebindv1alpha1.BindingProvider, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	blob, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get binding provider: %s", resp.Status)
+	}
+
+	provider := &kubebindv1alpha1.BindingProvider{}
+	if err := json.Unmarshal(blob, provider); err != nil {
+		return nil, err
+	}
+	return provider, nil
+}
+
+func newRemoteBindClient(kubeconfig []byte) (bindclient.Interface, error) {
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	config = rest.AddUserAgent(config, controllerName)
+	return bindclient.NewForConfig(config)
+}
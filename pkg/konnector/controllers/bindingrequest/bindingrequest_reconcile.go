@@ -0,0 +1,589 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindingrequest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/kubectl/base"
+	"go.bytebuilders.dev/kube-bind/pkg/kubectl/bind/plugin"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	conditionsapi "kmodules.xyz/client-go/api/v1"
+	"kmodules.xyz/client-go/conditions"
+)
+
+// pendingRecheckInterval is how often pending APIServiceExportRequests are
+// checked in the service provider cluster.
+const pendingRecheckInterval = 5 * time.Second
+
+type reconciler struct {
+	getSecret          func(ns, name string) (*corev1.Secret, error)
+	getNamespace       func(name string) (*corev1.Namespace, error)
+	getBindingProvider func(ctx context.Context, url string) (*kubebindv1alpha1.BindingProvider, error)
+	exchangeToken      func(ctx context.Context, exchange *kubebindv1alpha1.TokenExchange, token, sessionID, clusterID, clusterName, user string, resources []string) (k8sruntime.Object, *schema.GroupVersionKind, error)
+
+	ensureKubeconfigSecret func(ctx context.Context, secret *corev1.Secret) error
+
+	createServiceExportRequest func(ctx context.Context, kubeconfig []byte, ns string, request *kubebindv1alpha1.APIServiceExportRequest) (*kubebindv1alpha1.APIServiceExportRequest, error)
+	getServiceExportRequest    func(ctx context.Context, kubeconfig []byte, ns, name string) (*kubebindv1alpha1.APIServiceExportRequest, error)
+	deleteServiceExportRequest func(ctx context.Context, kubeconfig []byte, ns, name string) error
+
+	getServiceBinding    func(name string) (*kubebindv1alpha1.APIServiceBinding, error)
+	createServiceBinding func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error
+	updateServiceBinding func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error
+	deleteServiceBinding func(ctx context.Context, name string) error
+
+	requeueAfter func(request *kubebindv1alpha1.BindingRequest, duration time.Duration)
+}
+
+func (r *reconciler) reconcile(ctx context.Context, request *kubebindv1alpha1.BindingRequest) error {
+	if err := r.ensureAuthenticated(ctx, request); err != nil {
+		conditions.SetSummary(request)
+		return err
+	}
+
+	err := r.ensureServiceBindings(ctx, request)
+
+	conditions.SetSummary(request)
+
+	return err
+}
+
+// ensureAuthenticated exchanges the credentials for a BindingResponse, stores
+// the kubeconfig and creates the APIServiceExportRequests in the service
+// provider cluster. This happens once per generation of the spec, and again
+// if the kubeconfig secret is lost.
+func (r *reconciler) ensureAuthenticated(ctx context.Context, request *kubebindv1alpha1.BindingRequest) error {
+	if request.Status.ObservedGeneration == request.Generation && request.Status.KubeconfigSecretName != "" {
+		_, err := r.getSecret(models.KonnectorNamespace, request.Status.KubeconfigSecretName)
+		if err == nil {
+			return nil
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	exportURL, err := url.Parse(request.Spec.URL)
+	if err != nil {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"InvalidURL",
+			conditionsapi.ConditionSeverityError,
+			"Invalid url %q: %v",
+			request.Spec.URL, err,
+		)
+		return nil
+	}
+	user := exportURL.Query().Get("user")
+	if user == "" {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"InvalidURL",
+			conditionsapi.ConditionSeverityError,
+			"Missing user in url %q.",
+			request.Spec.URL,
+		)
+		return nil
+	}
+	clusterName := exportURL.Query().Get("cluster")
+
+	token, err := r.getToken(request)
+	if err != nil {
+		return err
+	} else if token == "" {
+		return nil // condition set by getToken
+	}
+
+	provider, err := r.getBindingProvider(ctx, request.Spec.URL)
+	if err != nil {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"BindingProviderUnavailable",
+			conditionsapi.ConditionSeverityWarning,
+			"Failed to get binding provider: %v",
+			err,
+		)
+		return err
+	}
+	if provider.APIVersion != kubebindv1alpha1.GroupVersion {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"UnsupportedBindingProvider",
+			conditionsapi.ConditionSeverityError,
+			"Unsupported binding provider version %q.",
+			provider.APIVersion,
+		)
+		return nil
+	}
+	var exchange *kubebindv1alpha1.TokenExchange
+	for _, m := range provider.AuthenticationMethods {
+		if m.Method == "Token" && m.Token != nil {
+			exchange = m.Token
+			break
+		}
+	}
+	if exchange == nil {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"UnsupportedBindingProvider",
+			conditionsapi.ConditionSeverityError,
+			"The service provider does not support bind tokens.",
+		)
+		return nil
+	}
+
+	ns, err := r.getNamespace(models.KonnectorNamespace)
+	if err != nil {
+		return err
+	}
+	var resources []string
+	for _, resource := range request.Spec.Resources {
+		resources = append(resources, resource.Resource+"."+resource.Group)
+	}
+	sessionID := plugin.SessionID()
+	obj, gvk, err := r.exchangeToken(ctx, exchange, token, sessionID, plugin.ClusterID(ns), clusterName, user, resources)
+	if err != nil {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"AuthenticationFailed",
+			conditionsapi.ConditionSeverityError,
+			"Failed to authenticate: %v",
+			err,
+		)
+		return err
+	}
+	response, err := parseBindingResponse(obj, gvk, sessionID)
+	if err != nil {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"InvalidBindingResponse",
+			conditionsapi.ConditionSeverityError,
+			"Unexpected response: %v",
+			err,
+		)
+		return nil
+	}
+	_, remoteNamespace, err := base.ParseRemoteKubeconfig(response.Kubeconfig)
+	if err != nil || remoteNamespace == "" {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"InvalidBindingResponse",
+			conditionsapi.ConditionSeverityError,
+			"Unexpected response: invalid kubeconfig: %v",
+			err,
+		)
+		return nil
+	}
+
+	secretName := kubeconfigSecretName(request)
+	if err := r.ensureKubeconfigSecret(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:       models.KonnectorNamespace,
+			Name:            secretName,
+			OwnerReferences: []metav1.OwnerReference{ownerReference(request)},
+		},
+		Data: map[string][]byte{
+			"kubeconfig": response.Kubeconfig,
+		},
+	}); err != nil {
+		return err
+	}
+
+	var names []string
+	for _, apiRequest := range filterRequests(response.requests, request.Spec.Resources) {
+		name, err := r.ensureServiceExportRequest(ctx, response.Kubeconfig, remoteNamespace, &kubebindv1alpha1.APIServiceExportRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: apiRequest.ObjectMeta.Name,
+			},
+			Spec: apiRequest.Spec,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create APIServiceExportRequest: %w", err)
+		}
+		names = append(names, name)
+	}
+
+	request.Status.ObservedGeneration = request.Generation
+	request.Status.KubeconfigSecretName = secretName
+	request.Status.RemoteNamespace = remoteNamespace
+	request.Status.ExportRequests = names
+
+	conditions.MarkTrue(request, kubebindv1alpha1.BindingRequestConditionAuthenticated)
+
+	return nil
+}
+
+// ensureServiceExportRequest creates the APIServiceExportRequest and returns
+// its name. An existing request of the same name, e.g. created by an earlier
+// attempt which failed later on, is reused, unless it failed. Then it is
+// replaced.
+func (r *reconciler) ensureServiceExportRequest(ctx context.Context, kubeconfig []byte, ns string, apiRequest *kubebindv1alpha1.APIServiceExportRequest) (string, error) {
+	created, err := r.createServiceExportRequest(ctx, kubeconfig, ns, apiRequest.DeepCopy())
+	if err == nil {
+		return created.Name, nil
+	} else if !errors.IsAlreadyExists(err) || apiRequest.Name == "" {
+		return "", err
+	}
+
+	existing, err := r.getServiceExportRequest(ctx, kubeconfig, ns, apiRequest.Name)
+	if err != nil {
+		return "", err
+	}
+	if existing.Status.Phase != kubebindv1alpha1.APIServiceExportRequestPhaseFailed {
+		return existing.Name, nil
+	}
+	if err := r.deleteServiceExportRequest(ctx, kubeconfig, ns, existing.Name); err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	created, err = r.createServiceExportRequest(ctx, kubeconfig, ns, apiRequest.DeepCopy())
+	if err != nil {
+		return "", err
+	}
+	return created.Name, nil
+}
+
+// getToken returns the bind token of the request, or an empty string if the
+// secret is missing or invalid.
+func (r *reconciler) getToken(request *kubebindv1alpha1.BindingRequest) (string, error) {
+	ref := request.Spec.Credentials.TokenSecretRef
+	key := ref.Key
+	if key == "" {
+		key = "token"
+	}
+
+	secret, err := r.getSecret(models.KonnectorNamespace, ref.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	} else if errors.IsNotFound(err) {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"TokenSecretNotFound",
+			conditionsapi.ConditionSeverityError,
+			"Token secret %s/%s not found.",
+			models.KonnectorNamespace, ref.Name,
+		)
+		return "", nil
+	}
+
+	token := strings.TrimSpace(string(secret.Data[key]))
+	if token == "" {
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionAuthenticated,
+			"TokenSecretInvalid",
+			conditionsapi.ConditionSeverityError,
+			"Token secret %s/%s is missing %q string key.",
+			models.KonnectorNamespace, ref.Name, key,
+		)
+		return "", nil
+	}
+	return token, nil
+}
+
+// ensureServiceBindings creates the APIServiceBindings of the succeeded
+// APIServiceExportRequests, and rechecks the pending ones later.
+func (r *reconciler) ensureServiceBindings(ctx context.Context, request *kubebindv1alpha1.BindingRequest) error {
+	if request.Status.KubeconfigSecretName == "" {
+		return nil // not authenticated yet
+	}
+
+	if err := r.pruneServiceBindings(ctx, request); err != nil {
+		return err
+	}
+
+	secret, err := r.getSecret(models.KonnectorNamespace, request.Status.KubeconfigSecretName)
+	if err != nil {
+		return err
+	}
+	kubeconfig := secret.Data["kubeconfig"]
+
+	var remaining, pending, failed []string
+	for _, name := range request.Status.ExportRequests {
+		apiRequest, err := r.getServiceExportRequest(ctx, kubeconfig, request.Status.RemoteNamespace, name)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		} else if errors.IsNotFound(err) {
+			failed = append(failed, fmt.Sprintf("APIServiceExportRequest %s was deleted by the service provider", name))
+			continue
+		}
+
+		switch apiRequest.Status.Phase {
+		case kubebindv1alpha1.APIServiceExportRequestPhaseSucceeded:
+			for _, resource := range apiRequest.Spec.Resources {
+				name, err := r.ensureServiceBinding(ctx, request, resource.GroupResource)
+				if err != nil {
+					return err
+				}
+				if !sets.New[string](request.Status.Bindings...).Has(name) {
+					request.Status.Bindings = append(request.Status.Bindings, name)
+				}
+			}
+		case kubebindv1alpha1.APIServiceExportRequestPhaseFailed:
+			remaining = append(remaining, name)
+			failed = append(failed, fmt.Sprintf("APIServiceExportRequest %s failed: %s", name, apiRequest.Status.TerminalMessage))
+		default:
+			remaining = append(remaining, name)
+			pending = append(pending, name)
+		}
+	}
+	request.Status.ExportRequests = remaining
+
+	var missing []string
+	bindings := sets.New[string](request.Status.Bindings...)
+	for _, resource := range request.Spec.Resources {
+		if name := resource.Resource + "." + resource.Group; !bindings.Has(name) {
+			missing = append(missing, name)
+		}
+	}
+
+	switch {
+	case len(failed) > 0:
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionBound,
+			"ExportRequestFailed",
+			conditionsapi.ConditionSeverityError,
+			"%s.",
+			strings.Join(failed, "; "),
+		)
+	case len(pending) > 0:
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionBound,
+			"Pending",
+			conditionsapi.ConditionSeverityInfo,
+			"Waiting for APIServiceExportRequests %s.",
+			strings.Join(pending, ", "),
+		)
+		r.requeueAfter(request, pendingRecheckInterval)
+	case len(missing) > 0:
+		conditions.MarkFalse(
+			request,
+			kubebindv1alpha1.BindingRequestConditionBound,
+			"ResourcesNotOffered",
+			conditionsapi.ConditionSeverityError,
+			"The service provider does not offer %s for the credentials.",
+			strings.Join(missing, ", "),
+		)
+	default:
+		conditions.MarkTrue(request, kubebindv1alpha1.BindingRequestConditionBound)
+	}
+
+	return nil
+}
+
+// ensureServiceBinding creates the APIServiceBinding of the resource, or adds
+// the service provider to an existing one.
+func (r *reconciler) ensureServiceBinding(ctx context.Context, request *kubebindv1alpha1.BindingRequest, resource kubebindv1alpha1.GroupResource) (string, error) {
+	name := resource.Resource + "." + resource.Group
+	provider := kubebindv1alpha1.Provider{
+		Kubeconfig: kubebindv1alpha1.ClusterSecretKeyRef{
+			LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{
+				Name: request.Status.KubeconfigSecretName,
+				Key:  "kubeconfig",
+			},
+			Namespace: models.KonnectorNamespace,
+		},
+		RemoteNamespace: request.Status.RemoteNamespace,
+	}
+
+	existing, err := r.getServiceBinding(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return "", err
+	} else if errors.IsNotFound(err) {
+		return name, r.createServiceBinding(ctx, &kubebindv1alpha1.APIServiceBinding{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            name,
+				OwnerReferences: []metav1.OwnerReference{ownerReference(request)},
+			},
+			Spec: kubebindv1alpha1.APIServiceBindingSpec{
+				Providers: []kubebindv1alpha1.Provider{provider},
+			},
+		})
+	}
+
+	for _, p := range existing.Spec.Providers {
+		if p.Kubeconfig.Namespace == models.KonnectorNamespace && p.Kubeconfig.Name == request.Status.KubeconfigSecretName {
+			return name, nil
+		}
+	}
+	existing = existing.DeepCopy()
+	existing.Spec.Providers = append(existing.Spec.Providers, provider)
+	return name, r.updateServiceBinding(ctx, existing)
+}
+
+// pruneServiceBindings removes the service provider from the APIServiceBindings
+// of resources which were removed from the spec. APIServiceBindings without
+// other service provider are deleted.
+func (r *reconciler) pruneServiceBindings(ctx context.Context, request *kubebindv1alpha1.BindingRequest) error {
+	if len(request.Spec.Resources) == 0 {
+		return nil // all resources offered are bound
+	}
+
+	wanted := sets.New[string]()
+	for _, resource := range request.Spec.Resources {
+		wanted.Insert(resource.Resource + "." + resource.Group)
+	}
+
+	var kept []string
+	for _, name := range request.Status.Bindings {
+		if wanted.Has(name) {
+			kept = append(kept, name)
+			continue
+		}
+		if err := r.removeServiceBinding(ctx, request, name); err != nil {
+			return err
+		}
+	}
+	request.Status.Bindings = kept
+	return nil
+}
+
+// removeServiceBinding removes the service provider and the owner reference of
+// the request from the APIServiceBinding, or deletes it if no other service
+// provider is left.
+func (r *reconciler) removeServiceBinding(ctx context.Context, request *kubebindv1alpha1.BindingRequest, name string) error {
+	existing, err := r.getServiceBinding(name)
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var providers []kubebindv1alpha1.Provider
+	for _, p := range existing.Spec.Providers {
+		if p.Kubeconfig.Namespace != models.KonnectorNamespace || p.Kubeconfig.Name != request.Status.KubeconfigSecretName {
+			providers = append(providers, p)
+		}
+	}
+	if len(providers) == 0 {
+		if err := r.deleteServiceBinding(ctx, name); err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return nil
+	}
+
+	var owners []metav1.OwnerReference
+	for _, ref := range existing.OwnerReferences {
+		if ref.UID != request.UID {
+			owners = append(owners, ref)
+		}
+	}
+	if len(providers) == len(existing.Spec.Providers) && len(owners) == len(existing.OwnerReferences) {
+		return nil
+	}
+	existing = existing.DeepCopy()
+	existing.Spec.Providers = providers
+	existing.OwnerReferences = owners
+	return r.updateServiceBinding(ctx, existing)
+}
+
+type bindingResponse struct {
+	*kubebindv1alpha1.BindingResponse
+	requests []*kubebindv1alpha1.APIServiceExportRequestResponse
+}
+
+// parseBindingResponse verifies the response of the service provider for the
+// given session and extracts the APIServiceExportRequests.
+func parseBindingResponse(obj k8sruntime.Object, gvk *schema.GroupVersionKind, sessionID string) (*bindingResponse, error) {
+	if gvk.GroupVersion() != kubebindv1alpha1.SchemeGroupVersion || gvk.Kind != "BindingResponse" {
+		return nil, fmt.Errorf("unexpected response type %s, only supporting %s", gvk, kubebindv1alpha1.SchemeGroupVersion.WithKind("BindingResponse"))
+	}
+	response, ok := obj.(*kubebindv1alpha1.BindingResponse)
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type %T", obj)
+	}
+	if response.Authentication.Token == nil {
+		return nil, fmt.Errorf("unexpected authentication method, expected token")
+	}
+	if response.Authentication.Token.SessionID != sessionID {
+		return nil, fmt.Errorf("unexpected session ID %q", response.Authentication.Token.SessionID)
+	}
+
+	result := &bindingResponse{BindingResponse: response}
+	for i, request := range response.Requests {
+		var meta metav1.TypeMeta
+		if err := json.Unmarshal(request.Raw, &meta); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal request #%d: %v", i, err)
+		}
+		if got, expected := meta.APIVersion, kubebindv1alpha1.SchemeGroupVersion.String(); got != expected {
+			return nil, fmt.Errorf("request #%d is not %s, got %s", i, expected, got)
+		}
+		var apiRequest kubebindv1alpha1.APIServiceExportRequestResponse
+		if err := json.Unmarshal(request.Raw, &apiRequest); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal request #%d: %v", i, err)
+		}
+		result.requests = append(result.requests, &apiRequest)
+	}
+	return result, nil
+}
+
+// filterRequests restricts the requests to the given resources. Requests
+// without any of the resources are dropped. No resources means all.
+func filterRequests(requests []*kubebindv1alpha1.APIServiceExportRequestResponse, resources []kubebindv1alpha1.GroupResource) []*kubebindv1alpha1.APIServiceExportRequestResponse {
+	if len(resources) == 0 {
+		return requests
+	}
+
+	wanted := sets.New[kubebindv1alpha1.GroupResource](resources...)
+	var filtered []*kubebindv1alpha1.APIServiceExportRequestResponse
+	for _, request := range requests {
+		var kept []kubebindv1alpha1.APIServiceExportRequestResource
+		for _, resource := range request.Spec.Resources {
+			if wanted.Has(resource.GroupResource) {
+				kept = append(kept, resource)
+			}
+		}
+		if len(kept) == 0 {
+			continue
+		}
+		request = request.DeepCopy()
+		request.Spec.Resources = kept
+		filtered = append(filtered, request)
+	}
+	return filtered
+}
+
+func kubeconfigSecretName(request *kubebindv1alpha1.BindingRequest) string {
+	return "bindingrequest-" + request.Name
+}
+
+func ownerReference(request *kubebindv1alpha1.BindingRequest) metav1.OwnerReference {
+	return *metav1.NewControllerRef(request, kubebindv1alpha1.SchemeGroupVersion.WithKind(kubebindv1alpha1.ResourceKindBindingRequest))
+}
@@ -0,0 +1,291 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bindingrequest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"kmodules.xyz/client-go/conditions"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: provider
+  cluster:
+    server: https://provider:6443
+contexts:
+- name: provider
+  context:
+    cluster: provider
+    namespace: kube-bind-abcdef
+    user: provider
+current-context: provider
+users:
+- name: provider
+  user:
+    token: secret
+`
+
+func TestReconcile(t *testing.T) {
+	secrets := map[string]*corev1.Secret{
+		"token": {
+			ObjectMeta: metav1.ObjectMeta{Namespace: models.KonnectorNamespace, Name: "token"},
+			Data:       map[string][]byte{"token": []byte("bt-abc.secret\n")},
+		},
+	}
+	exportRequests := map[string]*kubebindv1alpha1.APIServiceExportRequest{}
+	bindings := map[string]*kubebindv1alpha1.APIServiceBinding{}
+	var requeued time.Duration
+	wantResources := []string{"mangodbs.example.com"}
+
+	r := &reconciler{
+		getSecret: func(ns, name string) (*corev1.Secret, error) {
+			if s, ok := secrets[name]; ok && ns == models.KonnectorNamespace {
+				return s, nil
+			}
+			return nil, errors.NewNotFound(corev1.Resource("secrets"), name)
+		},
+		getNamespace: func(name string) (*corev1.Namespace, error) {
+			return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, UID: "uid"}}, nil
+		},
+		getBindingProvider: func(ctx context.Context, url string) (*kubebindv1alpha1.BindingProvider, error) {
+			return &kubebindv1alpha1.BindingProvider{
+				TypeMeta: metav1.TypeMeta{APIVersion: kubebindv1alpha1.GroupVersion},
+				AuthenticationMethods: []kubebindv1alpha1.AuthenticationMethod{
+					{Method: "Token", Token: &kubebindv1alpha1.TokenExchange{ExchangeURL: "https://provider/token"}},
+				},
+			}, nil
+		},
+		exchangeToken: func(ctx context.Context, exchange *kubebindv1alpha1.TokenExchange, token, sessionID, clusterID, clusterName, user string, resources []string) (k8sruntime.Object, *schema.GroupVersionKind, error) {
+			require.Equal(t, "bt-abc.secret", token)
+			require.Equal(t, "alice", user)
+			require.Equal(t, "prod", clusterName)
+			require.Equal(t, wantResources, resources)
+
+			var requests []k8sruntime.RawExtension
+			for _, resource := range []string{"mangodbs", "redis"} {
+				bs, err := json.Marshal(&kubebindv1alpha1.APIServiceExportRequestResponse{
+					TypeMeta:   metav1.TypeMeta{APIVersion: kubebindv1alpha1.SchemeGroupVersion.String(), Kind: "APIServiceExportRequest"},
+					ObjectMeta: kubebindv1alpha1.NameObjectMeta{Name: resource},
+					Spec: kubebindv1alpha1.APIServiceExportRequestSpec{
+						Resources: []kubebindv1alpha1.APIServiceExportRequestResource{
+							{GroupResource: kubebindv1alpha1.GroupResource{Group: "example.com", Resource: resource}},
+						},
+					},
+				})
+				require.NoError(t, err)
+				requests = append(requests, k8sruntime.RawExtension{Raw: bs})
+			}
+			gvk := kubebindv1alpha1.SchemeGroupVersion.WithKind("BindingResponse")
+			return &kubebindv1alpha1.BindingResponse{
+				Authentication: kubebindv1alpha1.BindingResponseAuthentication{
+					Token: &kubebindv1alpha1.BindingResponseAuthenticationToken{SessionID: sessionID},
+				},
+				Kubeconfig: []byte(testKubeconfig),
+				Requests:   requests,
+			}, &gvk, nil
+		},
+		ensureKubeconfigSecret: func(ctx context.Context, secret *corev1.Secret) error {
+			secrets[secret.Name] = secret
+			return nil
+		},
+		createServiceExportRequest: func(ctx context.Context, kubeconfig []byte, ns string, request *kubebindv1alpha1.APIServiceExportRequest) (*kubebindv1alpha1.APIServiceExportRequest, error) {
+			require.Equal(t, "kube-bind-abcdef", ns)
+			if _, found := exportRequests[request.Name]; found {
+				return nil, errors.NewAlreadyExists(kubebindv1alpha1.Resource("apiserviceexportrequests"), request.Name)
+			}
+			exportRequests[request.Name] = request
+			return request, nil
+		},
+		getServiceExportRequest: func(ctx context.Context, kubeconfig []byte, ns, name string) (*kubebindv1alpha1.APIServiceExportRequest, error) {
+			if request, ok := exportRequests[name]; ok {
+				return request, nil
+			}
+			return nil, errors.NewNotFound(kubebindv1alpha1.Resource("apiserviceexportrequests"), name)
+		},
+		deleteServiceExportRequest: func(ctx context.Context, kubeconfig []byte, ns, name string) error {
+			delete(exportRequests, name)
+			return nil
+		},
+		getServiceBinding: func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
+			if binding, ok := bindings[name]; ok {
+				return binding, nil
+			}
+			return nil, errors.NewNotFound(kubebindv1alpha1.Resource("apiservicebindings"), name)
+		},
+		createServiceBinding: func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
+			bindings[binding.Name] = binding
+			return nil
+		},
+		updateServiceBinding: func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
+			bindings[binding.Name] = binding
+			return nil
+		},
+		deleteServiceBinding: func(ctx context.Context, name string) error {
+			delete(bindings, name)
+			return nil
+		},
+		requeueAfter: func(request *kubebindv1alpha1.BindingRequest, duration time.Duration) {
+			requeued = duration
+		},
+	}
+
+	request := &kubebindv1alpha1.BindingRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Generation: 1, UID: "request-uid"},
+		Spec: kubebindv1alpha1.BindingRequestSpec{
+			URL: "https://provider/export?user=alice&cluster=prod",
+			Credentials: kubebindv1alpha1.BindingRequestCredentials{
+				TokenSecretRef: kubebindv1alpha1.TokenSecretKeyRef{Name: "token"},
+			},
+			Resources: []kubebindv1alpha1.GroupResource{{Group: "example.com", Resource: "mangodbs"}},
+		},
+	}
+
+	// authenticates and creates the filtered export requests
+	require.NoError(t, r.reconcile(context.Background(), request))
+	require.Equal(t, int64(1), request.Status.ObservedGeneration)
+	require.Equal(t, "bindingrequest-db", request.Status.KubeconfigSecretName)
+	require.Equal(t, "kube-bind-abcdef", request.Status.RemoteNamespace)
+	require.Equal(t, []string{"mangodbs"}, request.Status.ExportRequests)
+	require.Equal(t, []byte(testKubeconfig), secrets["bindingrequest-db"].Data["kubeconfig"])
+	require.True(t, conditions.IsTrue(request, kubebindv1alpha1.BindingRequestConditionAuthenticated))
+	require.True(t, conditions.IsFalse(request, kubebindv1alpha1.BindingRequestConditionBound))
+	require.Equal(t, pendingRecheckInterval, requeued)
+	require.Empty(t, bindings)
+
+	// binds when the export request succeeded
+	exportRequests["mangodbs"].Status.Phase = kubebindv1alpha1.APIServiceExportRequestPhaseSucceeded
+	require.NoError(t, r.reconcile(context.Background(), request))
+	require.Empty(t, request.Status.ExportRequests)
+	require.Equal(t, []string{"mangodbs.example.com"}, request.Status.Bindings)
+	require.True(t, conditions.IsTrue(request, kubebindv1alpha1.BindingRequestConditionBound))
+	binding := bindings["mangodbs.example.com"]
+	require.NotNil(t, binding)
+	require.Equal(t, "bindingrequest-db", binding.Spec.Providers[0].Kubeconfig.Name)
+	require.Equal(t, "kube-bind-abcdef", binding.Spec.Providers[0].RemoteNamespace)
+	require.Equal(t, types.UID("request-uid"), binding.OwnerReferences[0].UID)
+
+	// re-authenticates when the kubeconfig secret is lost, reusing the existing export request
+	delete(secrets, "bindingrequest-db")
+	require.NoError(t, r.reconcile(context.Background(), request))
+	require.NotNil(t, secrets["bindingrequest-db"])
+	require.Len(t, exportRequests, 1)
+	require.Equal(t, kubebindv1alpha1.APIServiceExportRequestPhaseSucceeded, exportRequests["mangodbs"].Status.Phase)
+
+	// replaces a failed export request of the same name
+	exportRequests["mangodbs"].Status.Phase = kubebindv1alpha1.APIServiceExportRequestPhaseFailed
+	delete(secrets, "bindingrequest-db")
+	require.NoError(t, r.reconcile(context.Background(), request))
+	require.Len(t, exportRequests, 1)
+	require.Empty(t, exportRequests["mangodbs"].Status.Phase)
+	require.Equal(t, []string{"mangodbs"}, request.Status.ExportRequests)
+
+	// prunes the bindings of removed resources
+	request.Generation++
+	request.Spec.Resources = []kubebindv1alpha1.GroupResource{{Group: "example.com", Resource: "redis"}}
+	wantResources = []string{"redis.example.com"}
+	require.NoError(t, r.reconcile(context.Background(), request))
+	require.Empty(t, request.Status.Bindings)
+	require.Empty(t, bindings)
+}
+
+func TestPruneServiceBindingsKeepsOtherProviders(t *testing.T) {
+	other := kubebindv1alpha1.Provider{
+		Kubeconfig: kubebindv1alpha1.ClusterSecretKeyRef{
+			LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: "bindingrequest-other", Key: "kubeconfig"},
+			Namespace:         models.KonnectorNamespace,
+		},
+	}
+	own := kubebindv1alpha1.Provider{
+		Kubeconfig: kubebindv1alpha1.ClusterSecretKeyRef{
+			LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: "bindingrequest-db", Key: "kubeconfig"},
+			Namespace:         models.KonnectorNamespace,
+		},
+	}
+	bindings := map[string]*kubebindv1alpha1.APIServiceBinding{
+		"mangodbs.example.com": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "mangodbs.example.com",
+				OwnerReferences: []metav1.OwnerReference{{Name: "db", UID: "request-uid"}},
+			},
+			Spec: kubebindv1alpha1.APIServiceBindingSpec{Providers: []kubebindv1alpha1.Provider{own, other}},
+		},
+	}
+	r := &reconciler{
+		getServiceBinding: func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
+			if binding, ok := bindings[name]; ok {
+				return binding, nil
+			}
+			return nil, errors.NewNotFound(kubebindv1alpha1.Resource("apiservicebindings"), name)
+		},
+		updateServiceBinding: func(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
+			bindings[binding.Name] = binding
+			return nil
+		},
+	}
+
+	request := &kubebindv1alpha1.BindingRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", UID: "request-uid"},
+		Spec: kubebindv1alpha1.BindingRequestSpec{
+			Resources: []kubebindv1alpha1.GroupResource{{Group: "example.com", Resource: "redis"}},
+		},
+		Status: kubebindv1alpha1.BindingRequestStatus{
+			KubeconfigSecretName: "bindingrequest-db",
+			Bindings:             []string{"mangodbs.example.com"},
+		},
+	}
+	require.NoError(t, r.pruneServiceBindings(context.Background(), request))
+	require.Empty(t, request.Status.Bindings)
+	require.Equal(t, []kubebindv1alpha1.Provider{other}, bindings["mangodbs.example.com"].Spec.Providers)
+	require.Empty(t, bindings["mangodbs.example.com"].OwnerReferences)
+}
+
+func TestReconcileMissingToken(t *testing.T) {
+	r := &reconciler{
+		getSecret: func(ns, name string) (*corev1.Secret, error) {
+			return nil, errors.NewNotFound(corev1.Resource("secrets"), name)
+		},
+	}
+	request := &kubebindv1alpha1.BindingRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: "db", Generation: 1},
+		Spec: kubebindv1alpha1.BindingRequestSpec{
+			URL: "https://provider/export?user=alice",
+			Credentials: kubebindv1alpha1.BindingRequestCredentials{
+				TokenSecretRef: kubebindv1alpha1.TokenSecretKeyRef{Name: "token"},
+			},
+		},
+	}
+
+	require.NoError(t, r.reconcile(context.Background(), request))
+	require.True(t, conditions.IsFalse(request, kubebindv1alpha1.BindingRequestConditionAuthenticated))
+	require.Equal(t, "TokenSecretNotFound", conditions.GetReason(request, kubebindv1alpha1.BindingRequestConditionAuthenticated))
+	require.Empty(t, request.Status.KubeconfigSecretName)
+}
@@ -18,7 +18,9 @@ package cluster
 
 import (
 	"context"
+	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
@@ -28,11 +30,16 @@ import (
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/clusterbinding"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/namespacedeletion"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/reachability"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/servicebinding"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/synctuning"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
 
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	crdlisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/runtime"
@@ -57,10 +64,14 @@ const (
 func NewController(
 	reconcileServiceBinding func(binding *kubebindv1alpha1.APIServiceBinding) bool,
 	consumerConfig *rest.Config,
+	metadataSync *kubebindv1alpha1.MetadataSync,
+	syncTuning *kubebindv1alpha1.SyncTuning,
+	sharder *sharding.Sharder,
 	providerInfos []*konnectormodels.ProviderInfo,
 	namespaceInformer dynamic.Informer[corelisters.NamespaceLister],
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	crdInformer dynamic.Informer[crdlisters.CustomResourceDefinitionLister],
+	conversionWebhook *apiextensionsv1.WebhookClientConfig,
 ) (*controller, error) {
 	consumerConfig = rest.CopyConfig(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
@@ -77,6 +88,7 @@ func NewController(
 		if provider.KubeClient, err = kubernetesclient.NewForConfig(provider.Config); err != nil {
 			return nil, err
 		}
+		provider.Reachability = reachability.NewTracker(provider.ClusterID, provider.KubeClient.Discovery().RESTClient())
 
 		provider.BindInformer = bindinformers.NewSharedInformerFactoryWithOptions(provider.BindClient, time.Minute*30, bindinformers.WithNamespace(provider.Namespace))
 		provider.KubeInformer = kubernetesinformers.NewSharedInformerFactoryWithOptions(provider.KubeClient, time.Minute*30, kubernetesinformers.WithNamespace(provider.Namespace))
@@ -92,12 +104,14 @@ func NewController(
 	}
 	consumerSecretInformers := kubernetesinformers.NewSharedInformerFactoryWithOptions(consumerKubeClient, time.Minute*30)
 
-	// create controllers
+	// create controllers, retrying with the default sync tuning
+	tuning := synctuning.Merge(syncTuning, nil)
 	clusterbindingCtrl, err := clusterbinding.NewController(
 		heartbeatInterval,
 		consumerConfig,
 		serviceBindingInformer,
 		consumerSecretInformers.Core().V1().Secrets(),
+		synctuning.NewRateLimiter(tuning),
 		providerInfos,
 	)
 	if err != nil {
@@ -106,6 +120,7 @@ func NewController(
 	namespacedeletionCtrl, err := namespacedeletion.NewController(
 		providerInfos,
 		namespaceInformer,
+		synctuning.NewRateLimiter(tuning),
 	)
 	if err != nil {
 		return nil, err
@@ -115,13 +130,18 @@ func NewController(
 		consumerConfig,
 		serviceBindingInformer,
 		crdInformer,
+		synctuning.NewRateLimiter(tuning),
 		providerInfos,
+		conversionWebhook,
 	)
 	if err != nil {
 		return nil, err
 	}
 	serviceexportCtrl, err := serviceexport.NewController(
 		consumerConfig,
+		metadataSync,
+		syncTuning,
+		sharder,
 		serviceBindingInformer,
 		crdInformer,
 		providerInfos,
@@ -184,11 +204,22 @@ func (c *controller) Start(ctx context.Context) {
 	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
 	ctx = klog.NewContext(ctx, logger)
 
+	logger.V(2).Info("starting reachability probes")
+	for _, provider := range c.providerInfos {
+		go provider.Reachability.Run(ctx)
+		provider.Reachability.Subscribe(ctx, func(bool) {
+			c.updateServiceBindings(ctx, c.markProviderReachable)
+		})
+	}
+
 	logger.V(2).Info("starting factories")
 	for _, factory := range c.factories {
 		factory.Start(ctx.Done())
 	}
 
+	informersSynced := metrics.NewInformersSynced()
+	defer informersSynced.Forget()
+
 	if err := wait.PollUntilContextCancel(ctx, heartbeatInterval, true, func(ctx context.Context) (bool, error) {
 		waitCtx, cancel := context.WithDeadline(ctx, time.Now().Add(heartbeatInterval/2))
 		defer cancel()
@@ -198,6 +229,10 @@ func (c *controller) Start(ctx context.Context) {
 			synced := factory.WaitForCacheSync(waitCtx.Done())
 			logger.V(2).Info("cache sync", "synced", synced)
 		}
+		for _, provider := range c.providerInfos {
+			informersSynced.Record(provider.ClusterID, "kube-bind", allSynced(provider.BindInformer.WaitForCacheSync(waitCtx.Done())))
+			informersSynced.Record(provider.ClusterID, "kube", allSynced(provider.KubeInformer.WaitForCacheSync(waitCtx.Done())))
+		}
 		select {
 		case <-ctx.Done():
 			// timeout
@@ -225,6 +260,7 @@ func (c *controller) Start(ctx context.Context) {
 	logger.V(2).Info("setting InformersSynced condition to true on service binding")
 	c.updateServiceBindings(ctx, func(binding *kubebindv1alpha1.APIServiceBinding) {
 		conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionInformersSynced)
+		c.markProviderReachable(binding)
 	})
 
 	go c.clusterbindingCtrl.Start(ctx, 2)
@@ -235,6 +271,38 @@ func (c *controller) Start(ctx context.Context) {
 	<-ctx.Done()
 }
 
+// markProviderReachable sets the ProviderReachable condition of the binding
+// from the reachability of all provider clusters.
+func (c *controller) markProviderReachable(binding *kubebindv1alpha1.APIServiceBinding) {
+	var unreachable []string
+	for _, provider := range c.providerInfos {
+		if reachable, since, err := provider.Reachability.State(); !reachable {
+			unreachable = append(unreachable, fmt.Sprintf("%s since %s: %v", provider.ClusterID, since.UTC().Format(time.RFC3339), err))
+		}
+	}
+	if len(unreachable) == 0 {
+		conditions.MarkTrue(binding, kubebindv1alpha1.APIServiceBindingConditionProviderReachable)
+		return
+	}
+	conditions.MarkFalse(
+		binding,
+		kubebindv1alpha1.APIServiceBindingConditionProviderReachable,
+		"ProviderUnreachable",
+		conditionsapi.ConditionSeverityWarning,
+		"Provider cluster unreachable: %s",
+		strings.Join(unreachable, "; "),
+	)
+}
+
+func allSynced(synced map[reflect.Type]bool) bool {
+	for _, ok := range synced {
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
 func (c *controller) updateServiceBindings(ctx context.Context, update func(*kubebindv1alpha1.APIServiceBinding)) {
 	logger := klog.FromContext(ctx)
 
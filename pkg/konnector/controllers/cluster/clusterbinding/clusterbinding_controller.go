@@ -28,6 +28,7 @@ import (
 	"go.bytebuilders.dev/kube-bind/pkg/committer"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	corev1 "k8s.io/api/core/v1"
@@ -57,9 +58,10 @@ func NewController(
 	consumerConfig *rest.Config,
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	consumerSecretInformer coreinformers.SecretInformer,
+	rateLimiter workqueue.RateLimiter,
 	providerInfos []*konnectormodels.ProviderInfo,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter, controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -347,6 +349,15 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
 
+	// update ProviderReachable on transitions
+	for _, provider := range c.providerInfos {
+		key := provider.Namespace + "/cluster"
+		provider.Reachability.Subscribe(ctx, func(reachable bool) {
+			logger.V(2).Info("queueing ClusterBinding", "key", key, "reason", "ProviderReachability", "reachable", reachable)
+			c.queue.Add(key)
+		})
+	}
+
 	// start the heartbeat
 	for _, provider := range c.providerInfos {
 		// nolint:errcheck
@@ -402,12 +413,13 @@ func (c *controller) process(ctx context.Context, key string) error {
 	}
 
 	var clusterBindingLister bindlisters.ClusterBindingLister
-	var consumerSecretRefKey string
+	var consumerSecretRefKey, clusterID string
 
 	for _, provider := range c.providerInfos {
 		if provider.Namespace == ns {
 			clusterBindingLister = provider.BindInformer.KubeBind().V1alpha1().ClusterBindings().Lister()
 			consumerSecretRefKey = provider.ConsumerSecretRefKey
+			clusterID = provider.ClusterID
 			break
 		}
 	}
@@ -439,7 +451,9 @@ func (c *controller) process(ctx context.Context, key string) error {
 	// If the object being reconciled changed as a result, update it.
 	oldResource := &Resource{ObjectMeta: old.ObjectMeta, Spec: &old.Spec, Status: &old.Status}
 	newResource := &Resource{ObjectMeta: obj.ObjectMeta, Spec: &obj.Spec, Status: &obj.Status}
-	if err := c.commit(ctx, oldResource, newResource); err != nil {
+	err = c.commit(ctx, oldResource, newResource)
+	metrics.RecordHeartbeat(clusterID, obj.Status.LastHeartbeatTime.Time, err)
+	if err != nil {
 		errs = append(errs, err)
 
 		// try to update service bindings
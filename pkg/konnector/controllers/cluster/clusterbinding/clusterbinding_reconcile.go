@@ -57,6 +57,10 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.Cl
 		return err
 	}
 
+	if err := r.ensureProviderReachable(ctx, binding, provider); err != nil {
+		errs = append(errs, err)
+	}
+
 	if err := r.ensureConsumerSecret(ctx, binding, provider); err != nil {
 		errs = append(errs, err)
 	}
@@ -65,6 +69,10 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.Cl
 		errs = append(errs, err)
 	}
 
+	if err := r.ensureKubeconfigRenewal(ctx, binding); err != nil {
+		errs = append(errs, err)
+	}
+
 	if err := r.ensureKonnectorVersion(ctx, binding); err != nil {
 		errs = append(errs, err)
 	}
@@ -113,6 +121,48 @@ func (r *reconciler) ensureHeartbeat(ctx context.Context, binding *kubebindv1alp
 	return nil
 }
 
+func (r *reconciler) ensureProviderReachable(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding, provider *konnectormodels.ProviderInfo) error {
+	reachable, since, err := provider.Reachability.State()
+	if reachable {
+		conditions.MarkTrue(
+			binding,
+			kubebindv1alpha1.ClusterBindingConditionProviderReachable,
+		)
+		return nil
+	}
+
+	// The status update will most likely fail too, but is retried and then
+	// records that the konnector lost the connection.
+	conditions.MarkFalse(
+		binding,
+		kubebindv1alpha1.ClusterBindingConditionProviderReachable,
+		"ProviderUnreachable",
+		conditionsapi.ConditionSeverityWarning,
+		"Provider cluster unreachable since %s: %v",
+		since.UTC().Format(time.RFC3339), err,
+	)
+	return nil
+}
+
+// ensureKubeconfigRenewal requests new credentials from the service provider
+// once the renewal time of the current ones has passed. The provider updates
+// the kubeconfig secret, which is then copied to the consumer secret.
+func (r *reconciler) ensureKubeconfigRenewal(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding) error {
+	logger := klog.FromContext(ctx)
+
+	renewal := binding.Status.KubeconfigRenewalTime
+	if renewal == nil || time.Now().Before(renewal.Time) {
+		return nil
+	}
+	if requested := binding.Status.KubeconfigRenewalRequestTime; requested != nil && !requested.Before(renewal) {
+		return nil // already requested
+	}
+
+	logger.Info("Requesting new kubeconfig credentials", "expiration", binding.Status.KubeconfigExpirationTime)
+	binding.Status.KubeconfigRenewalRequestTime = &metav1.Time{Time: time.Now()}
+	return nil
+}
+
 func (r *reconciler) ensureConsumerSecret(ctx context.Context, binding *kubebindv1alpha1.ClusterBinding, provider *konnectormodels.ProviderInfo) error {
 	logger := klog.FromContext(ctx)
 
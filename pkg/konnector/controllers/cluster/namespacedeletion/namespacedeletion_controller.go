@@ -47,8 +47,9 @@ const (
 func NewController(
 	providerInfos []*konnectormodels.ProviderInfo,
 	namespaceInformer dynamic.Informer[corelisters.NamespaceLister],
+	rateLimiter workqueue.RateLimiter,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter, controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 	for _, provider := range providerInfos {
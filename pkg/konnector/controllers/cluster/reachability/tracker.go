@@ -0,0 +1,165 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reachability
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/klog/v2"
+)
+
+const (
+	probeInterval = 10 * time.Second
+	probeTimeout  = 5 * time.Second
+
+	// failureThreshold is the number of consecutive failed probes after which
+	// the provider is considered unreachable. One successful probe makes it
+	// reachable again.
+	failureThreshold = 3
+)
+
+// Tracker tracks whether the API server of a provider cluster is reachable by
+// probing its /version endpoint, which every authenticated user may read.
+//
+// A nil Tracker is always reachable.
+type Tracker struct {
+	clusterID string
+	probe     func(ctx context.Context) error
+
+	lock      sync.RWMutex
+	reachable bool
+	since     time.Time
+	lastErr   error
+	failures  int
+	handlers  map[*func(reachable bool)]struct{}
+}
+
+// NewTracker returns a tracker probing the provider cluster with the given
+// REST client. The provider is considered reachable until probes fail.
+func NewTracker(clusterID string, client rest.Interface) *Tracker {
+	return &Tracker{
+		clusterID: clusterID,
+		probe: func(ctx context.Context) error {
+			return client.Get().AbsPath("/version").Do(ctx).Error()
+		},
+
+		reachable: true,
+		since:     time.Now(),
+		handlers:  map[*func(reachable bool)]struct{}{},
+	}
+}
+
+// Reachable returns true if the provider is reachable.
+func (t *Tracker) Reachable() bool {
+	if t == nil {
+		return true
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.reachable
+}
+
+// State returns whether the provider is reachable, since when, and the last
+// probe error if unreachable.
+func (t *Tracker) State() (reachable bool, since time.Time, err error) {
+	if t == nil {
+		return true, time.Time{}, nil
+	}
+
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	return t.reachable, t.since, t.lastErr
+}
+
+// Subscribe calls the handler on every transition until ctx is done.
+func (t *Tracker) Subscribe(ctx context.Context, handler func(reachable bool)) {
+	if t == nil {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.handlers[&handler] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		delete(t.handlers, &handler)
+	}()
+}
+
+// Run probes the provider until ctx is done.
+func (t *Tracker) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("clusterID", t.clusterID)
+	ctx = klog.NewContext(ctx, logger)
+
+	metrics.RecordProviderReachable(t.clusterID, t.Reachable())
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		probeCtx, cancel := context.WithTimeout(ctx, probeTimeout)
+		defer cancel()
+		t.observe(ctx, t.probe(probeCtx))
+	}, probeInterval)
+}
+
+// observe records the result of a probe, notifying the subscribers on
+// transitions.
+func (t *Tracker) observe(ctx context.Context, err error) {
+	logger := klog.FromContext(ctx)
+
+	t.lock.Lock()
+	wasReachable := t.reachable
+	if err == nil {
+		t.failures = 0
+		t.lastErr = nil
+		t.reachable = true
+	} else {
+		t.failures++
+		t.lastErr = err
+		if t.failures >= failureThreshold {
+			t.reachable = false
+		}
+	}
+	if t.reachable == wasReachable {
+		t.lock.Unlock()
+		return
+	}
+	t.since = time.Now()
+	reachable := t.reachable
+	handlers := make([]func(bool), 0, len(t.handlers))
+	for h := range t.handlers {
+		handlers = append(handlers, *h)
+	}
+	t.lock.Unlock()
+
+	if reachable {
+		logger.Info("provider cluster is reachable again")
+	} else {
+		logger.Info("provider cluster is unreachable", "err", err)
+	}
+	metrics.RecordProviderReachable(t.clusterID, reachable)
+	for _, h := range handlers {
+		h(reachable)
+	}
+}
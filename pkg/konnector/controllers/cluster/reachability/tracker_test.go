@@ -0,0 +1,62 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reachability
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	var nilTracker *Tracker
+	require.True(t, nilTracker.Reachable(), "a nil tracker is reachable")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	tr := NewTracker("abc", nil)
+	require.True(t, tr.Reachable(), "reachable until probes fail")
+
+	var transitions []bool
+	tr.Subscribe(ctx, func(reachable bool) { transitions = append(transitions, reachable) })
+
+	boom := errors.New("boom")
+	for i := 1; i < failureThreshold; i++ {
+		tr.observe(ctx, boom)
+		require.True(t, tr.Reachable(), "single failures are tolerated")
+	}
+	tr.observe(ctx, boom)
+	reachable, _, err := tr.State()
+	require.False(t, reachable)
+	require.Equal(t, boom, err)
+
+	tr.observe(ctx, boom)
+	require.Equal(t, []bool{false}, transitions, "only transitions are notified")
+
+	tr.observe(ctx, nil)
+	reachable, _, err = tr.State()
+	require.True(t, reachable)
+	require.NoError(t, err)
+	require.Equal(t, []bool{false, true}, transitions)
+
+	// failures are counted from the last success
+	tr.observe(ctx, boom)
+	require.True(t, tr.Reachable())
+}
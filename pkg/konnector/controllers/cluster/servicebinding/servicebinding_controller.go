@@ -53,9 +53,11 @@ func NewController(
 	consumerConfig *rest.Config,
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	crdInformer dynamic.Informer[apiextensionslisters.CustomResourceDefinitionLister],
+	rateLimiter workqueue.RateLimiter,
 	providerInfos []*konnectormodels.ProviderInfo,
+	conversionWebhook *apiextensionsv1.WebhookClientConfig,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter, controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -90,7 +92,8 @@ func NewController(
 		providerInfors: providerInfos,
 
 		reconciler: reconciler{
-			providerInfos: providerInfos,
+			providerInfos:     providerInfos,
+			conversionWebhook: conversionWebhook,
 
 			reconcileServiceBinding: reconcileServiceBinding,
 
@@ -35,6 +35,10 @@ import (
 type reconciler struct {
 	providerInfos []*konnectormodels.ProviderInfo
 
+	// conversionWebhook is the client config of the conversion webhook of the
+	// konnector. Without, CRDs only serve the sync version.
+	conversionWebhook *apiextensionsv1.WebhookClientConfig
+
 	reconcileServiceBinding func(binding *v1alpha1.APIServiceBinding) bool
 	getServiceExport        func(provider *konnectormodels.ProviderInfo, ns string) (*v1alpha1.APIServiceExport, error)
 	getServiceBinding       func(name string) (*v1alpha1.APIServiceBinding, error)
@@ -64,6 +68,8 @@ func (r *reconciler) reconcile(ctx context.Context, binding *v1alpha1.APIService
 		errs = append(errs, err)
 	}
 
+	r.ensurePaused(binding)
+
 	//if err := r.ensureClusterName(ctx, binding); err != nil {
 	//	errs = append(errs, err)
 	//}
@@ -73,6 +79,17 @@ func (r *reconciler) reconcile(ctx context.Context, binding *v1alpha1.APIService
 	return utilerrors.NewAggregate(errs)
 }
 
+// ensurePaused reports whether the sync of the bound resource is paused. The
+// syncers are stopped by the serviceexport controller.
+func (r *reconciler) ensurePaused(binding *v1alpha1.APIServiceBinding) {
+	if !binding.Spec.Paused {
+		conditions.Delete(binding, v1alpha1.APIServiceBindingConditionPaused)
+		return
+	}
+
+	conditions.MarkTrue(binding, v1alpha1.APIServiceBindingConditionPaused)
+}
+
 func (r *reconciler) ensureValidServiceExport(ctx context.Context, binding *v1alpha1.APIServiceBinding) error {
 	for _, provider := range r.providerInfos {
 		if _, err := r.getServiceExport(provider, binding.Name); err != nil && !errors.IsNotFound(err) {
@@ -127,7 +144,7 @@ func (r *reconciler) ensureCRDs(ctx context.Context, binding *v1alpha1.APIServic
 			return nil // nothing we can do here
 		}
 
-		crd, err := helpers.ServiceExportToCRD(export)
+		crd, err := helpers.ServiceExportToCRD(export, r.conversionWebhook)
 		if err != nil {
 			conditions.MarkFalse(
 				binding,
@@ -185,7 +202,15 @@ func (r *reconciler) ensureCRDs(ctx context.Context, binding *v1alpha1.APIServic
 			return nil
 		}
 
-		crd.ObjectMeta = existing.ObjectMeta
+		conversion, hasConversion := crd.Annotations[v1alpha1.ProviderConversionAnnotation]
+		crd.ObjectMeta = *existing.ObjectMeta.DeepCopy()
+		if hasConversion {
+			metav1.SetMetaDataAnnotation(&crd.ObjectMeta, v1alpha1.ProviderConversionAnnotation, conversion)
+		} else {
+			delete(crd.Annotations, v1alpha1.ProviderConversionAnnotation)
+		}
+		helpers.KeepStoredVersions(crd, existing)
+		helpers.SetConversionWebhook(crd, r.conversionWebhook)
 		if _, err := r.updateCRD(ctx, crd); err != nil && !errors.IsInvalid(err) {
 			return nil
 		} else if errors.IsInvalid(err) {
@@ -88,6 +88,21 @@ func TestEnsureCRDs(t *testing.T) {
 	}
 }
 
+func TestEnsurePaused(t *testing.T) {
+	r := &reconciler{}
+
+	b := newBinding("foo")
+	b.Spec.Paused = true
+	r.ensurePaused(b)
+	require.Len(t, b.Status.Conditions, 1)
+	require.Equal(t, v1alpha1.APIServiceBindingConditionPaused, b.Status.Conditions[0].Type)
+	require.Equal(t, metav1.ConditionTrue, b.Status.Conditions[0].Status)
+
+	b.Spec.Paused = false
+	r.ensurePaused(b)
+	require.Empty(t, b.Status.Conditions)
+}
+
 func newGetCRD(name string, crd *apiextensionsv1.CustomResourceDefinition) func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 	return func(n string) (*apiextensionsv1.CustomResourceDefinition, error) {
 		if n == name {
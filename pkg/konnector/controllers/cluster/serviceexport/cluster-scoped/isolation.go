@@ -0,0 +1,179 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterscoped
+
+import (
+	"errors"
+	"fmt"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ErrCollision is returned when a provider side object with the expected name
+// exists, but does not belong to the consumer behind the given cluster namespace.
+var ErrCollision = errors.New("object belongs to another consumer")
+
+// IsCollision returns true if err is or wraps ErrCollision.
+func IsCollision(err error) bool {
+	return errors.Is(err, ErrCollision)
+}
+
+// Isolator maps cluster-scoped consumer objects onto their provider side
+// representation according to the ClusterScopedIsolation of an APIServiceExport.
+type Isolator interface {
+	// UpstreamKey returns the provider side namespace and name of the consumer
+	// side object with the given name.
+	UpstreamKey(name, clusterNs string) (string, string)
+
+	// DownstreamName returns the consumer side name of the provider side object
+	// with the given namespace and name. It returns false if the object cannot
+	// belong to the given cluster namespace.
+	DownstreamName(ns, name, clusterNs string) (string, bool)
+
+	// ToUpstream mutates a consumer side object in place into its provider side
+	// representation.
+	ToUpstream(obj *unstructured.Unstructured, clusterNs, clusterNsUID string) error
+
+	// ToDownstream mutates a provider side object in place into its consumer side
+	// representation. It returns ErrCollision if the object does not belong to
+	// the given cluster namespace.
+	ToDownstream(obj *unstructured.Unstructured, clusterNs string) error
+}
+
+// NewIsolator returns the Isolator for the given isolation. An empty isolation
+// defaults to IsolationPrefixed.
+func NewIsolator(isolation kubebindv1alpha1.Isolation) (Isolator, error) {
+	switch isolation {
+	case kubebindv1alpha1.IsolationPrefixed, "":
+		return prefixedIsolator{}, nil
+	case kubebindv1alpha1.IsolationNamespaced:
+		return namespacedIsolator{}, nil
+	case kubebindv1alpha1.IsolationNone:
+		return noneIsolator{}, nil
+	}
+	return nil, fmt.Errorf("unknown cluster-scoped isolation %q", isolation)
+}
+
+// IsOwnedBy returns true if the provider side object carries the given cluster
+// namespace annotation.
+func IsOwnedBy(obj *unstructured.Unstructured, clusterNs string) bool {
+	existing, err := ExtractClusterNs(obj)
+	return err == nil && existing == clusterNs
+}
+
+// prefixedIsolator prepends the cluster namespace to the name of the
+// cluster-scoped provider side object.
+type prefixedIsolator struct{}
+
+func (prefixedIsolator) UpstreamKey(name, clusterNs string) (string, string) {
+	return "", Prepend(name, clusterNs)
+}
+
+func (prefixedIsolator) DownstreamName(ns, name, clusterNs string) (string, bool) {
+	if ns != "" {
+		return "", false
+	}
+	downstreamName := Behead(name, clusterNs)
+	return downstreamName, downstreamName != name
+}
+
+func (prefixedIsolator) ToUpstream(obj *unstructured.Unstructured, clusterNs, clusterNsUID string) error {
+	return TranslateFromDownstream(obj, clusterNs, clusterNsUID)
+}
+
+func (prefixedIsolator) ToDownstream(obj *unstructured.Unstructured, clusterNs string) error {
+	if !IsOwnedBy(obj, clusterNs) {
+		return fmt.Errorf("%s: %w", obj.GetName(), ErrCollision)
+	}
+	return TranslateFromUpstream(obj)
+}
+
+// namespacedIsolator maps the cluster-scoped consumer side object onto a
+// namespaced provider side object inside the cluster namespace.
+type namespacedIsolator struct{}
+
+func (namespacedIsolator) UpstreamKey(name, clusterNs string) (string, string) {
+	return clusterNs, name
+}
+
+func (namespacedIsolator) DownstreamName(ns, name, clusterNs string) (string, bool) {
+	return name, ns == clusterNs
+}
+
+func (namespacedIsolator) ToUpstream(obj *unstructured.Unstructured, clusterNs, _ string) error {
+	copy := obj.DeepCopy()
+	ans := copy.GetAnnotations()
+	if existing, found := ans[ClusterNsAnnotationKey]; found && existing != clusterNs {
+		return errors.New("mismatch between existing cluster namespace and given cluster namespace")
+	}
+	if ans == nil {
+		ans = map[string]string{}
+	}
+	ans[ClusterNsAnnotationKey] = clusterNs
+	copy.SetAnnotations(ans)
+	copy.SetNamespace(clusterNs)
+	*obj = *copy
+	return nil
+}
+
+func (namespacedIsolator) ToDownstream(obj *unstructured.Unstructured, clusterNs string) error {
+	if obj.GetNamespace() != clusterNs || !IsOwnedBy(obj, clusterNs) {
+		return fmt.Errorf("%s/%s: %w", obj.GetNamespace(), obj.GetName(), ErrCollision)
+	}
+	copy := obj.DeepCopy()
+	ans := copy.GetAnnotations()
+	delete(ans, ClusterNsAnnotationKey)
+	copy.SetAnnotations(ans)
+	copy.SetNamespace("")
+	*obj = *copy
+	return nil
+}
+
+// noneIsolator keeps the names of cluster-scoped objects 1:1. Objects of other
+// consumers with the same name are detected by the cluster namespace annotation.
+type noneIsolator struct{}
+
+func (noneIsolator) UpstreamKey(name, _ string) (string, string) {
+	return "", name
+}
+
+func (noneIsolator) DownstreamName(ns, name, _ string) (string, bool) {
+	return name, ns == ""
+}
+
+func (noneIsolator) ToUpstream(obj *unstructured.Unstructured, clusterNs, clusterNsUID string) error {
+	copy := obj.DeepCopy()
+	if err := InjectClusterNs(copy, clusterNs, clusterNsUID); err != nil {
+		return err
+	}
+	*obj = *copy
+	return nil
+}
+
+func (noneIsolator) ToDownstream(obj *unstructured.Unstructured, clusterNs string) error {
+	if !IsOwnedBy(obj, clusterNs) {
+		return fmt.Errorf("%s: %w", obj.GetName(), ErrCollision)
+	}
+	copy := obj.DeepCopy()
+	if err := ClearClusterNs(copy, clusterNs); err != nil {
+		return err
+	}
+	*obj = *copy
+	return nil
+}
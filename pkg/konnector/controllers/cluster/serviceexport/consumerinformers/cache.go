@@ -0,0 +1,199 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumerinformers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	controllerName = "kube-bind-konnector-consumer-informers"
+)
+
+// Cache shares the informers of the consumer cluster between the syncers of
+// all APIServiceExports, and across restarts of a syncer. Informers are started
+// on first use and stopped when the last user releases them.
+type Cache struct {
+	lock    sync.Mutex
+	entries map[schema.GroupVersionResource]*entry
+
+	// newInformer creates the informer of a resource.
+	newInformer func(gvr schema.GroupVersionResource) informers.GenericInformer
+}
+
+type entry struct {
+	informer informers.GenericInformer
+	refs     int
+	cancel   func()
+}
+
+// NewCache returns an empty informer cache for the given consumer client.
+func NewCache(client dynamicclient.Interface) *Cache {
+	return &Cache{
+		entries: map[schema.GroupVersionResource]*entry{},
+		newInformer: func(gvr schema.GroupVersionResource) informers.GenericInformer {
+			return dynamicinformer.NewFilteredDynamicInformer(client, gvr, metav1.NamespaceAll, time.Minute*30, cache.Indexers{
+				cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+			}, nil)
+		},
+	}
+}
+
+// Acquire returns a handle of the informer of the given resource, starting it
+// if not running yet. The handle must be released when not used anymore.
+func (c *Cache) Acquire(gvr schema.GroupVersionResource) *Handle {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, found := c.entries[gvr]
+	if !found {
+		inf := c.newInformer(gvr)
+
+		logger := klog.Background().WithValues("controller", controllerName, "gvr", gvr)
+		logger.V(1).Info("starting shared consumer informer")
+		ctx, cancel := context.WithCancel(klog.NewContext(context.Background(), logger))
+		go inf.Informer().Run(ctx.Done())
+
+		e = &entry{informer: inf, cancel: cancel}
+		c.entries[gvr] = e
+	}
+	e.refs++
+
+	h := &Handle{cache: c, gvr: gvr, delegate: e.informer}
+	h.informer = &handleInformer{SharedIndexInformer: e.informer.Informer(), handle: h}
+	return h
+}
+
+func (c *Cache) release(gvr schema.GroupVersionResource) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, found := c.entries[gvr]
+	if !found {
+		return
+	}
+	e.refs--
+	if e.refs > 0 {
+		return
+	}
+
+	klog.Background().V(1).Info("stopping shared consumer informer", "controller", controllerName, "gvr", gvr)
+	e.cancel()
+	delete(c.entries, gvr)
+}
+
+// Handle is a reference to a shared informer. The event handlers added
+// through it are removed on release.
+type Handle struct {
+	cache    *Cache
+	gvr      schema.GroupVersionResource
+	delegate informers.GenericInformer
+	informer *handleInformer
+
+	lock          sync.Mutex
+	registrations []cache.ResourceEventHandlerRegistration
+	released      bool
+}
+
+var _ informers.GenericInformer = &Handle{}
+
+func (h *Handle) Informer() cache.SharedIndexInformer {
+	return h.informer
+}
+
+func (h *Handle) Lister() cache.GenericLister {
+	return h.delegate.Lister()
+}
+
+// Release removes the event handlers added through the handle and stops the
+// informer if this was the last reference. Further calls do nothing.
+func (h *Handle) Release() {
+	h.lock.Lock()
+	if h.released {
+		h.lock.Unlock()
+		return
+	}
+	h.released = true
+	registrations := h.registrations
+	h.registrations = nil
+	h.lock.Unlock()
+
+	for _, reg := range registrations {
+		if err := h.delegate.Informer().RemoveEventHandler(reg); err != nil {
+			utilruntime.HandleError(err)
+		}
+	}
+	h.cache.release(h.gvr)
+}
+
+func (h *Handle) track(reg cache.ResourceEventHandlerRegistration) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.registrations = append(h.registrations, reg)
+}
+
+func (h *Handle) untrack(registration cache.ResourceEventHandlerRegistration) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	for i, reg := range h.registrations {
+		if reg == registration {
+			h.registrations = append(h.registrations[:i], h.registrations[i+1:]...)
+			break
+		}
+	}
+}
+
+// handleInformer records the event handlers added to the shared informer,
+// such that the handle can remove them on release.
+type handleInformer struct {
+	cache.SharedIndexInformer
+	handle *Handle
+}
+
+func (i *handleInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	reg, err := i.SharedIndexInformer.AddEventHandler(handler)
+	if err != nil {
+		return nil, err
+	}
+	i.handle.track(reg)
+	return reg, nil
+}
+
+func (i *handleInformer) AddEventHandlerWithResyncPeriod(handler cache.ResourceEventHandler, resyncPeriod time.Duration) (cache.ResourceEventHandlerRegistration, error) {
+	reg, err := i.SharedIndexInformer.AddEventHandlerWithResyncPeriod(handler, resyncPeriod)
+	if err != nil {
+		return nil, err
+	}
+	i.handle.track(reg)
+	return reg, nil
+}
+
+func (i *handleInformer) RemoveEventHandler(registration cache.ResourceEventHandlerRegistration) error {
+	i.handle.untrack(registration)
+	return i.SharedIndexInformer.RemoveEventHandler(registration)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consumerinformers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeInformer struct {
+	cache.SharedIndexInformer
+
+	stopCh   <-chan struct{}
+	handlers map[*fakeRegistration]cache.ResourceEventHandler
+}
+
+type fakeRegistration struct {
+	id int
+}
+
+func (r *fakeRegistration) HasSynced() bool { return true }
+
+func (f *fakeInformer) Informer() cache.SharedIndexInformer { return f }
+func (f *fakeInformer) Lister() cache.GenericLister         { return nil }
+
+func (f *fakeInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	reg := &fakeRegistration{id: len(f.handlers)}
+	f.handlers[reg] = handler
+	return reg, nil
+}
+
+func (f *fakeInformer) RemoveEventHandler(registration cache.ResourceEventHandlerRegistration) error {
+	delete(f.handlers, registration.(*fakeRegistration))
+	return nil
+}
+
+func (f *fakeInformer) Run(stopCh <-chan struct{}) {}
+
+func TestCache(t *testing.T) {
+	var created []*fakeInformer
+	c := &Cache{
+		entries: map[schema.GroupVersionResource]*entry{},
+		newInformer: func(gvr schema.GroupVersionResource) informers.GenericInformer {
+			inf := &fakeInformer{handlers: map[*fakeRegistration]cache.ResourceEventHandler{}}
+			created = append(created, inf)
+			return inf
+		},
+	}
+
+	gvr := schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "foos"}
+	h1 := c.Acquire(gvr)
+	h2 := c.Acquire(gvr)
+	require.Len(t, created, 1, "informer should be shared")
+	inf := created[0]
+
+	_, err := h1.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{})
+	require.NoError(t, err)
+	reg, err := h2.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{})
+	require.NoError(t, err)
+	_, err = h2.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{})
+	require.NoError(t, err)
+	require.Len(t, inf.handlers, 3)
+	require.NoError(t, h2.Informer().RemoveEventHandler(reg))
+	require.Len(t, inf.handlers, 2)
+
+	h3 := c.Acquire(schema.GroupVersionResource{Version: "v1", Resource: "secrets"})
+	require.Len(t, created, 2, "different resources should not share informers")
+
+	h1.Release()
+	h1.Release()
+	require.Len(t, inf.handlers, 1, "handlers of the released handle should be removed")
+	require.Contains(t, c.entries, gvr, "informer should keep running while referenced")
+
+	h2.Release()
+	require.Empty(t, inf.handlers)
+	require.NotContains(t, c.entries, gvr, "informer should be stopped after the last release")
+
+	h4 := c.Acquire(gvr)
+	require.Len(t, created, 3, "informer should be recreated after it was stopped")
+
+	h3.Release()
+	h4.Release()
+	require.Empty(t, c.entries)
+}
@@ -0,0 +1,55 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package downstream
+
+import (
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// IsAdoptable returns true if the upstream object obj is labeled for adoption
+// and has never been synced, i.e. has no provider cluster annotation and has
+// not been orphaned.
+func IsAdoptable(obj metav1.Object) bool {
+	if obj.GetLabels()[kubebindv1alpha1.AdoptLabel] != "true" || IsOrphaned(obj) {
+		return false
+	}
+	_, found := obj.GetAnnotations()[konnectormodels.AnnotationProviderClusterID]
+	return !found
+}
+
+// NewAdopted returns the downstream object ns/name of the upstream object obj
+// of the given provider cluster. Everything but metadata and status is copied.
+// Labels and annotations are left to the sync policy of the caller.
+func NewAdopted(obj *unstructured.Unstructured, ns, name, clusterID string) *unstructured.Unstructured {
+	adopted := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	for k, v := range obj.Object {
+		if k != "metadata" && k != "status" {
+			adopted.Object[k] = runtime.DeepCopyJSONValue(v)
+		}
+	}
+	adopted.SetNamespace(ns)
+	adopted.SetName(name)
+	adopted.SetAnnotations(map[string]string{
+		konnectormodels.AnnotationProviderClusterID: clusterID,
+	})
+	return adopted
+}
@@ -0,0 +1,88 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package downstream
+
+import (
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestIsAdoptable(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		want        bool
+	}{
+		{name: "not labeled"},
+		{name: "labeled", labels: map[string]string{kubebindv1alpha1.AdoptLabel: "true"}, want: true},
+		{name: "labeled false", labels: map[string]string{kubebindv1alpha1.AdoptLabel: "false"}},
+		{
+			name:        "already synced",
+			labels:      map[string]string{kubebindv1alpha1.AdoptLabel: "true"},
+			annotations: map[string]string{konnectormodels.AnnotationProviderClusterID: "abc"},
+		},
+		{
+			name:   "orphaned",
+			labels: map[string]string{kubebindv1alpha1.AdoptLabel: "true", kubebindv1alpha1.OrphanedLabel: "true"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			obj := &unstructured.Unstructured{}
+			obj.SetLabels(tt.labels)
+			obj.SetAnnotations(tt.annotations)
+			require.Equal(t, tt.want, IsAdoptable(obj))
+		})
+	}
+}
+
+func TestNewAdopted(t *testing.T) {
+	upstream := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Foo",
+		"metadata": map[string]interface{}{
+			"namespace":       "kube-bind-abc-default",
+			"name":            "default-instance",
+			"resourceVersion": "42",
+			"labels":          map[string]interface{}{kubebindv1alpha1.AdoptLabel: "true"},
+		},
+		"spec":   map[string]interface{}{"size": "small"},
+		"status": map[string]interface{}{"phase": "Ready"},
+	}}
+
+	adopted := NewAdopted(upstream, "default", "default-instance", "abc")
+	require.Equal(t, &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"kind":       "Foo",
+		"metadata": map[string]interface{}{
+			"namespace":   "default",
+			"name":        "default-instance",
+			"annotations": map[string]interface{}{konnectormodels.AnnotationProviderClusterID: "abc"},
+		},
+		"spec": map[string]interface{}{"size": "small"},
+	}}, adopted)
+
+	// the upstream object is not shared
+	unstructured.SetNestedField(adopted.Object, "large", "spec", "size") // nolint:errcheck
+	require.Equal(t, "small", upstream.Object["spec"].(map[string]interface{})["size"])
+}
@@ -0,0 +1,231 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package downstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	conditionsapi "kmodules.xyz/client-go/api/v1"
+)
+
+// ConditionTypePrefix is the prefix of all condition types the konnector puts
+// on downstream objects. These conditions are preserved when the status is
+// copied from upstream.
+const ConditionTypePrefix = "kube-bind.appscode.com/"
+
+// ConditionsAnnotation holds the konnector conditions of downstream objects
+// without status subresource as JSON.
+const ConditionsAnnotation = "kube-bind.appscode.com/conditions"
+
+// GetConditions returns the conditions in status.conditions of obj.
+func GetConditions(obj *unstructured.Unstructured) (conditionsapi.Conditions, error) {
+	raw, found, err := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if err != nil || !found {
+		return nil, err
+	}
+	conditions := make(conditionsapi.Conditions, 0, len(raw))
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var c conditionsapi.Condition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &c); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+// SetConditions replaces status.conditions of obj.
+func SetConditions(obj *unstructured.Unstructured, conditions conditionsapi.Conditions) error {
+	if len(conditions) == 0 {
+		unstructured.RemoveNestedField(obj.Object, "status", "conditions")
+		return nil
+	}
+	raw := make([]interface{}, 0, len(conditions))
+	for i := range conditions {
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conditions[i])
+		if err != nil {
+			return err
+		}
+		raw = append(raw, m)
+	}
+	return unstructured.SetNestedSlice(obj.Object, raw, "status", "conditions")
+}
+
+// SetCondition sets the given condition in status.conditions of obj, keeping
+// the last transition time if the status did not change. It returns true if
+// obj was changed.
+func SetCondition(obj *unstructured.Unstructured, condition conditionsapi.Condition) (bool, error) {
+	conditions, err := GetConditions(obj)
+	if err != nil {
+		return false, err
+	}
+	conditions, changed := setCondition(conditions, condition)
+	if !changed {
+		return false, nil
+	}
+	return true, SetConditions(obj, conditions)
+}
+
+// RemoveCondition removes the condition of the given type from status.conditions
+// of obj. It returns true if obj was changed.
+func RemoveCondition(obj *unstructured.Unstructured, t conditionsapi.ConditionType) (bool, error) {
+	conditions, err := GetConditions(obj)
+	if err != nil {
+		return false, err
+	}
+	conditions, changed := removeCondition(conditions, t)
+	if !changed {
+		return false, nil
+	}
+	return true, SetConditions(obj, conditions)
+}
+
+// GetAnnotationConditions returns the conditions kept in the ConditionsAnnotation
+// of obj.
+func GetAnnotationConditions(obj *unstructured.Unstructured) (conditionsapi.Conditions, error) {
+	value, found := obj.GetAnnotations()[ConditionsAnnotation]
+	if !found || value == "" {
+		return nil, nil
+	}
+	var conditions conditionsapi.Conditions
+	if err := json.Unmarshal([]byte(value), &conditions); err != nil {
+		return nil, fmt.Errorf("failed to decode annotation %s: %w", ConditionsAnnotation, err)
+	}
+	return conditions, nil
+}
+
+// SetAnnotationConditions replaces the conditions kept in the
+// ConditionsAnnotation of obj.
+func SetAnnotationConditions(obj *unstructured.Unstructured, conditions conditionsapi.Conditions) error {
+	annotations := obj.GetAnnotations()
+	if len(conditions) == 0 {
+		delete(annotations, ConditionsAnnotation)
+		obj.SetAnnotations(annotations)
+		return nil
+	}
+	value, err := json.Marshal(conditions)
+	if err != nil {
+		return err
+	}
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ConditionsAnnotation] = string(value)
+	obj.SetAnnotations(annotations)
+	return nil
+}
+
+// SetAnnotationCondition is SetCondition for objects without status
+// subresource, keeping the conditions in the ConditionsAnnotation.
+func SetAnnotationCondition(obj *unstructured.Unstructured, condition conditionsapi.Condition) (bool, error) {
+	conditions, err := GetAnnotationConditions(obj)
+	if err != nil {
+		return false, err
+	}
+	conditions, changed := setCondition(conditions, condition)
+	if !changed {
+		return false, nil
+	}
+	return true, SetAnnotationConditions(obj, conditions)
+}
+
+// RemoveAnnotationCondition is RemoveCondition for objects without status
+// subresource, keeping the conditions in the ConditionsAnnotation.
+func RemoveAnnotationCondition(obj *unstructured.Unstructured, t conditionsapi.ConditionType) (bool, error) {
+	conditions, err := GetAnnotationConditions(obj)
+	if err != nil {
+		return false, err
+	}
+	conditions, changed := removeCondition(conditions, t)
+	if !changed {
+		return false, nil
+	}
+	return true, SetAnnotationConditions(obj, conditions)
+}
+
+func setCondition(conditions conditionsapi.Conditions, condition conditionsapi.Condition) (conditionsapi.Conditions, bool) {
+	for i, existing := range conditions {
+		if existing.Type != condition.Type {
+			continue
+		}
+		if existing.Status == condition.Status {
+			condition.LastTransitionTime = existing.LastTransitionTime
+		} else if condition.LastTransitionTime.IsZero() {
+			condition.LastTransitionTime = metav1.Now()
+		}
+		if reflect.DeepEqual(existing, condition) {
+			return conditions, false
+		}
+		conditions[i] = condition
+		return conditions, true
+	}
+
+	if condition.LastTransitionTime.IsZero() {
+		condition.LastTransitionTime = metav1.Now()
+	}
+	return append(conditions, condition), true
+}
+
+func removeCondition(conditions conditionsapi.Conditions, t conditionsapi.ConditionType) (conditionsapi.Conditions, bool) {
+	var remaining conditionsapi.Conditions
+	for _, c := range conditions {
+		if c.Type != t {
+			remaining = append(remaining, c)
+		}
+	}
+	return remaining, len(remaining) != len(conditions)
+}
+
+// IsPaused returns true if the sync of obj is paused by the
+// DownstreamPausedAnnotation. The deletion of obj is never paused, such that
+// its finalizer is removed and its deletion policy applied upstream.
+func IsPaused(obj metav1.Object) bool {
+	if ts := obj.GetDeletionTimestamp(); ts != nil && !ts.IsZero() {
+		return false
+	}
+	return obj.GetAnnotations()[kubebindv1alpha1.DownstreamPausedAnnotation] == "true"
+}
+
+// PreserveConditions copies the konnector conditions of from into to, replacing
+// conditions of the same type in to.
+func PreserveConditions(from, to *unstructured.Unstructured) error {
+	conditions, err := GetConditions(from)
+	if err != nil {
+		return err
+	}
+	for _, c := range conditions {
+		if !strings.HasPrefix(string(c.Type), ConditionTypePrefix) {
+			continue
+		}
+		if _, err := SetCondition(to, c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
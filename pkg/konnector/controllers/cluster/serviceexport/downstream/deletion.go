@@ -0,0 +1,78 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package downstream
+
+import (
+	"encoding/json"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// DeletionPolicy returns the deletion policy of obj, i.e. the value of the
+// DeletionPolicyAnnotation if valid, and defaultPolicy otherwise. An empty
+// defaultPolicy means Delete.
+func DeletionPolicy(obj metav1.Object, defaultPolicy kubebindv1alpha1.DeletionPolicy) kubebindv1alpha1.DeletionPolicy {
+	switch p := kubebindv1alpha1.DeletionPolicy(obj.GetAnnotations()[kubebindv1alpha1.DeletionPolicyAnnotation]); p {
+	case kubebindv1alpha1.DeletionPolicyDelete, kubebindv1alpha1.DeletionPolicyOrphan, kubebindv1alpha1.DeletionPolicyRetain:
+		return p
+	}
+	if defaultPolicy == "" {
+		return kubebindv1alpha1.DeletionPolicyDelete
+	}
+	return defaultPolicy
+}
+
+// IsOrphaned returns true if the upstream object obj has been orphaned.
+func IsOrphaned(obj metav1.Object) bool {
+	return obj.GetLabels()[kubebindv1alpha1.OrphanedLabel] == "true"
+}
+
+// OrphanPatch returns the merge patch detaching an upstream object from its
+// consumer: the provider cluster annotation is removed, such that the konnector
+// ignores the object, and it is tagged as orphaned at the given time.
+func OrphanPatch(now time.Time) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				kubebindv1alpha1.OrphanedLabel: "true",
+			},
+			"annotations": map[string]interface{}{
+				konnectormodels.AnnotationProviderClusterID: nil,
+				kubebindv1alpha1.OrphanedAtAnnotation:       now.UTC().Format(time.RFC3339),
+			},
+		},
+	})
+}
+
+// AdoptPatch returns the merge patch removing the orphan tags of an upstream
+// object that has a consumer object again.
+func AdoptPatch() ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"labels": map[string]interface{}{
+				kubebindv1alpha1.OrphanedLabel: nil,
+			},
+			"annotations": map[string]interface{}{
+				kubebindv1alpha1.OrphanedAtAnnotation: nil,
+			},
+		},
+	})
+}
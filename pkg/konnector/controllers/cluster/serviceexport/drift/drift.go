@@ -0,0 +1,123 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"sort"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/spec"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// maxObjects is the maximal number of objects listed per kind of drift.
+	maxObjects = 10
+
+	// staleFinalizerAge is how long a consumer object may be deleting before
+	// the kube-bind finalizer on it is considered stale.
+	staleFinalizerAge = 5 * time.Minute
+)
+
+// Detect returns the drift between the downstream and the upstream objects of
+// the provider cluster with the given UID. Both are keyed by the consumer side
+// namespace/name, or name if cluster-scoped. Specs are compared as synced by
+// the given policy.
+func Detect(clusterUID string, policy *fieldsync.Policy, downstream, upstream map[string]*unstructured.Unstructured, now time.Time) kubebindv1alpha1.ProviderDrift {
+	var missingUpstream, missingDownstream, specMismatch, staleFinalizers []string
+
+	for key, obj := range downstream {
+		if ts := obj.GetDeletionTimestamp(); ts != nil && !ts.IsZero() {
+			if hasFinalizer(obj) && now.Sub(ts.Time) > staleFinalizerAge {
+				staleFinalizers = append(staleFinalizers, key)
+			}
+			continue // the deletion is in progress
+		}
+
+		up, found := upstream[key]
+		if !found {
+			missingUpstream = append(missingUpstream, key)
+			continue
+		}
+		if desired, actual := spec.UpstreamSpecs(policy, obj, up); specHash(desired) != specHash(actual) {
+			specMismatch = append(specMismatch, key)
+		}
+	}
+	for key := range upstream {
+		if _, found := downstream[key]; !found {
+			missingDownstream = append(missingDownstream, key)
+		}
+	}
+
+	return kubebindv1alpha1.ProviderDrift{
+		ClusterUID:        clusterUID,
+		LastCheckTime:     metav1.NewTime(now),
+		MissingUpstream:   newDriftedObjects(missingUpstream),
+		MissingDownstream: newDriftedObjects(missingDownstream),
+		SpecMismatch:      newDriftedObjects(specMismatch),
+		StaleFinalizers:   newDriftedObjects(staleFinalizers),
+	}
+}
+
+// Equal returns true if a and b report the same drift, ignoring the check time.
+func Equal(a, b kubebindv1alpha1.ProviderDrift) bool {
+	a.LastCheckTime, b.LastCheckTime = metav1.Time{}, metav1.Time{}
+	x, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	y, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(x) == string(y)
+}
+
+func newDriftedObjects(keys []string) kubebindv1alpha1.DriftedObjects {
+	sort.Strings(keys)
+	objs := kubebindv1alpha1.DriftedObjects{Count: int32(len(keys))}
+	if len(keys) > maxObjects {
+		keys = keys[:maxObjects]
+	}
+	objs.Objects = keys
+	return objs
+}
+
+func hasFinalizer(obj *unstructured.Unstructured) bool {
+	for _, f := range obj.GetFinalizers() {
+		if f == kubebindv1alpha1.DownstreamFinalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// specHash returns the hash of the given spec. Map keys are marshalled in
+// sorted order, such that equal specs have equal hashes.
+func specHash(spec interface{}) [sha256.Size]byte {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return [sha256.Size]byte{}
+	}
+	return sha256.Sum256(data)
+}
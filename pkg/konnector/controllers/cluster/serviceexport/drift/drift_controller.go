@@ -0,0 +1,248 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"context"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamiclister"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	controllerName = "kube-bind-konnector-cluster-drift"
+
+	// checkInterval is how often the drift is computed.
+	checkInterval = time.Minute
+	// refreshInterval is how often an unchanged drift is written to the
+	// APIServiceBinding, to refresh the check time.
+	refreshInterval = 10 * time.Minute
+)
+
+// NewController returns a new controller periodically reporting the drift
+// between the downstream and the upstream objects in the status of the
+// APIServiceBinding. The isolator is nil for namespaced resources. The provider
+// informers of the synced objects are by provider cluster ID.
+func NewController(
+	bindingName string,
+	gvr schema.GroupVersionResource,
+	isolator clusterscoped.Isolator,
+	policy *fieldsync.Policy,
+	consumerConfig *rest.Config,
+	consumerDynamicInformer informers.GenericInformer,
+	objectInformers map[string]multinsinformer.GetterInformer,
+	providerInfos []*konnectormodels.ProviderInfo,
+) (*controller, error) {
+	consumerConfig = rest.CopyConfig(consumerConfig)
+	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+
+	bindClient, err := bindclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &controller{
+		bindingName:     bindingName,
+		isolator:        isolator,
+		policy:          policy,
+		bindClient:      bindClient,
+		consumerLister:  dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), gvr),
+		objectInformers: objectInformers,
+		providerInfos:   providerInfos,
+	}, nil
+}
+
+// controller reports the drift between downstream and upstream objects.
+type controller struct {
+	bindingName string
+	isolator    clusterscoped.Isolator
+	policy      *fieldsync.Policy
+
+	bindClient      bindclient.Interface
+	consumerLister  dynamiclister.Lister
+	objectInformers map[string]multinsinformer.GetterInformer
+	providerInfos   []*konnectormodels.ProviderInfo
+}
+
+// Start reports the drift periodically. It blocks until the context is done.
+// The informers must be synced.
+func (c *controller) Start(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName, "binding", c.bindingName)
+	ctx = klog.NewContext(ctx, logger)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := c.check(ctx); err != nil {
+			runtime.HandleError(err)
+		}
+	}, checkInterval)
+}
+
+// check computes the drift of all provider clusters and writes it to the
+// APIServiceBinding if changed or due for a refresh.
+func (c *controller) check(ctx context.Context) error {
+	now := time.Now()
+	drifts := make([]kubebindv1alpha1.ProviderDrift, 0, len(c.providerInfos))
+	for _, provider := range c.providerInfos {
+		downstream, err := c.downstreamObjects(provider)
+		if err != nil {
+			return err
+		}
+		upstream, err := c.upstreamObjects(provider)
+		if err != nil {
+			return err
+		}
+		drifts = append(drifts, Detect(provider.ClusterID, c.policy, downstream, upstream, now))
+	}
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		binding, err := c.bindClient.KubeBindV1alpha1().APIServiceBindings().Get(ctx, c.bindingName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if !mergeDrifts(&binding.Status, drifts, now) {
+			return nil
+		}
+		klog.FromContext(ctx).V(2).Info("Updating drift report")
+		_, err = c.bindClient.KubeBindV1alpha1().APIServiceBindings().UpdateStatus(ctx, binding, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// mergeDrifts replaces the drifts of the same provider clusters in status. A
+// drift is only replaced if changed or older than the refresh interval. It
+// returns true if status changed.
+func mergeDrifts(status *kubebindv1alpha1.APIServiceBindingStatus, drifts []kubebindv1alpha1.ProviderDrift, now time.Time) bool {
+	changed := false
+	for _, d := range drifts {
+		found := false
+		for i, existing := range status.Drift {
+			if existing.ClusterUID != d.ClusterUID {
+				continue
+			}
+			found = true
+			if !Equal(existing, d) || now.Sub(existing.LastCheckTime.Time) >= refreshInterval {
+				status.Drift[i] = d
+				changed = true
+			}
+			break
+		}
+		if !found {
+			status.Drift = append(status.Drift, d)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// downstreamObjects returns the downstream objects of the provider by key.
+func (c *controller) downstreamObjects(provider *konnectormodels.ProviderInfo) (map[string]*unstructured.Unstructured, error) {
+	objs, err := c.consumerLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	byKey := make(map[string]*unstructured.Unstructured, len(objs))
+	for _, obj := range objs {
+		clusterID, found := obj.GetAnnotations()[konnectormodels.AnnotationProviderClusterID]
+		if clusterID != provider.ClusterID && (found || len(c.providerInfos) != 1) {
+			continue
+		}
+		byKey[key(obj.GetNamespace(), obj.GetName())] = obj
+	}
+	return byKey, nil
+}
+
+// upstreamObjects returns the upstream objects synced with the consumer by
+// their downstream key.
+func (c *controller) upstreamObjects(provider *konnectormodels.ProviderInfo) (map[string]*unstructured.Unstructured, error) {
+	inf, found := c.objectInformers[provider.ClusterID]
+	if !found || inf == nil {
+		return nil, nil
+	}
+
+	byKey := map[string]*unstructured.Unstructured{}
+	if c.isolator != nil {
+		objs, err := inf.List(metav1.NamespaceAll)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range objs {
+			obj := o.(*unstructured.Unstructured)
+			if !konnectormodels.IsMatchProvider(provider, obj) {
+				continue
+			}
+			if name, ok := c.isolator.DownstreamName(obj.GetNamespace(), obj.GetName(), provider.Namespace); ok {
+				byKey[key("", name)] = obj
+			}
+		}
+		return byKey, nil
+	}
+
+	sns, err := provider.DynamicServiceNamespaceInformer.Lister().APIServiceNamespaces(provider.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	for _, sn := range sns {
+		if sn.Status.Namespace == "" {
+			continue
+		}
+		objs, err := inf.List(sn.Status.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, o := range objs {
+			obj := o.(*unstructured.Unstructured)
+			if obj.GetNamespace() != sn.Status.Namespace || !konnectormodels.IsMatchProvider(provider, obj) {
+				continue
+			}
+			byKey[key(sn.Name, obj.GetName())] = obj
+		}
+	}
+	return byKey, nil
+}
+
+func key(ns, name string) string {
+	if ns == "" {
+		return name
+	}
+	return ns + "/" + name
+}
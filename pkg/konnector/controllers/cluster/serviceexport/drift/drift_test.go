@@ -0,0 +1,241 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package drift
+
+import (
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newObject(name string, spec map[string]interface{}) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	obj.SetName(name)
+	if spec != nil {
+		obj.Object["spec"] = spec
+	}
+	return obj
+}
+
+func newPolicy(t *testing.T, spec *kubebindv1alpha1.APIServiceExportSpec) *fieldsync.Policy {
+	policy, err := fieldsync.NewPolicy(spec, nil)
+	require.NoError(t, err)
+	return policy
+}
+
+func TestDetect(t *testing.T) {
+	now := time.Now()
+	policy := newPolicy(t, &kubebindv1alpha1.APIServiceExportSpec{})
+
+	deleting := func(since time.Duration, finalizers ...string) *unstructured.Unstructured {
+		obj := newObject("deleting", nil)
+		obj.SetDeletionTimestamp(&metav1.Time{Time: now.Add(-since)})
+		obj.SetFinalizers(finalizers)
+		return obj
+	}
+
+	tests := []struct {
+		name       string
+		downstream map[string]*unstructured.Unstructured
+		upstream   map[string]*unstructured.Unstructured
+		want       kubebindv1alpha1.ProviderDrift
+	}{
+		{
+			name: "in sync",
+			downstream: map[string]*unstructured.Unstructured{
+				"default/foo": newObject("foo", map[string]interface{}{"a": "1", "b": "2"}),
+			},
+			upstream: map[string]*unstructured.Unstructured{
+				"default/foo": newObject("foo", map[string]interface{}{"b": "2", "a": "1"}),
+			},
+		},
+		{
+			name: "missing upstream and downstream",
+			downstream: map[string]*unstructured.Unstructured{
+				"default/foo": newObject("foo", nil),
+				"default/bar": newObject("bar", nil),
+			},
+			upstream: map[string]*unstructured.Unstructured{
+				"default/bar": newObject("bar", nil),
+				"default/baz": newObject("baz", nil),
+			},
+			want: kubebindv1alpha1.ProviderDrift{
+				MissingUpstream:   kubebindv1alpha1.DriftedObjects{Count: 1, Objects: []string{"default/foo"}},
+				MissingDownstream: kubebindv1alpha1.DriftedObjects{Count: 1, Objects: []string{"default/baz"}},
+			},
+		},
+		{
+			name: "spec mismatch",
+			downstream: map[string]*unstructured.Unstructured{
+				"foo": newObject("foo", map[string]interface{}{"a": "1"}),
+			},
+			upstream: map[string]*unstructured.Unstructured{
+				"foo": newObject("foo", map[string]interface{}{"a": "2"}),
+			},
+			want: kubebindv1alpha1.ProviderDrift{
+				SpecMismatch: kubebindv1alpha1.DriftedObjects{Count: 1, Objects: []string{"foo"}},
+			},
+		},
+		{
+			name: "stale finalizer",
+			downstream: map[string]*unstructured.Unstructured{
+				"default/deleting": deleting(10*time.Minute, kubebindv1alpha1.DownstreamFinalizer),
+			},
+			want: kubebindv1alpha1.ProviderDrift{
+				StaleFinalizers: kubebindv1alpha1.DriftedObjects{Count: 1, Objects: []string{"default/deleting"}},
+			},
+		},
+		{
+			name: "recent deletion",
+			downstream: map[string]*unstructured.Unstructured{
+				"default/deleting": deleting(time.Minute, kubebindv1alpha1.DownstreamFinalizer),
+			},
+		},
+		{
+			name: "deletion with foreign finalizer",
+			downstream: map[string]*unstructured.Unstructured{
+				"default/deleting": deleting(10*time.Minute, "example.com/finalizer"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.ClusterUID = "abc"
+			tt.want.LastCheckTime = metav1.NewTime(now)
+			require.Equal(t, tt.want, Detect("abc", policy, tt.downstream, tt.upstream, now))
+		})
+	}
+}
+
+func TestDetectLimitsObjects(t *testing.T) {
+	downstream := map[string]*unstructured.Unstructured{}
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l"} {
+		downstream[name] = newObject(name, nil)
+	}
+
+	got := Detect("abc", newPolicy(t, &kubebindv1alpha1.APIServiceExportSpec{}), downstream, nil, time.Now())
+	require.Equal(t, int32(12), got.MissingUpstream.Count)
+	require.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}, got.MissingUpstream.Objects)
+}
+
+func TestDetectComparesSyncedSpec(t *testing.T) {
+	now := time.Now()
+	policy := newPolicy(t, &kubebindv1alpha1.APIServiceExportSpec{
+		FieldSync: []kubebindv1alpha1.FieldSyncRule{
+			{Path: "spec.providerOnly", Direction: kubebindv1alpha1.SyncDirectionIgnore},
+		},
+		RelatedResources: []kubebindv1alpha1.RelatedResource{
+			{Path: "spec.configRef.name", Kind: "ConfigMap", Direction: kubebindv1alpha1.SyncDirectionToProvider},
+		},
+	})
+
+	namespaced := func(spec map[string]interface{}) *unstructured.Unstructured {
+		obj := newObject("foo", spec)
+		obj.SetNamespace("default")
+		return obj
+	}
+	withConsumerFields := func(obj *unstructured.Unstructured, fields string) *unstructured.Unstructured {
+		obj.SetManagedFields([]metav1.ManagedFieldsEntry{{
+			Manager:    "kubectl",
+			Operation:  metav1.ManagedFieldsOperationApply,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(fields)},
+		}})
+		return obj
+	}
+
+	tests := []struct {
+		name       string
+		downstream *unstructured.Unstructured
+		upstream   *unstructured.Unstructured
+		mismatch   bool
+	}{
+		{
+			name:       "reference rewritten to the copy",
+			downstream: namespaced(map[string]interface{}{"configRef": map[string]interface{}{"name": "config"}}),
+			upstream:   namespaced(map[string]interface{}{"configRef": map[string]interface{}{"name": "config-consumer"}}),
+		},
+		{
+			name:       "reference not rewritten upstream",
+			downstream: namespaced(map[string]interface{}{"configRef": map[string]interface{}{"name": "config"}}),
+			upstream:   namespaced(map[string]interface{}{"configRef": map[string]interface{}{"name": "config"}}),
+			mismatch:   true,
+		},
+		{
+			name:       "ignored field",
+			downstream: namespaced(map[string]interface{}{"a": "1", "providerOnly": "x"}),
+			upstream:   namespaced(map[string]interface{}{"a": "1", "providerOnly": "y"}),
+		},
+		{
+			name: "field not set by the consumer",
+			downstream: withConsumerFields(namespaced(map[string]interface{}{"a": "1", "defaulted": "x"}),
+				`{"f:spec":{"f:a":{}}}`),
+			upstream: namespaced(map[string]interface{}{"a": "1", "defaulted": "y"}),
+		},
+		{
+			name: "field set by the consumer",
+			downstream: withConsumerFields(namespaced(map[string]interface{}{"a": "1", "defaulted": "x"}),
+				`{"f:spec":{"f:a":{}}}`),
+			upstream: namespaced(map[string]interface{}{"a": "2", "defaulted": "x"}),
+			mismatch: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect("abc", policy,
+				map[string]*unstructured.Unstructured{"default/foo": tt.downstream},
+				map[string]*unstructured.Unstructured{"default/foo": tt.upstream},
+				now)
+			if tt.mismatch {
+				require.Equal(t, kubebindv1alpha1.DriftedObjects{Count: 1, Objects: []string{"default/foo"}}, got.SpecMismatch)
+			} else {
+				require.Equal(t, kubebindv1alpha1.DriftedObjects{}, got.SpecMismatch)
+			}
+		})
+	}
+}
+
+func TestMergeDrifts(t *testing.T) {
+	now := time.Now()
+	missing := kubebindv1alpha1.DriftedObjects{Count: 1, Objects: []string{"foo"}}
+
+	status := &kubebindv1alpha1.APIServiceBindingStatus{}
+	require.True(t, mergeDrifts(status, []kubebindv1alpha1.ProviderDrift{{ClusterUID: "abc", LastCheckTime: metav1.NewTime(now), MissingUpstream: missing}}, now))
+	require.Len(t, status.Drift, 1)
+
+	// unchanged drift is not rewritten before the refresh interval
+	later := now.Add(time.Minute)
+	require.False(t, mergeDrifts(status, []kubebindv1alpha1.ProviderDrift{{ClusterUID: "abc", LastCheckTime: metav1.NewTime(later), MissingUpstream: missing}}, later))
+	require.Equal(t, metav1.NewTime(now), status.Drift[0].LastCheckTime)
+
+	// but after
+	later = now.Add(refreshInterval)
+	require.True(t, mergeDrifts(status, []kubebindv1alpha1.ProviderDrift{{ClusterUID: "abc", LastCheckTime: metav1.NewTime(later), MissingUpstream: missing}}, later))
+	require.Equal(t, metav1.NewTime(later), status.Drift[0].LastCheckTime)
+
+	// changed drift is written right away, and other providers are kept
+	require.True(t, mergeDrifts(status, []kubebindv1alpha1.ProviderDrift{{ClusterUID: "def", LastCheckTime: metav1.NewTime(later)}}, later))
+	require.Len(t, status.Drift, 2)
+	require.True(t, mergeDrifts(status, []kubebindv1alpha1.ProviderDrift{{ClusterUID: "abc", LastCheckTime: metav1.NewTime(later)}}, later))
+	require.Equal(t, kubebindv1alpha1.DriftedObjects{}, status.Drift[0].MissingUpstream)
+}
@@ -0,0 +1,278 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/indexers"
+	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8sruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/dynamic/dynamiclister"
+	"k8s.io/client-go/informers"
+	kubernetesclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog/v2"
+)
+
+const (
+	controllerName = "kube-bind-konnector-cluster-event"
+)
+
+// EventsGVR is the resource of the upstream events watched by the controller.
+var EventsGVR = corev1.SchemeGroupVersion.WithResource("events")
+
+// NewController returns a new controller mirroring upstream events of synced
+// objects onto the downstream objects. The event informers and the informers of
+// the synced objects are by provider cluster ID. The isolator is nil for namespaced resources.
+// The queue name identifies the export in the queue metrics.
+func NewController(
+	gvr schema.GroupVersionResource,
+	kind string,
+	isolator clusterscoped.Isolator,
+	consumerConfig *rest.Config,
+	consumerDynamicInformer informers.GenericInformer,
+	eventInformers map[string]multinsinformer.GetterInformer,
+	objectInformers map[string]multinsinformer.GetterInformer,
+	queueName string,
+	rateLimiter workqueue.RateLimiter,
+	providerInfos []*konnectormodels.ProviderInfo,
+) (*controller, error) {
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter, controllerName+"-"+queueName)
+
+	logger := klog.Background().WithValues("controller", controllerName)
+
+	consumerConfig = rest.CopyConfig(consumerConfig)
+	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+
+	consumerKubeClient, err := kubernetesclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	// the broadcaster aggregates similar events and rate limits them per object
+	broadcaster := record.NewBroadcaster()
+
+	dynamicConsumerLister := dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), gvr)
+	c := &controller{
+		queue: queue,
+
+		consumerKubeClient: consumerKubeClient,
+		broadcaster:        broadcaster,
+
+		eventInformers: eventInformers,
+		providerInfos:  providerInfos,
+
+		reconciler: reconciler{
+			isolator: isolator,
+			gk:       schema.GroupKind{Group: gvr.Group, Kind: kind},
+
+			mirrored: map[types.UID]int32{},
+
+			recorder: broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: controllerName}),
+
+			getUpstreamObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+				inf, found := objectInformers[provider.ClusterID]
+				if !found || inf == nil {
+					return nil, errors.NewNotFound(gvr.GroupResource(), name)
+				}
+				obj, err := inf.Get(ns, name)
+				if err != nil {
+					return nil, err
+				}
+				return obj.(*unstructured.Unstructured), nil
+			},
+			getServiceNamespace: func(provider *konnectormodels.ProviderInfo, upstreamNamespace string) (*v1alpha1.APIServiceNamespace, error) {
+				sns, err := provider.DynamicServiceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, upstreamNamespace)
+				if err != nil {
+					return nil, err
+				}
+				if len(sns) == 0 {
+					return nil, errors.NewNotFound(v1alpha1.SchemeGroupVersion.WithResource("APIServiceNamespace").GroupResource(), upstreamNamespace)
+				}
+				return sns[0].(*v1alpha1.APIServiceNamespace), nil
+			},
+			getConsumerObject: func(ns, name string) (*unstructured.Unstructured, error) {
+				if ns != "" {
+					return dynamicConsumerLister.Namespace(ns).Get(name)
+				}
+				return dynamicConsumerLister.Get(name)
+			},
+		},
+	}
+
+	for _, provider := range providerInfos {
+		inf, found := eventInformers[provider.ClusterID]
+		if !found {
+			continue
+		}
+		inf.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueProvider(logger, provider, obj)
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				c.enqueueProvider(logger, provider, newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				if event, ok := obj.(*unstructured.Unstructured); ok {
+					c.forget(event.GetUID())
+				}
+			},
+		})
+	}
+
+	return c, nil
+}
+
+// controller mirrors upstream events onto downstream objects.
+type controller struct {
+	queue workqueue.RateLimitingInterface
+
+	consumerKubeClient kubernetesclient.Interface
+	broadcaster        record.EventBroadcaster
+
+	eventInformers map[string]multinsinformer.GetterInformer
+	providerInfos  []*konnectormodels.ProviderInfo
+
+	reconciler
+}
+
+func (c *controller) enqueueProvider(logger klog.Logger, provider *konnectormodels.ProviderInfo, obj interface{}) {
+	event, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	if kind, _, _ := unstructured.NestedString(event.Object, "involvedObject", "kind"); kind != c.gk.Kind {
+		return
+	}
+
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	logger.V(3).Info("queueing Event", "key", key)
+	c.queue.Add(provider.ClusterID + "/" + key)
+}
+
+// Start starts the controller, which stops when ctx.Done() is closed.
+func (c *controller) Start(ctx context.Context, numThreads int) {
+	defer runtime.HandleCrash()
+	defer c.queue.ShutDown()
+
+	logger := klog.FromContext(ctx).WithValues("controller", controllerName)
+
+	logger.Info("Starting controller")
+	defer logger.Info("Shutting down controller")
+
+	c.startTime = time.Now()
+	c.broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: c.consumerKubeClient.CoreV1().Events("")})
+	defer c.broadcaster.Shutdown()
+
+	for i := 0; i < numThreads; i++ {
+		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
+	}
+
+	<-ctx.Done()
+}
+
+func (c *controller) startWorker(ctx context.Context) {
+	defer runtime.HandleCrash()
+
+	for c.processNextWorkItem(ctx) {
+	}
+}
+
+func (c *controller) processNextWorkItem(ctx context.Context) bool {
+	// Wait until there is a new item in the working queue
+	k, quit := c.queue.Get()
+	if quit {
+		return false
+	}
+	key := k.(string)
+
+	logger := klog.FromContext(ctx).WithValues("key", key)
+	ctx = klog.NewContext(ctx, logger)
+	logger.V(3).Info("processing key")
+
+	// No matter what, tell the queue we're done with this key, to unblock
+	// other workers.
+	defer c.queue.Done(key)
+
+	if err := c.process(ctx, key); err != nil {
+		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
+		c.queue.AddRateLimited(key)
+		return true
+	}
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *controller) process(ctx context.Context, key string) error {
+	parts := strings.Split(key, "/")
+	if len(parts) != 3 {
+		runtime.HandleError(fmt.Errorf("unexpected key format: %q", key))
+		return nil // we cannot do anything
+	}
+	clusterID, ns, name := parts[0], parts[1], parts[2]
+
+	provider, err := konnectormodels.GetProviderInfoWithClusterID(c.providerInfos, clusterID)
+	if err != nil {
+		return err
+	}
+	inf, found := c.eventInformers[clusterID]
+	if !found {
+		return nil // we cannot do anything
+	}
+
+	obj, err := inf.Get(ns, name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		return nil // deleted in the meantime
+	}
+
+	var event corev1.Event
+	if err := k8sruntime.DefaultUnstructuredConverter.FromUnstructured(obj.(*unstructured.Unstructured).Object, &event); err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	}
+
+	return c.reconcile(ctx, provider, &event)
+}
@@ -0,0 +1,155 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+)
+
+type reconciler struct {
+	isolator clusterscoped.Isolator
+	gk       schema.GroupKind
+
+	// startTime is when the controller started. Events last observed before
+	// are not mirrored again, e.g. after a konnector restart.
+	startTime time.Time
+
+	lock sync.Mutex
+	// mirrored holds the count of upstream events already mirrored, by event UID.
+	mirrored map[types.UID]int32
+
+	recorder record.EventRecorder
+
+	getUpstreamObject   func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error)
+	getServiceNamespace func(provider *konnectormodels.ProviderInfo, upstreamNamespace string) (*v1alpha1.APIServiceNamespace, error)
+	getConsumerObject   func(ns, name string) (*unstructured.Unstructured, error)
+}
+
+// reconcile re-emits an upstream event against the downstream object of the
+// upstream object it is about. Every occurrence of an event is mirrored at most
+// once.
+func (r *reconciler) reconcile(ctx context.Context, provider *konnectormodels.ProviderInfo, event *corev1.Event) error {
+	logger := klog.FromContext(ctx)
+
+	involved := event.InvolvedObject
+	if involved.Kind != r.gk.Kind || schema.FromAPIVersionAndKind(involved.APIVersion, involved.Kind).Group != r.gk.Group {
+		return nil // not about a synced object
+	}
+
+	count := eventCount(event)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.mirrored[event.UID] >= count {
+		return nil // mirrored already
+	}
+	if lastObserved(event).Before(r.startTime) {
+		r.mirrored[event.UID] = count
+		return nil
+	}
+
+	upstream, err := r.getUpstreamObject(provider, involved.Namespace, involved.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		logger.V(2).Info("skipping event of unknown upstream object")
+		return nil
+	}
+	if involved.UID != "" && upstream.GetUID() != involved.UID {
+		logger.V(2).Info("skipping event of former upstream object")
+		return nil
+	}
+
+	ns, name := involved.Namespace, involved.Name
+	if r.isolator != nil {
+		downstreamName, ok := r.isolator.DownstreamName(ns, name, provider.Namespace)
+		if !ok || !clusterscoped.IsOwnedBy(upstream, provider.Namespace) {
+			logger.V(2).Info("skipping event of another consumer")
+			return nil
+		}
+		ns, name = "", downstreamName
+	} else {
+		sn, err := r.getServiceNamespace(provider, ns)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		} else if errors.IsNotFound(err) || sn.Namespace != provider.Namespace {
+			logger.V(2).Info("skipping event of another consumer")
+			return nil
+		}
+		ns = sn.Name
+	}
+
+	downstream, err := r.getConsumerObject(ns, name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		logger.V(2).Info("skipping event of upstream object without downstream object", "downstreamNamespace", ns, "downstreamName", name)
+		return nil
+	}
+
+	logger.V(2).Info("mirroring event", "reason", event.Reason, "downstreamNamespace", ns, "downstreamName", name)
+	r.recorder.AnnotatedEventf(downstream, map[string]string{
+		konnectormodels.AnnotationProviderClusterID: provider.ClusterID,
+	}, event.Type, event.Reason, "%s", event.Message)
+	r.mirrored[event.UID] = count
+
+	return nil
+}
+
+// forget drops the bookkeeping of a deleted upstream event.
+func (r *reconciler) forget(uid types.UID) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.mirrored, uid)
+}
+
+// eventCount returns how often the event has been observed.
+func eventCount(event *corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > 0 {
+		return event.Series.Count
+	}
+	if event.Count > 0 {
+		return event.Count
+	}
+	return 1
+}
+
+// lastObserved returns when the event has been observed last.
+func lastObserved(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil && !event.Series.LastObservedTime.IsZero():
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
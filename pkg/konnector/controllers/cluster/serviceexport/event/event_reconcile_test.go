@@ -0,0 +1,148 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+)
+
+const mirroredEvent = "Warning Failed provisioning failed map[provider.kube-bind.appscode.com/cluster-id:abc]"
+
+func TestReconcile(t *testing.T) {
+	start := time.Now()
+	tests := []struct {
+		name     string
+		events   []*corev1.Event
+		upstream *unstructured.Unstructured
+		expected []string
+	}{
+		{
+			name:     "mirrored",
+			events:   []*corev1.Event{newEvent("e1", "Failed", 1, start.Add(time.Second))},
+			upstream: newUpstream("foo", "uid-foo"),
+			expected: []string{mirroredEvent},
+		},
+		{
+			name: "deduplicated",
+			events: []*corev1.Event{
+				newEvent("e1", "Failed", 1, start.Add(time.Second)),
+				newEvent("e1", "Failed", 1, start.Add(time.Second)),
+				newEvent("e1", "Failed", 2, start.Add(2*time.Second)),
+			},
+			upstream: newUpstream("foo", "uid-foo"),
+			expected: []string{mirroredEvent, mirroredEvent},
+		},
+		{
+			name: "observed before start",
+			events: []*corev1.Event{
+				newEvent("e1", "Failed", 1, start.Add(-time.Hour)),
+				newEvent("e1", "Failed", 1, start.Add(-time.Hour)),
+			},
+			upstream: newUpstream("foo", "uid-foo"),
+		},
+		{
+			name:     "former upstream object",
+			events:   []*corev1.Event{newEvent("e1", "Failed", 1, start.Add(time.Second))},
+			upstream: newUpstream("foo", "uid-other"),
+		},
+		{
+			name:   "no upstream object",
+			events: []*corev1.Event{newEvent("e1", "Failed", 1, start.Add(time.Second))},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &reconciler{
+				gk:        schema.GroupKind{Group: "example.com", Kind: "Foo"},
+				startTime: start,
+				mirrored:  map[types.UID]int32{},
+				recorder:  recorder,
+				getUpstreamObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+					if tt.upstream == nil || tt.upstream.GetName() != name {
+						return nil, errors.NewNotFound(schema.GroupResource{Group: "example.com", Resource: "foos"}, name)
+					}
+					return tt.upstream, nil
+				},
+				getServiceNamespace: func(provider *konnectormodels.ProviderInfo, upstreamNamespace string) (*v1alpha1.APIServiceNamespace, error) {
+					return &v1alpha1.APIServiceNamespace{
+						ObjectMeta: metav1.ObjectMeta{Namespace: provider.Namespace, Name: "default"},
+						Status:     v1alpha1.APIServiceNamespaceStatus{Namespace: upstreamNamespace},
+					}, nil
+				},
+				getConsumerObject: func(ns, name string) (*unstructured.Unstructured, error) {
+					obj := newUpstream(name, "uid-downstream")
+					obj.SetNamespace(ns)
+					return obj, nil
+				},
+			}
+			provider := &konnectormodels.ProviderInfo{Namespace: "cluster-abc", ClusterID: "abc"}
+			for _, event := range tt.events {
+				err := r.reconcile(context.Background(), provider, event)
+				require.NoError(t, err)
+			}
+			close(recorder.Events)
+			var got []string
+			for e := range recorder.Events {
+				got = append(got, e)
+			}
+			require.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func newEvent(uid, reason string, count int32, last time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind-abc-default", Name: "foo." + uid, UID: types.UID(uid)},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion: "example.com/v1",
+			Kind:       "Foo",
+			Namespace:  "kube-bind-abc-default",
+			Name:       "foo",
+			UID:        "uid-foo",
+		},
+		Type:          corev1.EventTypeWarning,
+		Reason:        reason,
+		Message:       "provisioning failed",
+		Count:         count,
+		LastTimestamp: metav1.NewTime(last),
+	}
+}
+
+func newUpstream(name, uid string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("example.com/v1")
+	obj.SetKind("Foo")
+	obj.SetNamespace("kube-bind-abc-default")
+	obj.SetName(name)
+	obj.SetUID(types.UID(uid))
+	return obj
+}
@@ -0,0 +1,179 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldsync
+
+import (
+	"fmt"
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Policy decides in which direction the fields, labels and annotations of
+// service objects are synced. By default, spec is synced to the provider, status
+// to the consumer, and all other fields are not synced. Rules override the
+// direction of a field and its children, the rule with the longest path winning.
+type Policy struct {
+	root       *node
+	metadata   metadataPolicy
+	references []Reference
+}
+
+type node struct {
+	direction kubebindv1alpha1.SyncDirection
+	children  map[string]*node
+}
+
+// NewPolicy returns the Policy of the given APIServiceExport spec. Its label and
+// annotation filters take precedence over the given defaults.
+func NewPolicy(spec *kubebindv1alpha1.APIServiceExportSpec, defaults *kubebindv1alpha1.MetadataSync) (*Policy, error) {
+	root := &node{
+		direction: kubebindv1alpha1.SyncDirectionIgnore,
+		children: map[string]*node{
+			"spec":   {direction: kubebindv1alpha1.SyncDirectionToProvider},
+			"status": {direction: kubebindv1alpha1.SyncDirectionToConsumer},
+		},
+	}
+
+	for _, rule := range spec.FieldSync {
+		switch rule.Direction {
+		case kubebindv1alpha1.SyncDirectionToProvider, kubebindv1alpha1.SyncDirectionToConsumer, kubebindv1alpha1.SyncDirectionIgnore:
+		default:
+			return nil, fmt.Errorf("invalid direction %q for field %q", rule.Direction, rule.Path)
+		}
+
+		path := strings.Split(strings.TrimPrefix(rule.Path, "."), ".")
+		switch path[0] {
+		case "", "metadata", "apiVersion", "kind":
+			return nil, fmt.Errorf("field %q cannot be synced", rule.Path)
+		}
+
+		n := root
+		for _, name := range path {
+			if name == "" {
+				return nil, fmt.Errorf("invalid field path %q", rule.Path)
+			}
+			child, found := n.children[name]
+			if !found {
+				if n.children == nil {
+					n.children = map[string]*node{}
+				}
+				child = &node{}
+				n.children[name] = child
+			}
+			n = child
+		}
+		n.direction = rule.Direction
+	}
+
+	root.inherit(root.direction)
+
+	p := &Policy{
+		root:     root,
+		metadata: newMetadataPolicy(defaults, spec.MetadataSync),
+	}
+	var err error
+	if p.references, err = newReferences(spec.RelatedResources, p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// inherit sets the direction of all nodes without a rule of their own to the
+// direction of their parent.
+func (n *node) inherit(direction kubebindv1alpha1.SyncDirection) {
+	if n.direction == "" {
+		n.direction = direction
+	}
+	for _, child := range n.children {
+		child.inherit(n.direction)
+	}
+}
+
+// uniform returns true if n and all its children are synced in the same direction.
+func (n *node) uniform() bool {
+	for _, child := range n.children {
+		if child.direction != n.direction || !child.uniform() {
+			return false
+		}
+	}
+	return true
+}
+
+// Direction returns the direction the field with the given path is synced in.
+func (p *Policy) Direction(path ...string) kubebindv1alpha1.SyncDirection {
+	n := p.root
+	for _, name := range path {
+		child, found := n.children[name]
+		if !found {
+			break
+		}
+		n = child
+	}
+	return n.direction
+}
+
+// Merge overwrites the fields of dst that are synced in the given direction with
+// those of src. Fields missing in src are removed from dst. All other fields of
+// dst are kept.
+func (p *Policy) Merge(dst, src map[string]interface{}, direction kubebindv1alpha1.SyncDirection) {
+	merge(p.root, dst, src, direction)
+}
+
+func merge(n *node, dst, src map[string]interface{}, direction kubebindv1alpha1.SyncDirection) {
+	keys := map[string]bool{}
+	for k := range dst {
+		keys[k] = true
+	}
+	for k := range src {
+		keys[k] = true
+	}
+
+	for k := range keys {
+		child, found := n.children[k]
+		if !found {
+			child = &node{direction: n.direction}
+		}
+
+		if child.uniform() {
+			if child.direction != direction {
+				continue
+			}
+			if v, found := src[k]; found {
+				dst[k] = runtime.DeepCopyJSONValue(v)
+			} else {
+				delete(dst, k)
+			}
+			continue
+		}
+
+		// mixed directions below, descend into objects.
+		srcChild, _ := src[k].(map[string]interface{})
+		dstChild, ok := dst[k].(map[string]interface{})
+		if !ok {
+			dstChild = map[string]interface{}{}
+		}
+		merge(child, dstChild, srcChild, direction)
+		if _, found := src[k]; len(dstChild) == 0 && !found {
+			delete(dst, k)
+		} else {
+			dst[k] = dstChild
+		}
+	}
+}
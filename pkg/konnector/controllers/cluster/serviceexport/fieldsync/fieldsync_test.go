@@ -0,0 +1,159 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldsync
+
+import (
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestMerge(t *testing.T) {
+	consumer := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"metadata":   map[string]interface{}{"name": "foo"},
+		"spec": map[string]interface{}{
+			"size":     int64(3),
+			"endpoint": "stale",
+			"internal": map[string]interface{}{"debug": true},
+		},
+		"status": map[string]interface{}{
+			"phase": "Pending",
+			"note":  "consumer note",
+		},
+	}
+	provider := map[string]interface{}{
+		"apiVersion": "example.com/v1",
+		"metadata":   map[string]interface{}{"name": "kube-bind-zlp9m-foo"},
+		"spec": map[string]interface{}{
+			"size":     int64(2),
+			"endpoint": "https://foo.example.com",
+		},
+		"status": map[string]interface{}{
+			"phase": "Ready",
+		},
+	}
+
+	tests := []struct {
+		name      string
+		rules     []kubebindv1alpha1.FieldSyncRule
+		direction kubebindv1alpha1.SyncDirection
+		dst       map[string]interface{}
+		src       map[string]interface{}
+		expected  map[string]interface{}
+	}{
+		{
+			name:      "default to provider",
+			direction: kubebindv1alpha1.SyncDirectionToProvider,
+			dst:       map[string]interface{}{},
+			src:       consumer,
+			expected: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"size":     int64(3),
+					"endpoint": "stale",
+					"internal": map[string]interface{}{"debug": true},
+				},
+			},
+		},
+		{
+			name:      "default to consumer",
+			direction: kubebindv1alpha1.SyncDirectionToConsumer,
+			dst:       map[string]interface{}{"spec": map[string]interface{}{"size": int64(3)}, "status": map[string]interface{}{"note": "consumer note"}},
+			src:       provider,
+			expected: map[string]interface{}{
+				"spec":   map[string]interface{}{"size": int64(3)},
+				"status": map[string]interface{}{"phase": "Ready"},
+			},
+		},
+		{
+			name: "rules to provider",
+			rules: []kubebindv1alpha1.FieldSyncRule{
+				{Path: "spec.endpoint", Direction: kubebindv1alpha1.SyncDirectionToConsumer},
+				{Path: ".spec.internal", Direction: kubebindv1alpha1.SyncDirectionIgnore},
+				{Path: "status.note", Direction: kubebindv1alpha1.SyncDirectionToProvider},
+			},
+			direction: kubebindv1alpha1.SyncDirectionToProvider,
+			dst:       map[string]interface{}{},
+			src:       consumer,
+			expected: map[string]interface{}{
+				"spec":   map[string]interface{}{"size": int64(3)},
+				"status": map[string]interface{}{"note": "consumer note"},
+			},
+		},
+		{
+			name: "rules to consumer",
+			rules: []kubebindv1alpha1.FieldSyncRule{
+				{Path: "spec.endpoint", Direction: kubebindv1alpha1.SyncDirectionToConsumer},
+				{Path: ".spec.internal", Direction: kubebindv1alpha1.SyncDirectionIgnore},
+				{Path: "status.note", Direction: kubebindv1alpha1.SyncDirectionToProvider},
+			},
+			direction: kubebindv1alpha1.SyncDirectionToConsumer,
+			dst:       consumer,
+			src:       provider,
+			expected: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"metadata":   map[string]interface{}{"name": "foo"},
+				"spec": map[string]interface{}{
+					"size":     int64(3),
+					"endpoint": "https://foo.example.com",
+					"internal": map[string]interface{}{"debug": true},
+				},
+				"status": map[string]interface{}{
+					"phase": "Ready",
+					"note":  "consumer note",
+				},
+			},
+		},
+		{
+			name: "nested override",
+			rules: []kubebindv1alpha1.FieldSyncRule{
+				{Path: "spec", Direction: kubebindv1alpha1.SyncDirectionIgnore},
+				{Path: "spec.size", Direction: kubebindv1alpha1.SyncDirectionToProvider},
+			},
+			direction: kubebindv1alpha1.SyncDirectionToProvider,
+			dst:       map[string]interface{}{},
+			src:       consumer,
+			expected: map[string]interface{}{
+				"spec": map[string]interface{}{"size": int64(3)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewPolicy(&kubebindv1alpha1.APIServiceExportSpec{FieldSync: tt.rules}, nil)
+			require.NoError(t, err)
+
+			dst := runtime.DeepCopyJSON(tt.dst)
+			policy.Merge(dst, tt.src, tt.direction)
+			require.Equal(t, tt.expected, dst)
+		})
+	}
+}
+
+func TestNewPolicyInvalid(t *testing.T) {
+	for _, rule := range []kubebindv1alpha1.FieldSyncRule{
+		{Path: "metadata.labels", Direction: kubebindv1alpha1.SyncDirectionToProvider},
+		{Path: "spec..foo", Direction: kubebindv1alpha1.SyncDirectionToProvider},
+		{Path: "spec.foo", Direction: "sideways"},
+	} {
+		_, err := NewPolicy(&kubebindv1alpha1.APIServiceExportSpec{FieldSync: []kubebindv1alpha1.FieldSyncRule{rule}}, nil)
+		require.Error(t, err, rule.Path)
+	}
+}
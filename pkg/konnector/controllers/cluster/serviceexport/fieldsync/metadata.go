@@ -0,0 +1,146 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldsync
+
+import (
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// internalPrefixes are the key prefixes of labels and annotations the konnector
+// maintains itself. They are never synced to the consumer.
+var internalPrefixes = []string{
+	"kube-bind.appscode.com/",
+	"provider.kube-bind.appscode.com/",
+}
+
+// metadataPolicy holds the effective label and annotation filters.
+type metadataPolicy struct {
+	labelsToProvider, annotationsToProvider *kubebindv1alpha1.PrefixFilter
+	labelsToConsumer, annotationsToConsumer *kubebindv1alpha1.PrefixFilter
+}
+
+func newMetadataPolicy(defaults, overrides *kubebindv1alpha1.MetadataSync) metadataPolicy {
+	var p metadataPolicy
+	for _, ms := range []*kubebindv1alpha1.MetadataSync{defaults, overrides} {
+		if ms == nil {
+			continue
+		}
+		if f := ms.ToProvider; f != nil {
+			if f.Labels != nil {
+				p.labelsToProvider = f.Labels
+			}
+			if f.Annotations != nil {
+				p.annotationsToProvider = f.Annotations
+			}
+		}
+		if f := ms.ToConsumer; f != nil {
+			if f.Labels != nil {
+				p.labelsToConsumer = f.Labels
+			}
+			if f.Annotations != nil {
+				p.annotationsToConsumer = f.Annotations
+			}
+		}
+	}
+	return p
+}
+
+// matches returns true if the filter selects the given key. A nil filter
+// selects nothing.
+func matches(f *kubebindv1alpha1.PrefixFilter, key string) bool {
+	if f == nil {
+		return false
+	}
+	return hasPrefix(key, f.Include) && !hasPrefix(key, f.Exclude)
+}
+
+func hasPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if prefix == "*" || strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterLabels returns the labels that are synced in the given direction.
+func (p *Policy) FilterLabels(labels map[string]string, direction kubebindv1alpha1.SyncDirection) map[string]string {
+	return filterKeys(labels, p.metadata.labelsToProvider, p.metadata.labelsToConsumer, direction)
+}
+
+// FilterAnnotations returns the annotations that are synced in the given direction.
+func (p *Policy) FilterAnnotations(annotations map[string]string, direction kubebindv1alpha1.SyncDirection) map[string]string {
+	return filterKeys(annotations, p.metadata.annotationsToProvider, p.metadata.annotationsToConsumer, direction)
+}
+
+func filterKeys(m map[string]string, toProvider, toConsumer *kubebindv1alpha1.PrefixFilter, direction kubebindv1alpha1.SyncDirection) map[string]string {
+	var filtered map[string]string
+	for k, v := range m {
+		if !selected(k, toProvider, toConsumer, direction) {
+			continue
+		}
+		if filtered == nil {
+			filtered = map[string]string{}
+		}
+		filtered[k] = v
+	}
+	return filtered
+}
+
+func selected(key string, toProvider, toConsumer *kubebindv1alpha1.PrefixFilter, direction kubebindv1alpha1.SyncDirection) bool {
+	if hasPrefix(key, internalPrefixes) {
+		return false
+	}
+	switch direction {
+	case kubebindv1alpha1.SyncDirectionToProvider:
+		// keys synced to the consumer are owned by the provider
+		return matches(toProvider, key) && !matches(toConsumer, key)
+	case kubebindv1alpha1.SyncDirectionToConsumer:
+		return matches(toConsumer, key)
+	}
+	return false
+}
+
+// MergeMetadata overwrites the labels and annotations of dst that are synced in
+// the given direction with those of src. Selected keys missing in src are
+// removed from dst.
+func (p *Policy) MergeMetadata(dst, src *unstructured.Unstructured, direction kubebindv1alpha1.SyncDirection) {
+	dst.SetLabels(mergeKeys(dst.GetLabels(), src.GetLabels(), p.metadata.labelsToProvider, p.metadata.labelsToConsumer, direction))
+	dst.SetAnnotations(mergeKeys(dst.GetAnnotations(), src.GetAnnotations(), p.metadata.annotationsToProvider, p.metadata.annotationsToConsumer, direction))
+}
+
+func mergeKeys(dst, src map[string]string, toProvider, toConsumer *kubebindv1alpha1.PrefixFilter, direction kubebindv1alpha1.SyncDirection) map[string]string {
+	merged := make(map[string]string, len(dst))
+	for k, v := range dst {
+		if !selected(k, toProvider, toConsumer, direction) {
+			merged[k] = v
+		}
+	}
+	for k, v := range src {
+		if selected(k, toProvider, toConsumer, direction) {
+			merged[k] = v
+		}
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
@@ -0,0 +1,70 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldsync
+
+import (
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestMetadata(t *testing.T) {
+	defaults := &kubebindv1alpha1.MetadataSync{
+		ToProvider: &kubebindv1alpha1.MetadataFilter{
+			Labels:      &kubebindv1alpha1.PrefixFilter{Include: []string{"*"}, Exclude: []string{"argocd.argoproj.io/"}},
+			Annotations: &kubebindv1alpha1.PrefixFilter{Include: []string{"*"}, Exclude: []string{"kubectl.kubernetes.io/"}},
+		},
+	}
+	overrides := &kubebindv1alpha1.MetadataSync{
+		ToConsumer: &kubebindv1alpha1.MetadataFilter{
+			Labels: &kubebindv1alpha1.PrefixFilter{Include: []string{"example.com/"}},
+		},
+	}
+	policy, err := NewPolicy(&kubebindv1alpha1.APIServiceExportSpec{MetadataSync: overrides}, defaults)
+	require.NoError(t, err)
+
+	consumer := &unstructured.Unstructured{}
+	consumer.SetLabels(map[string]string{
+		"app":                         "foo",
+		"argocd.argoproj.io/instance": "foo",
+		"example.com/tier":            "stale",
+	})
+	consumer.SetAnnotations(map[string]string{
+		"kubectl.kubernetes.io/last-applied-configuration": "{}",
+		"kube-bind.appscode.com/cluster-namespace":         "kube-bind-zlp9m",
+		"note": "hello",
+	})
+
+	require.Equal(t, map[string]string{"app": "foo"}, policy.FilterLabels(consumer.GetLabels(), kubebindv1alpha1.SyncDirectionToProvider))
+	require.Equal(t, map[string]string{"note": "hello"}, policy.FilterAnnotations(consumer.GetAnnotations(), kubebindv1alpha1.SyncDirectionToProvider))
+
+	provider := &unstructured.Unstructured{}
+	provider.SetLabels(map[string]string{
+		"example.com/id": "42",
+		"internal":       "true",
+	})
+	policy.MergeMetadata(consumer, provider, kubebindv1alpha1.SyncDirectionToConsumer)
+	require.Equal(t, map[string]string{
+		"app":                         "foo",
+		"argocd.argoproj.io/instance": "foo",
+		"example.com/id":              "42",
+	}, consumer.GetLabels())
+	require.Len(t, consumer.GetAnnotations(), 3)
+}
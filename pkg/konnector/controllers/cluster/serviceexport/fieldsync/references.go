@@ -0,0 +1,152 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldsync
+
+import (
+	"fmt"
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+)
+
+// defaultReferences are the references synced if an APIServiceExport does not
+// declare related resources.
+var defaultReferences = []kubebindv1alpha1.RelatedResource{
+	{
+		Path:      "status.secretRef.name",
+		Kind:      "Secret",
+		Direction: kubebindv1alpha1.SyncDirectionToConsumer,
+	},
+}
+
+// Reference is a field of service objects holding the name of a related object.
+type Reference struct {
+	// Kind is the kind of the referenced object.
+	Kind string
+	// Direction is the direction the referenced object is synced in.
+	Direction kubebindv1alpha1.SyncDirection
+
+	path []string
+}
+
+func newReferences(related []kubebindv1alpha1.RelatedResource, p *Policy) ([]Reference, error) {
+	if len(related) == 0 {
+		related = defaultReferences
+	}
+
+	refs := make([]Reference, 0, len(related))
+	for _, rr := range related {
+		switch rr.Kind {
+		case "Secret", "ConfigMap":
+		default:
+			return nil, fmt.Errorf("unsupported kind %q of related resource %q", rr.Kind, rr.Path)
+		}
+
+		path := strings.Split(strings.TrimPrefix(rr.Path, "."), ".")
+		fieldPath := make([]string, 0, len(path))
+		for _, name := range path {
+			name = strings.TrimSuffix(name, "[]")
+			if name == "" {
+				return nil, fmt.Errorf("invalid related resource path %q", rr.Path)
+			}
+			fieldPath = append(fieldPath, name)
+		}
+
+		switch rr.Direction {
+		case kubebindv1alpha1.SyncDirectionToProvider, kubebindv1alpha1.SyncDirectionToConsumer:
+		default:
+			return nil, fmt.Errorf("invalid direction %q of related resource %q", rr.Direction, rr.Path)
+		}
+		if d := p.Direction(fieldPath...); d != rr.Direction {
+			return nil, fmt.Errorf("related resource %q is synced %s, but its field is synced %s", rr.Path, rr.Direction, d)
+		}
+
+		refs = append(refs, Reference{
+			Kind:      rr.Kind,
+			Direction: rr.Direction,
+			path:      path,
+		})
+	}
+	return refs, nil
+}
+
+// References returns the references whose objects are synced in the given direction.
+func (p *Policy) References(direction kubebindv1alpha1.SyncDirection) []Reference {
+	var refs []Reference
+	for _, ref := range p.references {
+		if ref.Direction == direction {
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+// Names returns the non-empty names the reference holds in obj.
+func (r Reference) Names(obj map[string]interface{}) []string {
+	var names []string
+	visit(obj, r.path, func(name string) string {
+		names = append(names, name)
+		return name
+	})
+	return names
+}
+
+// Rewrite replaces the names the reference holds in obj according to renames.
+// Names missing in renames are kept.
+func (r Reference) Rewrite(obj map[string]interface{}, renames map[string]string) {
+	visit(obj, r.path, func(name string) string {
+		if renamed, found := renames[name]; found {
+			return renamed
+		}
+		return name
+	})
+}
+
+// visit calls fn for every non-empty string at path in obj and replaces it with
+// the returned value.
+func visit(obj map[string]interface{}, path []string, fn func(string) string) {
+	name, isList := strings.CutSuffix(path[0], "[]")
+	v, found := obj[name]
+	if !found {
+		return
+	}
+
+	var items []interface{}
+	if isList {
+		items, _ = v.([]interface{})
+	} else {
+		items = []interface{}{v}
+	}
+
+	for i, item := range items {
+		if len(path) > 1 {
+			if m, ok := item.(map[string]interface{}); ok {
+				visit(m, path[1:], fn)
+			}
+			continue
+		}
+		s, ok := item.(string)
+		if !ok || s == "" {
+			continue
+		}
+		if isList {
+			items[i] = fn(s)
+		} else {
+			obj[name] = fn(s)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fieldsync
+
+import (
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReferences(t *testing.T) {
+	policy, err := NewPolicy(&kubebindv1alpha1.APIServiceExportSpec{
+		RelatedResources: []kubebindv1alpha1.RelatedResource{
+			{Path: "spec.credentialsRef.name", Kind: "Secret", Direction: kubebindv1alpha1.SyncDirectionToProvider},
+			{Path: "status.configMaps[].name", Kind: "ConfigMap", Direction: kubebindv1alpha1.SyncDirectionToConsumer},
+		},
+	}, nil)
+	require.NoError(t, err)
+
+	obj := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"credentialsRef": map[string]interface{}{"name": "creds"},
+		},
+		"status": map[string]interface{}{
+			"configMaps": []interface{}{
+				map[string]interface{}{"name": "a"},
+				map[string]interface{}{"name": ""},
+				map[string]interface{}{"name": "b"},
+			},
+		},
+	}
+
+	toProvider := policy.References(kubebindv1alpha1.SyncDirectionToProvider)
+	require.Len(t, toProvider, 1)
+	require.Equal(t, "Secret", toProvider[0].Kind)
+	require.Equal(t, []string{"creds"}, toProvider[0].Names(obj))
+
+	toConsumer := policy.References(kubebindv1alpha1.SyncDirectionToConsumer)
+	require.Len(t, toConsumer, 1)
+	require.Equal(t, []string{"a", "b"}, toConsumer[0].Names(obj))
+
+	toConsumer[0].Rewrite(obj, map[string]string{"a": "a-copy"})
+	require.Equal(t, []string{"a-copy", "b"}, toConsumer[0].Names(obj))
+}
+
+func TestReferencesDefault(t *testing.T) {
+	policy, err := NewPolicy(&kubebindv1alpha1.APIServiceExportSpec{}, nil)
+	require.NoError(t, err)
+
+	refs := policy.References(kubebindv1alpha1.SyncDirectionToConsumer)
+	require.Len(t, refs, 1)
+	require.Equal(t, []string{"creds"}, refs[0].Names(map[string]interface{}{
+		"status": map[string]interface{}{"secretRef": map[string]interface{}{"name": "creds"}},
+	}))
+}
+
+func TestReferencesDirectionMismatch(t *testing.T) {
+	_, err := NewPolicy(&kubebindv1alpha1.APIServiceExportSpec{
+		RelatedResources: []kubebindv1alpha1.RelatedResource{
+			{Path: "spec.credentialsRef.name", Kind: "Secret", Direction: kubebindv1alpha1.SyncDirectionToConsumer},
+		},
+	}, nil)
+	require.Error(t, err)
+}
@@ -31,6 +31,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	dynamicclient "k8s.io/client-go/dynamic"
@@ -48,7 +49,8 @@ const (
 type GetterInformer interface {
 	Get(ns, name string) (runtime.Object, error)
 	List(ns string) ([]runtime.Object, error)
-	AddEventHandler(handler cache.ResourceEventHandler)
+	AddEventHandler(handler cache.ResourceEventHandler) cache.ResourceEventHandlerRegistration
+	RemoveEventHandler(registration cache.ResourceEventHandlerRegistration) error
 
 	Start(ctx context.Context)
 	WaitForCacheSync(stopCh <-chan struct{}) map[schema.GroupVersionResource]bool
@@ -69,7 +71,28 @@ type DynamicMultiNamespaceInformer struct {
 	lock               sync.RWMutex
 	namespaceInformers map[string]informers.GenericInformer
 	namespaceCancel    map[string]func()
-	handlers           []cache.ResourceEventHandler
+	handlers           map[*multiNamespaceRegistration]cache.ResourceEventHandler
+}
+
+// multiNamespaceRegistration is the registration of an event handler with the
+// informers of all namespaces.
+type multiNamespaceRegistration struct {
+	inf *DynamicMultiNamespaceInformer
+	// registrations are by APIServiceNamespace name.
+	registrations map[string]cache.ResourceEventHandlerRegistration
+}
+
+// HasSynced returns true if the handler has seen the initial objects of all
+// running namespace informers.
+func (r *multiNamespaceRegistration) HasSynced() bool {
+	r.inf.lock.RLock()
+	defer r.inf.lock.RUnlock()
+	for _, reg := range r.registrations {
+		if !reg.HasSynced() {
+			return false
+		}
+	}
+	return true
 }
 
 func NewDynamicMultiNamespaceInformer(
@@ -94,6 +117,7 @@ func NewDynamicMultiNamespaceInformer(
 
 		namespaceInformers: map[string]informers.GenericInformer{},
 		namespaceCancel:    map[string]func(){},
+		handlers:           map[*multiNamespaceRegistration]cache.ResourceEventHandler{},
 	}
 
 	return &inf, nil
@@ -170,6 +194,9 @@ func (inf *DynamicMultiNamespaceInformer) enqueueServiceNamespace(obj interface{
 			logger.V(2).Info("stopping informer", "namespace", sns.Status.Namespace)
 			delete(inf.namespaceCancel, name)
 			delete(inf.namespaceInformers, name)
+			for reg := range inf.handlers {
+				delete(reg.registrations, name)
+			}
 			cancel()
 		}
 		return
@@ -190,27 +217,54 @@ func (inf *DynamicMultiNamespaceInformer) enqueueServiceNamespace(obj interface{
 	inf.namespaceCancel[name] = cancel
 	inf.namespaceInformers[name] = gvrInf
 
-	for _, h := range inf.handlers {
-		_, err = gvrInf.Informer().AddEventHandler(h)
+	for reg, h := range inf.handlers {
+		r, err := gvrInf.Informer().AddEventHandler(h)
 		if err != nil {
 			panic(err)
 		}
+		reg.registrations[name] = r
 	}
 
 	factory.Start(ctx.Done())
 }
 
-func (inf *DynamicMultiNamespaceInformer) AddEventHandler(handler cache.ResourceEventHandler) {
+func (inf *DynamicMultiNamespaceInformer) AddEventHandler(handler cache.ResourceEventHandler) cache.ResourceEventHandlerRegistration {
 	inf.lock.Lock()
 	defer inf.lock.Unlock()
 
-	for _, i := range inf.namespaceInformers {
-		_, err := i.Informer().AddEventHandler(handler)
+	reg := &multiNamespaceRegistration{
+		inf:           inf,
+		registrations: map[string]cache.ResourceEventHandlerRegistration{},
+	}
+	for name, i := range inf.namespaceInformers {
+		r, err := i.Informer().AddEventHandler(handler)
 		if err != nil {
 			panic(err)
 		}
+		reg.registrations[name] = r
 	}
-	inf.handlers = append(inf.handlers, handler)
+	inf.handlers[reg] = handler
+	return reg
+}
+
+func (inf *DynamicMultiNamespaceInformer) RemoveEventHandler(registration cache.ResourceEventHandlerRegistration) error {
+	inf.lock.Lock()
+	defer inf.lock.Unlock()
+
+	reg, ok := registration.(*multiNamespaceRegistration)
+	if !ok || reg.inf != inf {
+		return fmt.Errorf("foreign event handler registration %v", registration)
+	}
+	var errs []error
+	for name, r := range reg.registrations {
+		if i, found := inf.namespaceInformers[name]; found {
+			if err := i.Informer().RemoveEventHandler(r); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	delete(inf.handlers, reg)
+	return utilerrors.NewAggregate(errs)
 }
 
 func (inf *DynamicMultiNamespaceInformer) Get(ns, name string) (runtime.Object, error) {
@@ -303,11 +357,16 @@ func (w GetterInformerWrapper) List(ns string) ([]runtime.Object, error) {
 	return w.Delegate.ForResource(w.GVR).Lister().ByNamespace(ns).List(labels.Everything())
 }
 
-func (w GetterInformerWrapper) AddEventHandler(handler cache.ResourceEventHandler) {
-	_, err := w.Delegate.ForResource(w.GVR).Informer().AddEventHandler(handler)
+func (w GetterInformerWrapper) AddEventHandler(handler cache.ResourceEventHandler) cache.ResourceEventHandlerRegistration {
+	reg, err := w.Delegate.ForResource(w.GVR).Informer().AddEventHandler(handler)
 	if err != nil {
 		panic(err)
 	}
+	return reg
+}
+
+func (w GetterInformerWrapper) RemoveEventHandler(registration cache.ResourceEventHandlerRegistration) error {
+	return w.Delegate.ForResource(w.GVR).Informer().RemoveEventHandler(registration)
 }
 
 func (w GetterInformerWrapper) Start(ctx context.Context) {
@@ -0,0 +1,228 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providerinformers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	dynamicclient "k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	controllerName = "kube-bind-konnector-provider-informers"
+)
+
+// Key identifies a shared provider informer.
+type Key struct {
+	ClusterID string
+	// ProviderNamespace is the cluster namespace of the consumer on the
+	// provider cluster. Informers of different consumers are not shared.
+	ProviderNamespace string
+	GVR               schema.GroupVersionResource
+
+	// ClusterWide informers watch all namespaces of the provider cluster.
+	// Namespace informers watch only the given namespace. Otherwise, only the
+	// service namespaces of the consumer are watched.
+	ClusterWide bool
+	Namespace   string
+	// FieldSelector restricts the objects of cluster-wide and namespace informers.
+	FieldSelector string
+}
+
+// Cache shares the informers of provider clusters between the syncers of all
+// APIServiceExports. Informers are started on first use and stopped when the
+// last user releases them.
+type Cache struct {
+	lock    sync.Mutex
+	entries map[Key]*entry
+
+	// newInformer creates the informer of a key.
+	newInformer func(provider *konnectormodels.ProviderInfo, key Key) (multinsinformer.GetterInformer, error)
+}
+
+type entry struct {
+	informer multinsinformer.GetterInformer
+	refs     int
+	cancel   func()
+}
+
+// NewCache returns an empty informer cache.
+func NewCache() *Cache {
+	return &Cache{
+		entries:     map[Key]*entry{},
+		newInformer: newInformer,
+	}
+}
+
+func newInformer(provider *konnectormodels.ProviderInfo, key Key) (multinsinformer.GetterInformer, error) {
+	if !key.ClusterWide && key.Namespace == "" {
+		return multinsinformer.NewDynamicMultiNamespaceInformer(
+			key.GVR,
+			provider.Namespace,
+			provider.Config,
+			provider.DynamicServiceNamespaceInformer,
+		)
+	}
+
+	config := rest.CopyConfig(provider.Config)
+	config = rest.AddUserAgent(config, controllerName)
+	client, err := dynamicclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	ns := metav1.NamespaceAll
+	if !key.ClusterWide {
+		ns = key.Namespace
+	}
+	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(client, time.Minute*30, ns, func(options *metav1.ListOptions) {
+		options.FieldSelector = key.FieldSelector
+	})
+	factory.ForResource(key.GVR).Lister() // wire the GVR up in the informer factory
+	return multinsinformer.GetterInformerWrapper{
+		GVR:      key.GVR,
+		Delegate: factory,
+	}, nil
+}
+
+// Acquire returns a handle of the informer of the given key, starting it if
+// not running yet. The handle must be released when not used anymore.
+func (c *Cache) Acquire(provider *konnectormodels.ProviderInfo, key Key) (*Handle, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, found := c.entries[key]
+	if !found {
+		inf, err := c.newInformer(provider, key)
+		if err != nil {
+			return nil, err
+		}
+
+		logger := klog.Background().WithValues("controller", controllerName, "clusterID", key.ClusterID, "providerNamespace", key.ProviderNamespace, "gvr", key.GVR)
+		logger.V(1).Info("starting shared provider informer", "clusterWide", key.ClusterWide, "namespace", key.Namespace, "fieldSelector", key.FieldSelector)
+		ctx, cancel := context.WithCancel(klog.NewContext(context.Background(), logger))
+		inf.Start(ctx)
+
+		e = &entry{informer: inf, cancel: cancel}
+		c.entries[key] = e
+	}
+	e.refs++
+
+	return &Handle{cache: c, key: key, informer: e.informer}, nil
+}
+
+func (c *Cache) release(key Key) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	e, found := c.entries[key]
+	if !found {
+		return
+	}
+	e.refs--
+	if e.refs > 0 {
+		return
+	}
+
+	klog.Background().V(1).Info("stopping shared provider informer", "controller", controllerName, "clusterID", key.ClusterID, "providerNamespace", key.ProviderNamespace, "gvr", key.GVR)
+	e.cancel()
+	delete(c.entries, key)
+}
+
+// Handle is a reference to a shared informer. The event handlers added
+// through it are removed on release.
+type Handle struct {
+	cache    *Cache
+	key      Key
+	informer multinsinformer.GetterInformer
+
+	lock          sync.Mutex
+	registrations []cache.ResourceEventHandlerRegistration
+	released      bool
+}
+
+var _ multinsinformer.GetterInformer = &Handle{}
+
+func (h *Handle) Get(ns, name string) (runtime.Object, error) {
+	return h.informer.Get(ns, name)
+}
+
+func (h *Handle) List(ns string) ([]runtime.Object, error) {
+	return h.informer.List(ns)
+}
+
+func (h *Handle) AddEventHandler(handler cache.ResourceEventHandler) cache.ResourceEventHandlerRegistration {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	reg := h.informer.AddEventHandler(handler)
+	h.registrations = append(h.registrations, reg)
+	return reg
+}
+
+func (h *Handle) RemoveEventHandler(registration cache.ResourceEventHandlerRegistration) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	for i, reg := range h.registrations {
+		if reg == registration {
+			h.registrations = append(h.registrations[:i], h.registrations[i+1:]...)
+			break
+		}
+	}
+	return h.informer.RemoveEventHandler(registration)
+}
+
+// Start does nothing. Shared informers are started by the cache.
+func (h *Handle) Start(ctx context.Context) {}
+
+func (h *Handle) WaitForCacheSync(stopCh <-chan struct{}) map[schema.GroupVersionResource]bool {
+	return h.informer.WaitForCacheSync(stopCh)
+}
+
+// Release removes the event handlers added through the handle and stops the
+// informer if this was the last reference. Further calls do nothing.
+func (h *Handle) Release() {
+	h.lock.Lock()
+	if h.released {
+		h.lock.Unlock()
+		return
+	}
+	h.released = true
+	registrations := h.registrations
+	h.registrations = nil
+	h.lock.Unlock()
+
+	for _, reg := range registrations {
+		if err := h.informer.RemoveEventHandler(reg); err != nil {
+			utilruntime.HandleError(err)
+		}
+	}
+	h.cache.release(h.key)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package providerinformers
+
+import (
+	"context"
+	"testing"
+
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/tools/cache"
+)
+
+type fakeInformer struct {
+	ctx      context.Context
+	handlers map[*fakeRegistration]cache.ResourceEventHandler
+}
+
+type fakeRegistration struct {
+	id int
+}
+
+func (r *fakeRegistration) HasSynced() bool { return true }
+
+func (f *fakeInformer) Get(ns, name string) (runtime.Object, error) { return nil, nil }
+func (f *fakeInformer) List(ns string) ([]runtime.Object, error)    { return nil, nil }
+
+func (f *fakeInformer) AddEventHandler(handler cache.ResourceEventHandler) cache.ResourceEventHandlerRegistration {
+	reg := &fakeRegistration{id: len(f.handlers)}
+	f.handlers[reg] = handler
+	return reg
+}
+
+func (f *fakeInformer) RemoveEventHandler(registration cache.ResourceEventHandlerRegistration) error {
+	delete(f.handlers, registration.(*fakeRegistration))
+	return nil
+}
+
+func (f *fakeInformer) Start(ctx context.Context) { f.ctx = ctx }
+
+func (f *fakeInformer) WaitForCacheSync(stopCh <-chan struct{}) map[schema.GroupVersionResource]bool {
+	return nil
+}
+
+func TestCache(t *testing.T) {
+	var created []*fakeInformer
+	c := NewCache()
+	c.newInformer = func(provider *konnectormodels.ProviderInfo, key Key) (multinsinformer.GetterInformer, error) {
+		inf := &fakeInformer{handlers: map[*fakeRegistration]cache.ResourceEventHandler{}}
+		created = append(created, inf)
+		return inf, nil
+	}
+
+	provider := &konnectormodels.ProviderInfo{ClusterID: "cluster"}
+	key := Key{ClusterID: "cluster", GVR: schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "foos"}}
+
+	h1, err := c.Acquire(provider, key)
+	require.NoError(t, err)
+	h2, err := c.Acquire(provider, key)
+	require.NoError(t, err)
+	require.Len(t, created, 1, "informer should be shared")
+	inf := created[0]
+	require.NotNil(t, inf.ctx, "informer should be started")
+
+	h1.AddEventHandler(cache.ResourceEventHandlerFuncs{})
+	h2.AddEventHandler(cache.ResourceEventHandlerFuncs{})
+	require.Len(t, inf.handlers, 2)
+
+	other := key
+	other.ClusterWide = true
+	h3, err := c.Acquire(provider, other)
+	require.NoError(t, err)
+	require.Len(t, created, 2, "different keys should not share informers")
+
+	otherConsumer := key
+	otherConsumer.ProviderNamespace = "kube-bind-other"
+	h5, err := c.Acquire(provider, otherConsumer)
+	require.NoError(t, err)
+	require.Len(t, created, 3, "different consumers should not share informers")
+	h5.Release()
+
+	h1.Release()
+	h1.Release()
+	require.Len(t, inf.handlers, 1, "handlers of the released handle should be removed")
+	require.NoError(t, inf.ctx.Err(), "informer should keep running while referenced")
+
+	h2.Release()
+	require.Empty(t, inf.handlers)
+	require.Error(t, inf.ctx.Err(), "informer should be stopped after the last release")
+
+	h4, err := c.Acquire(provider, key)
+	require.NoError(t, err)
+	require.Len(t, created, 4, "informer should be recreated after it was stopped")
+
+	h3.Release()
+	h4.Release()
+	require.Empty(t, c.entries)
+}
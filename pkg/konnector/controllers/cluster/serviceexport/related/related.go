@@ -0,0 +1,180 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package related
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// Syncer copies related objects of service objects from a source to a target cluster.
+type Syncer struct {
+	Source dynamic.Interface
+	Target dynamic.Interface
+
+	// Suffix is appended to the names of the copies.
+	Suffix string
+}
+
+// Rewrite rewrites the names the reference holds in obj to the names of their
+// copies. It returns the original names.
+func (s Syncer) Rewrite(ref fieldsync.Reference, obj map[string]interface{}) []string {
+	names := ref.Names(obj)
+	renames := make(map[string]string, len(names))
+	for _, name := range names {
+		renames[name] = name + s.Suffix
+	}
+	ref.Rewrite(obj, renames)
+	return names
+}
+
+// Sync copies the named objects of the referenced kind from the source namespace
+// into the namespace of owner, and deletes the copies named in stale that are
+// not copies of the named objects anymore.
+func (s Syncer) Sync(ctx context.Context, ref fieldsync.Reference, names []string, sourceNs string, owner *unstructured.Unstructured, stale []string) error {
+	logger := klog.FromContext(ctx)
+	gvr := GroupVersionResource(ref.Kind)
+
+	copies := sets.New[string]()
+	for _, name := range names {
+		copies.Insert(name + s.Suffix)
+
+		src, err := s.Source.Resource(gvr).Namespace(sourceNs).Get(ctx, name, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			logger.V(2).Info("related object not found", "kind", ref.Kind, "namespace", sourceNs, "name", name)
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := Apply(ctx, s.Target.Resource(gvr), src, owner.GetNamespace(), name+s.Suffix, owner); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range stale {
+		if copies.Has(name) {
+			continue
+		}
+		if err := Delete(ctx, s.Target.Resource(gvr), owner.GetNamespace(), name, owner); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GroupVersionResource returns the resource of the given kind of related objects.
+func GroupVersionResource(kind string) schema.GroupVersionResource {
+	switch kind {
+	case "ConfigMap":
+		return schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	default:
+		return schema.GroupVersionResource{Version: "v1", Resource: "secrets"}
+	}
+}
+
+// IsOwnedBy returns true if obj has an owner reference to owner.
+func IsOwnedBy(obj, owner metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply creates or updates the copy of src with the given namespace and name,
+// owned by owner. It fails if an object not owned by owner exists with that name.
+func Apply(ctx context.Context, client dynamic.NamespaceableResourceInterface, src *unstructured.Unstructured, ns, name string, owner *unstructured.Unstructured) error {
+	logger := klog.FromContext(ctx)
+
+	existing, err := client.Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	} else if errors.IsNotFound(err) {
+		obj := &unstructured.Unstructured{Object: content(src)}
+		obj.SetNamespace(ns)
+		obj.SetName(name)
+		obj.SetOwnerReferences([]metav1.OwnerReference{
+			{
+				APIVersion: owner.GetAPIVersion(),
+				Kind:       owner.GetKind(),
+				Name:       owner.GetName(),
+				UID:        owner.GetUID(),
+			},
+		})
+		logger.V(1).Info("Creating related object", "kind", src.GetKind(), "namespace", ns, "name", name)
+		_, err := client.Namespace(ns).Create(ctx, obj, metav1.CreateOptions{})
+		return err
+	}
+
+	if !IsOwnedBy(existing, owner) {
+		return fmt.Errorf("%s %s/%s already exists and is not owned by %s %s", src.GetKind(), ns, name, owner.GetKind(), owner.GetName())
+	}
+	if reflect.DeepEqual(content(existing), content(src)) {
+		return nil
+	}
+
+	obj := &unstructured.Unstructured{Object: content(src)}
+	obj.Object["metadata"] = existing.Object["metadata"]
+	logger.V(1).Info("Updating related object", "kind", src.GetKind(), "namespace", ns, "name", name)
+	_, err = client.Namespace(ns).Update(ctx, obj, metav1.UpdateOptions{})
+	return err
+}
+
+// Delete deletes the object with the given namespace and name if it is owned by owner.
+func Delete(ctx context.Context, client dynamic.NamespaceableResourceInterface, ns, name string, owner metav1.Object) error {
+	existing, err := client.Namespace(ns).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !IsOwnedBy(existing, owner) {
+		return nil // not ours
+	}
+
+	klog.FromContext(ctx).V(1).Info("Deleting related object", "kind", existing.GetKind(), "namespace", ns, "name", name)
+	err = client.Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// content returns a copy of the fields of obj other than metadata.
+func content(obj *unstructured.Unstructured) map[string]interface{} {
+	c := make(map[string]interface{}, len(obj.Object))
+	for k, v := range obj.Object {
+		if k != "metadata" {
+			c[k] = runtime.DeepCopyJSONValue(v)
+		}
+	}
+	return c
+}
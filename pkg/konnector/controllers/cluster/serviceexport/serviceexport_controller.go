@@ -26,15 +26,21 @@ import (
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/pkg/committer"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/consumerinformers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/providerinformers"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/synctuning"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
+	dynamicclient "k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
@@ -46,26 +52,32 @@ const (
 )
 
 // NewController returns a new controller for ServiceExports, spawning spec
-// and status syncer on-demand.
+// and status syncer on-demand for the APIServiceBindings owned by the sharder.
 func NewController(
 	consumerConfig *rest.Config,
+	metadataSync *v1alpha1.MetadataSync,
+	syncTuning *v1alpha1.SyncTuning,
+	sharder *sharding.Sharder,
 	serviceBindingInformer dynamic.Informer[bindlisters.APIServiceBindingLister],
 	crdInformer dynamic.Informer[apiextensionslisters.CustomResourceDefinitionLister],
 	providerInfos []*konnectormodels.ProviderInfo,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(synctuning.NewRateLimiter(synctuning.Merge(syncTuning, nil)), controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
 	consumerConfig = rest.CopyConfig(consumerConfig)
 	consumerConfig = rest.AddUserAgent(consumerConfig, controllerName)
+	consumerClient, err := dynamicclient.NewForConfig(consumerConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	for _, provider := range providerInfos {
 		provider.Config = rest.CopyConfig(provider.Config)
 		provider.Config = rest.AddUserAgent(provider.Config, controllerName)
 
 		// create shared informer factory
-		var err error
 		if provider.BindClient, err = bindclient.NewForConfig(provider.Config); err != nil {
 			return nil, err
 		}
@@ -79,13 +91,18 @@ func NewController(
 		serviceBindingInformer: serviceBindingInformer,
 		crdInformer:            crdInformer,
 		providerInfos:          providerInfos,
+		sharder:                sharder,
 
 		reconciler: reconciler{
 			consumerConfig: consumerConfig,
+			metadataSync:   metadataSync,
+			syncTuning:     syncTuning,
 
 			syncContext: map[syncInfo]syncContext{},
 
-			providerInfos: providerInfos,
+			providerInfos:     providerInfos,
+			providerInformers: providerinformers.NewCache(),
+			consumerInformers: consumerinformers.NewCache(consumerClient),
 
 			getCRD: func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
 				return crdInformer.Lister().Get(name)
@@ -144,6 +161,7 @@ type controller struct {
 	crdInformer            dynamic.Informer[apiextensionslisters.CustomResourceDefinitionLister]
 
 	providerInfos []*konnectormodels.ProviderInfo
+	sharder       *sharding.Sharder
 
 	reconciler
 
@@ -211,6 +229,20 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 		},
 	})
 
+	// start and stop syncers when shards move
+	c.sharder.Subscribe(ctx, func() {
+		for _, provider := range c.providerInfos {
+			exports, err := provider.BindInformer.KubeBind().V1alpha1().APIServiceExports().Lister().APIServiceExports(provider.Namespace).List(labels.Everything())
+			if err != nil {
+				runtime.HandleError(err)
+				continue
+			}
+			for _, export := range exports {
+				c.enqueueServiceExport(logger, export)
+			}
+		}
+	})
+
 	c.crdInformer.Informer().AddDynamicEventHandler(ctx, controllerName, cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			c.enqueueCRD(logger, obj)
@@ -290,6 +322,13 @@ func (c *controller) process(ctx context.Context, key string) error {
 		}
 		return nil
 	}
+	if !c.sharder.Owns(provider.ShardKey) {
+		// another replica handles the APIServiceBinding. Stop the syncer as if deleted.
+		return c.reconcile(ctx, &syncInfo{
+			clusterID:    provider.ClusterID,
+			exporterName: name,
+		}, nil)
+	}
 
 	old := obj
 	obj = obj.DeepCopy()
@@ -18,24 +18,36 @@ package serviceexport
 
 import (
 	"context"
+	"reflect"
 	"sync"
-	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
+	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/consumerinformers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/drift"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/event"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/providerinformers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/related"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/spec"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/status"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/synctuning"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
 	runtimeschema "k8s.io/apimachinery/pkg/runtime/schema"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	dynamicclient "k8s.io/client-go/dynamic"
-	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
 	"k8s.io/klog/v2"
 	conditionsapi "kmodules.xyz/client-go/api/v1"
 	"kmodules.xyz/client-go/conditions"
@@ -51,7 +63,16 @@ type reconciler struct {
 	lock           sync.Mutex
 	syncContext    map[syncInfo]syncContext // by ClusterID and CRD name
 
+	// metadataSync holds the default label and annotation filters.
+	metadataSync *v1alpha1.MetadataSync
+	// syncTuning holds the default tuning of the sync controllers.
+	syncTuning *v1alpha1.SyncTuning
+
 	providerInfos []*konnectormodels.ProviderInfo
+	// providerInformers and consumerInformers are shared between the syncers
+	// of all exports.
+	providerInformers *providerinformers.Cache
+	consumerInformers *consumerinformers.Cache
 
 	getCRD            func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
 	getServiceBinding func(name string) (*v1alpha1.APIServiceBinding, error)
@@ -59,7 +80,10 @@ type reconciler struct {
 
 type syncContext struct {
 	generation int64
-	cancel     func()
+	tuning     v1alpha1.SyncTuning
+	// stop stops the controllers, cancel additionally releases the informers.
+	stop   func()
+	cancel func()
 }
 
 func (r *reconciler) reconcile(ctx context.Context, sync *syncInfo, export *v1alpha1.APIServiceExport) error {
@@ -124,15 +148,19 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 	if err != nil && !errors.IsNotFound(err) {
 		return err
 	}
-	if binding == nil {
-		// stop it
+	if binding == nil || binding.Spec.Paused {
+		// stop it. Unpausing starts a new syncer, resyncing all objects.
+		reason := "NoAPIServiceBinding"
+		if binding != nil {
+			reason = "APIServiceBindingPaused"
+		}
 		r.lock.Lock()
 		defer r.lock.Unlock()
 		if c, found := r.syncContext[syncInfo{
 			clusterID:    sync.clusterID,
 			exporterName: export.Name,
 		}]; found {
-			logger.V(1).Info("Stopping APIServiceExport sync", "reason", "NoAPIServiceBinding")
+			logger.V(1).Info("Stopping APIServiceExport sync", "reason", reason)
 			c.cancel()
 			delete(r.syncContext, syncInfo{
 				clusterID:    sync.clusterID,
@@ -142,6 +170,7 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 
 		return nil
 	}
+	tuning := synctuning.Merge(r.syncTuning, binding.Spec.Sync)
 
 	r.lock.Lock()
 	c, found := r.syncContext[syncInfo{
@@ -149,15 +178,21 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 		exporterName: export.Name,
 	}]
 	if found {
-		if c.generation == export.Generation {
+		if c.generation == export.Generation && reflect.DeepEqual(c.tuning, tuning) {
 			r.lock.Unlock()
 			return nil // all as expected
 		}
 
-		// technically, we could be less aggressive here if nothing big changed in the resource, e.g. just schemas. But ¯\_(ツ)_/¯
-
-		logger.V(1).Info("Stopping APIServiceExport sync", "reason", "GenerationChanged", "generation", export.Generation)
-		c.cancel()
+		// The controllers are restarted with the new spec. The informers are
+		// released only after the new syncer acquired them, such that they keep
+		// running and do not relist if the resource did not change.
+		reason := "GenerationChanged"
+		if c.generation == export.Generation {
+			reason = "SyncTuningChanged"
+		}
+		logger.V(1).Info("Stopping APIServiceExport sync", "reason", reason, "generation", export.Generation)
+		c.stop()
+		defer c.cancel()
 		delete(r.syncContext, syncInfo{
 			clusterID:    sync.clusterID,
 			exporterName: export.Name,
@@ -167,18 +202,49 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 
 	// start a new syncer
 
-	var syncVersion string
-	for _, v := range export.Spec.Versions {
-		if v.Served {
-			syncVersion = v.Name
-			break
-		}
+	syncVersion, err := helpers.SyncVersion(&export.Spec.APIServiceExportCRDSpec)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
 	}
 	gvr := runtimeschema.GroupVersionResource{Group: export.Spec.Group, Version: syncVersion, Resource: export.Spec.Names.Plural}
 
-	dynamicConsumerClient := dynamicclient.NewForConfigOrDie(r.consumerConfig)
-	consumerInf := dynamicinformer.NewDynamicSharedInformerFactory(dynamicConsumerClient, time.Minute*30)
+	// the informers are shared between exports. Handles acquired here are
+	// released when the syncer stops, or right away if it does not start.
+	var handles []*providerinformers.Handle
+	var consumerHandles []*consumerinformers.Handle
+	releaseHandles := func() {
+		for _, h := range handles {
+			h.Release()
+		}
+		for _, h := range consumerHandles {
+			h.Release()
+		}
+	}
+	started := false
+	defer func() {
+		if !started {
+			releaseHandles()
+		}
+	}()
+	acquire := func(provider *konnectormodels.ProviderInfo, key providerinformers.Key) (multinsinformer.GetterInformer, error) {
+		h, err := r.providerInformers.Acquire(provider, key)
+		if err != nil {
+			return nil, err
+		}
+		handles = append(handles, h)
+		return h, nil
+	}
+	acquireConsumer := func(gvr runtimeschema.GroupVersionResource) informers.GenericInformer {
+		h := r.consumerInformers.Acquire(gvr)
+		consumerHandles = append(consumerHandles, h)
+		return h
+	}
+	consumerInf := acquireConsumer(gvr)
+	consumerHasSynced := []cache.InformerSynced{consumerInf.Informer().HasSynced}
 
+	objectInformers := map[string]multinsinformer.GetterInformer{}
+	eventInformers := map[string]multinsinformer.GetterInformer{}
 	for _, provider := range r.providerInfos {
 		dynamicProviderClient := dynamicclient.NewForConfigOrDie(provider.Config)
 
@@ -192,31 +258,98 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 			provider.NamespaceUID = string(pns.GetUID())
 		}
 
-		if crd.Spec.Scope == apiextensionsv1.ClusterScoped || export.Spec.InformerScope == v1alpha1.ClusterScope {
-			factory := dynamicinformer.NewDynamicSharedInformerFactory(dynamicProviderClient, time.Minute*30)
-			factory.ForResource(gvr).Lister() // wire the GVR up in the informer factory
-			provider.ProviderDynamicInformer = multinsinformer.GetterInformerWrapper{
-				GVR:      gvr,
-				Delegate: factory,
-			}
-		} else {
-			provider.ProviderDynamicInformer, err = multinsinformer.NewDynamicMultiNamespaceInformer(
-				gvr,
-				provider.Namespace,
-				provider.Config,
-				provider.DynamicServiceNamespaceInformer,
-			)
-			if err != nil {
-				return err
-			}
+		objectKey := providerinformers.Key{ClusterID: provider.ClusterID, ProviderNamespace: provider.Namespace, GVR: gvr}
+		switch {
+		case crd.Spec.Scope == apiextensionsv1.ClusterScoped && export.Spec.ClusterScopedIsolation == v1alpha1.IsolationNamespaced:
+			// the provider side objects are namespaced, in the cluster namespace
+			objectKey.Namespace = provider.Namespace
+		case crd.Spec.Scope == apiextensionsv1.ClusterScoped || export.Spec.InformerScope == v1alpha1.ClusterScope:
+			objectKey.ClusterWide = true
+		}
+		if objectInformers[provider.ClusterID], err = acquire(provider, objectKey); err != nil {
+			return err
+		}
+
+		// Events are only readable in the service namespaces and the cluster
+		// namespace of the consumer. Events of cluster-scoped objects are mirrored
+		// if the provider records them in the cluster namespace.
+		eventKey := providerinformers.Key{ClusterID: provider.ClusterID, ProviderNamespace: provider.Namespace, GVR: event.EventsGVR}
+		if crd.Spec.Scope == apiextensionsv1.ClusterScoped {
+			eventKey.Namespace = provider.Namespace
+			eventKey.FieldSelector = fields.OneTermEqualSelector("involvedObject.kind", export.Spec.Names.Kind).String()
+		}
+		if eventInformers[provider.ClusterID], err = acquire(provider, eventKey); err != nil {
+			// events are best effort and must not hold back the sync
+			logger.Error(err, "failed to start provider event informer", "clusterID", provider.ClusterID)
+			delete(eventInformers, provider.ClusterID)
+		}
+	}
+
+	// the sync controllers get their own provider clients, tuned for the binding
+	providerInfos := make([]*konnectormodels.ProviderInfo, 0, len(r.providerInfos))
+	for _, provider := range r.providerInfos {
+		tuned := *provider
+		tuned.Config = rest.CopyConfig(provider.Config)
+		if tuning.ProviderQPS > 0 {
+			tuned.Config.QPS = float32(tuning.ProviderQPS)
+		}
+		if tuning.ProviderBurst > 0 {
+			tuned.Config.Burst = int(tuning.ProviderBurst)
+		}
+		providerInfos = append(providerInfos, &tuned)
+	}
+
+	var isolator clusterscoped.Isolator
+	if crd.Spec.Scope == apiextensionsv1.ClusterScoped {
+		if isolator, err = clusterscoped.NewIsolator(export.Spec.ClusterScopedIsolation); err != nil {
+			runtime.HandleError(err)
+			return nil // nothing we can do here
+		}
+	}
+
+	policy, err := fieldsync.NewPolicy(&export.Spec, r.metadataSync)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	}
+
+	// one informer per related kind is shared by all exports referencing it
+	relatedInformers := map[string]informers.GenericInformer{}
+	for _, ref := range policy.References(v1alpha1.SyncDirectionToProvider) {
+		if _, found := relatedInformers[ref.Kind]; found {
+			continue
+		}
+		relatedInformers[ref.Kind] = acquireConsumer(related.GroupVersionResource(ref.Kind))
+		consumerHasSynced = append(consumerHasSynced, relatedInformers[ref.Kind].Informer().HasSynced)
+	}
+
+	// the queues of the syncers of different exports and clusters are told
+	// apart in the workqueue metrics.
+	queueName := export.Name
+	if sync.clusterID != "" {
+		queueName += "-" + sync.clusterID
+	}
+
+	statusSubresource := false
+	for _, v := range crd.Spec.Versions {
+		if v.Name == syncVersion && v.Subresources != nil && v.Subresources.Status != nil {
+			statusSubresource = true
 		}
 	}
 
 	specCtrl, err := spec.NewController(
 		gvr,
+		isolator,
+		policy,
+		statusSubresource,
+		export.Spec.DeletionPolicy,
 		r.consumerConfig,
-		consumerInf.ForResource(gvr),
-		r.providerInfos,
+		consumerInf,
+		relatedInformers,
+		objectInformers,
+		queueName,
+		synctuning.NewRateLimiter(tuning),
+		providerInfos,
 	)
 	if err != nil {
 		runtime.HandleError(err)
@@ -224,34 +357,89 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 	}
 	statusCtrl, err := status.NewController(
 		gvr,
+		isolator,
+		policy,
+		export.Spec.DeletionPolicy,
+		export.Spec.Adopt,
 		r.consumerConfig,
-		consumerInf.ForResource(gvr),
-		r.providerInfos,
+		consumerInf,
+		objectInformers,
+		queueName,
+		synctuning.NewRateLimiter(tuning),
+		providerInfos,
 	)
 	if err != nil {
 		runtime.HandleError(err)
 		return nil // nothing we can do here
 	}
 
-	ctx, cancel := context.WithCancel(ctx)
+	eventCtrl, err := event.NewController(
+		gvr,
+		export.Spec.Names.Kind,
+		isolator,
+		r.consumerConfig,
+		consumerInf,
+		eventInformers,
+		objectInformers,
+		queueName,
+		synctuning.NewRateLimiter(tuning),
+		providerInfos,
+	)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
+	}
 
-	consumerInf.Start(ctx.Done())
-	for _, provider := range r.providerInfos {
-		provider.ProviderDynamicInformer.Start(ctx)
+	driftCtrl, err := drift.NewController(
+		export.Name,
+		gvr,
+		isolator,
+		policy,
+		r.consumerConfig,
+		consumerInf,
+		objectInformers,
+		providerInfos,
+	)
+	if err != nil {
+		runtime.HandleError(err)
+		return nil // nothing we can do here
 	}
 
+	ctx, cancel := context.WithCancel(ctx)
+	started = true
+
+	informersSynced := metrics.NewInformersSynced()
+	go func() {
+		<-ctx.Done()
+		informersSynced.Forget()
+	}()
+
 	go func() {
 		// to not block the main thread
-		consumerSynced := consumerInf.WaitForCacheSync(ctx.Done())
+		consumerSynced := cache.WaitForCacheSync(ctx.Done(), consumerHasSynced...)
 		logger.V(2).Info("Synced informers", "consumer", consumerSynced)
 
 		for _, provider := range r.providerInfos {
-			providerSynced := provider.ProviderDynamicInformer.WaitForCacheSync(ctx.Done())
-			logger.V(2).Info("Synced informers", "provider", providerSynced)
+			providerSynced := objectInformers[provider.ClusterID].WaitForCacheSync(ctx.Done())
+			logger.V(2).Info("Synced informers", "clusterID", provider.ClusterID, "provider", providerSynced)
+			informersSynced.Record(provider.ClusterID, gvr.GroupResource().String(), providerSynced[gvr])
+		}
+
+		go specCtrl.Start(ctx, int(tuning.Workers))
+		go statusCtrl.Start(ctx, int(tuning.Workers))
+		go driftCtrl.Start(ctx)
+	}()
+
+	go func() {
+		// events are started on their own, such that providers not granting
+		// access to events do not hold back the sync.
+		cache.WaitForCacheSync(ctx.Done(), consumerHasSynced...)
+		for clusterID, inf := range eventInformers {
+			eventsSynced := inf.WaitForCacheSync(ctx.Done())
+			logger.V(2).Info("Synced informers", "clusterID", clusterID, "events", eventsSynced)
 		}
 
-		go specCtrl.Start(ctx, 1)
-		go statusCtrl.Start(ctx, 1)
+		eventCtrl.Start(ctx, int(tuning.Workers))
 	}()
 
 	r.lock.Lock()
@@ -267,7 +455,12 @@ func (r *reconciler) ensureControllers(ctx context.Context, sync *syncInfo, expo
 		exporterName: export.Name,
 	}] = syncContext{
 		generation: export.Generation,
-		cancel:     cancel,
+		tuning:     tuning,
+		stop:       cancel,
+		cancel: func() {
+			cancel()
+			releaseHandles()
+		},
 	}
 
 	return utilerrors.NewAggregate(errs)
@@ -0,0 +1,208 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/related"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+	"sigs.k8s.io/structured-merge-diff/v4/value"
+)
+
+// consumerFieldSet returns the union of the fields of obj that are managed by
+// field managers other than the ignored ones, i.e. the fields the consumer
+// actually set. It returns false if obj has no managed fields to go by.
+func consumerFieldSet(obj *unstructured.Unstructured, ignoredManagers ...string) (*fieldpath.Set, bool) {
+	entries := obj.GetManagedFields()
+	if len(entries) == 0 {
+		return nil, false
+	}
+
+	set := fieldpath.NewSet()
+	for _, entry := range entries {
+		if entry.Subresource != "" || entry.FieldsV1 == nil || isIgnoredManager(entry.Manager, ignoredManagers) {
+			continue
+		}
+		entrySet := fieldpath.NewSet()
+		if err := entrySet.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, false
+		}
+		set = set.Union(entrySet)
+	}
+	return set, true
+}
+
+func isIgnoredManager(manager string, ignoredManagers []string) bool {
+	for _, m := range ignoredManagers {
+		if m == manager {
+			return true
+		}
+	}
+	return false
+}
+
+// filterFields returns the parts of v that are contained in set. Leaf members
+// of set are copied as a whole.
+func filterFields(v interface{}, set *fieldpath.Set) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			pe := fieldpath.PathElement{FieldName: &k}
+			if set.Members.Has(pe) {
+				out[k] = child
+			} else if childSet, found := set.Children.Get(pe); found {
+				out[k] = filterFields(child, childSet)
+			}
+		}
+		return out
+	case []interface{}:
+		var out []interface{}
+		for i, item := range v {
+			if pe, found := listItemPathElement(set, i, item); found {
+				if set.Members.Has(pe) {
+					out = append(out, item)
+				} else if childSet, found := set.Children.Get(pe); found {
+					out = append(out, filterFields(item, childSet))
+				}
+			}
+		}
+		return out
+	}
+	return v
+}
+
+// listItemPathElement finds the path element in set that identifies the given
+// list item, either by its associative keys, by its value or by its index.
+func listItemPathElement(set *fieldpath.Set, index int, item interface{}) (fieldpath.PathElement, bool) {
+	var match fieldpath.PathElement
+	found := false
+	matches := func(pe fieldpath.PathElement) {
+		if found {
+			return
+		}
+		switch {
+		case pe.Index != nil:
+			found = *pe.Index == index
+		case pe.Value != nil:
+			found = value.Equals(*pe.Value, value.NewValueInterface(item))
+		case pe.Key != nil:
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				return
+			}
+			found = true
+			for _, f := range *pe.Key {
+				fv, ok := m[f.Name]
+				if !ok || !value.Equals(f.Value, value.NewValueInterface(fv)) {
+					found = false
+					return
+				}
+			}
+		}
+		if found {
+			match = pe
+		}
+	}
+	set.Members.Iterate(matches)
+	set.Children.Iterate(matches)
+	return match, found
+}
+
+// conflictingManagers returns the field managers the given apply conflict error
+// names.
+func conflictingManagers(err error) []string {
+	status, ok := err.(errors.APIStatus)
+	if !ok || status.Status().Details == nil {
+		return nil
+	}
+	var managers []string
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(strings.TrimPrefix(cause.Message, "conflict with "))
+		if err != nil {
+			continue
+		}
+		if manager, err := strconv.Unquote(quoted); err == nil {
+			managers = append(managers, manager)
+		}
+	}
+	return managers
+}
+
+// defaultKonnectorManager returns the field manager name the API servers
+// derive from the user agent of this binary for non-apply requests.
+func defaultKonnectorManager() string {
+	return strings.SplitN(rest.DefaultKubernetesUserAgent(), "/", 2)[0]
+}
+
+// providerSource returns a copy of the fields of obj other than metadata, to be
+// merged into the upstream object. The spec is limited to the fields the
+// consumer set on the downstream object, if they are known from its managed
+// fields.
+func providerSource(obj, downstream *unstructured.Unstructured, konnectorManager string) map[string]interface{} {
+	source := runtime.DeepCopyJSON(obj.Object)
+	delete(source, "metadata")
+	spec, found := source["spec"]
+	if !found {
+		return source
+	}
+	if fields, ok := consumerFieldSet(downstream, konnectorManager); ok {
+		specField := "spec"
+		if specFields, found := fields.Children.Get(fieldpath.PathElement{FieldName: &specField}); found {
+			source["spec"] = filterFields(spec, specFields)
+		} else if !fields.Members.Has(fieldpath.PathElement{FieldName: &specField}) {
+			source["spec"] = map[string]interface{}{}
+		}
+	}
+	return source
+}
+
+// UpstreamSpecs returns the spec the downstream object is applied upstream
+// with, and the spec of the upstream object limited to the same fields. Both
+// are equal if the upstream object is in sync. References to related objects
+// of namespaced objects point to their copies, as they do upstream.
+func UpstreamSpecs(policy *fieldsync.Policy, downstream, upstream *unstructured.Unstructured) (desired, actual interface{}) {
+	konnectorManager := defaultKonnectorManager()
+
+	want := map[string]interface{}{}
+	policy.Merge(want, providerSource(downstream, downstream, konnectorManager), v1alpha1.SyncDirectionToProvider)
+	if downstream.GetNamespace() != "" {
+		syncer := related.Syncer{Suffix: relatedSuffix}
+		for _, ref := range policy.References(v1alpha1.SyncDirectionToProvider) {
+			syncer.Rewrite(ref, want)
+		}
+	}
+
+	got := map[string]interface{}{}
+	policy.Merge(got, providerSource(upstream, downstream, konnectorManager), v1alpha1.SyncDirectionToProvider)
+
+	return want["spec"], got["spec"]
+}
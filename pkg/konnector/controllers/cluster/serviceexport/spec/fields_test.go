@@ -0,0 +1,80 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+func TestConsumerFieldSet(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"size":    int64(3),
+			"version": "1.2.3",
+			"ports": []interface{}{
+				map[string]interface{}{"name": "http", "port": int64(80)},
+				map[string]interface{}{"name": "https", "port": int64(443)},
+			},
+			"tags": []interface{}{"a", "b"},
+		},
+	}}
+	obj.SetManagedFields([]metav1.ManagedFieldsEntry{
+		{
+			Manager:    "kubectl",
+			Operation:  metav1.ManagedFieldsOperationApply,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:size":{},"f:ports":{"k:{\"name\":\"http\"}":{".":{},"f:name":{},"f:port":{}}},"f:tags":{"v:\"b\"":{}}}}`)},
+		},
+		{
+			Manager:    "konnector",
+			Operation:  metav1.ManagedFieldsOperationUpdate,
+			FieldsType: "FieldsV1",
+			FieldsV1:   &metav1.FieldsV1{Raw: []byte(`{"f:spec":{"f:version":{}}}`)},
+		},
+		{
+			Manager:     "kubectl",
+			Operation:   metav1.ManagedFieldsOperationUpdate,
+			Subresource: "status",
+			FieldsType:  "FieldsV1",
+			FieldsV1:    &metav1.FieldsV1{Raw: []byte(`{"f:status":{"f:phase":{}}}`)},
+		},
+	})
+
+	set, ok := consumerFieldSet(obj, "konnector")
+	require.True(t, ok)
+	specField := "spec"
+	specSet, found := set.Children.Get(fieldpath.PathElement{FieldName: &specField})
+	require.True(t, found)
+
+	spec, _, err := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"size": int64(3),
+		"ports": []interface{}{
+			map[string]interface{}{"name": "http", "port": int64(80)},
+		},
+		"tags": []interface{}{"b"},
+	}, filterFields(spec, specSet))
+
+	_, ok = consumerFieldSet(&unstructured.Unstructured{Object: map[string]interface{}{}})
+	require.False(t, ok)
+}
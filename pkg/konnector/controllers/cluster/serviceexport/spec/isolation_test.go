@@ -0,0 +1,169 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/related"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// fakeProvider stores the upstream objects of a resource that is namespaced or
+// cluster-scoped in the provider cluster, like the API server rejecting objects
+// of the wrong scope.
+type fakeProvider struct {
+	namespaced bool
+	objects    map[string]*unstructured.Unstructured
+	writes     int
+}
+
+func (p *fakeProvider) get(_ *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+	obj, found := p.objects[ns+"/"+name]
+	if !found {
+		return nil, apierrors.NewNotFound(schema.GroupResource{Group: "example.com", Resource: "foos"}, name)
+	}
+	return obj.DeepCopy(), nil
+}
+
+func (p *fakeProvider) apply(_ context.Context, _ *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, _ bool, _ ...string) (*unstructured.Unstructured, error) {
+	if p.namespaced != (obj.GetNamespace() != "") {
+		return nil, fmt.Errorf("namespace %q of %s does not match the scope of the resource", obj.GetNamespace(), obj.GetName())
+	}
+	key := obj.GetNamespace() + "/" + obj.GetName()
+	applied := obj.DeepCopy()
+	applied.SetResourceVersion("1")
+	if existing, found := p.objects[key]; found {
+		applied.SetResourceVersion(existing.GetResourceVersion())
+		if equality.Semantic.DeepEqual(applied.Object, existing.Object) {
+			return applied.DeepCopy(), nil
+		}
+		rv, _ := strconv.Atoi(existing.GetResourceVersion())
+		applied.SetResourceVersion(strconv.Itoa(rv + 1))
+	}
+	p.objects[key] = applied
+	p.writes++
+	return applied.DeepCopy(), nil
+}
+
+func TestReconcileIsolation(t *testing.T) {
+	const clusterNs = "kube-bind-zlp9m"
+
+	tests := []struct {
+		name              string
+		isolation         v1alpha1.Isolation
+		namespaced        bool
+		expectedNamespace string
+		expectedName      string
+	}{
+		{
+			name:         "prefixed",
+			isolation:    v1alpha1.IsolationPrefixed,
+			expectedName: "kube-bind-zlp9m-example-foo",
+		},
+		{
+			name:              "namespaced",
+			isolation:         v1alpha1.IsolationNamespaced,
+			namespaced:        true,
+			expectedNamespace: clusterNs,
+			expectedName:      "example-foo",
+		},
+		{
+			name:         "none",
+			isolation:    v1alpha1.IsolationNone,
+			expectedName: "example-foo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolator, err := clusterscoped.NewIsolator(tt.isolation)
+			require.NoError(t, err)
+			policy, err := fieldsync.NewPolicy(&v1alpha1.APIServiceExportSpec{}, nil)
+			require.NoError(t, err)
+
+			provider := &konnectormodels.ProviderInfo{Namespace: clusterNs, NamespaceUID: "real-identity", ClusterID: "abc"}
+			upstream := &fakeProvider{namespaced: tt.namespaced, objects: map[string]*unstructured.Unstructured{}}
+			r := &reconciler{
+				isolator:          isolator,
+				policy:            policy,
+				statusSubresource: true,
+				getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
+					return provider, nil
+				},
+				getUpstreamObject:   upstream.get,
+				applyUpstreamObject: upstream.apply,
+				newRelatedSyncer: func(provider *konnectormodels.ProviderInfo) related.Syncer {
+					return related.Syncer{}
+				},
+				updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					return obj, nil
+				},
+				updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					return obj, nil
+				},
+			}
+
+			obj := &unstructured.Unstructured{Object: map[string]interface{}{
+				"apiVersion": "example.com/v1",
+				"kind":       "Foo",
+				"spec":       map[string]interface{}{"size": int64(3)},
+			}}
+			obj.SetName("example-foo")
+			obj.SetFinalizers([]string{v1alpha1.DownstreamFinalizer})
+
+			// creates the upstream object in its isolated place
+			require.NoError(t, r.reconcile(context.Background(), obj))
+			require.Len(t, upstream.objects, 1)
+			created, found := upstream.objects[tt.expectedNamespace+"/"+tt.expectedName]
+			require.True(t, found, "upstream objects: %v", upstream.objects)
+			require.True(t, clusterscoped.IsOwnedBy(created, clusterNs))
+			require.Equal(t, map[string]interface{}{"size": int64(3)}, created.Object["spec"])
+
+			// finds the upstream object again, and updates it in place
+			require.NoError(t, r.reconcile(context.Background(), obj))
+			require.Equal(t, 1, upstream.writes)
+			require.NoError(t, unstructured.SetNestedField(obj.Object, int64(5), "spec", "size"))
+			require.NoError(t, r.reconcile(context.Background(), obj))
+			require.Len(t, upstream.objects, 1)
+			require.Equal(t, 2, upstream.writes)
+			require.Equal(t, map[string]interface{}{"size": int64(5)}, upstream.objects[tt.expectedNamespace+"/"+tt.expectedName].Object["spec"])
+
+			// an object of another consumer in the same place is not touched
+			foreign := &unstructured.Unstructured{Object: map[string]interface{}{}}
+			foreign.SetName("example-foo")
+			require.NoError(t, isolator.ToUpstream(foreign, "kube-bind-s85lc", "other-identity"))
+			foreign.SetNamespace(tt.expectedNamespace)
+			foreign.SetName(tt.expectedName)
+			upstream.objects = map[string]*unstructured.Unstructured{tt.expectedNamespace + "/" + tt.expectedName: foreign}
+			err = r.reconcile(context.Background(), obj)
+			require.True(t, clusterscoped.IsCollision(err), "expected collision, got %v", err)
+			require.Equal(t, 2, upstream.writes)
+		})
+	}
+}
@@ -20,12 +20,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"sync"
 	"time"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/downstream"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/related"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -34,6 +41,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/wait"
 	dynamicclient "k8s.io/client-go/dynamic"
 	"k8s.io/client-go/dynamic/dynamiclister"
@@ -48,17 +56,38 @@ import (
 const (
 	controllerName = "kube-bind-konnector-cluster-spec"
 
-	applyManager = "kube-bind.appscode.com"
+	// applyManager is the field manager of the server-side applies of upstream objects.
+	applyManager = "kube-bind-konnector"
+	// legacyApplyManager is the field manager used by earlier konnector versions.
+	legacyApplyManager = "kube-bind.appscode.com"
+
+	// relatedSuffix is appended to the names of related objects copied to the provider.
+	relatedSuffix = "-consumer"
 )
 
 // NewController returns a new controller reconciling downstream objects to upstream.
+// The isolator is nil for namespaced resources. The related informers by kind
+// watch the consumer objects referenced by related resources synced to the provider.
+// The provider informers of the synced objects are by provider cluster ID.
+// Without status subresource, the conditions of downstream objects are kept in an
+// annotation. The deletion policy applies to objects without deletion policy
+// annotation. The queue name is appended to the controller name to tell the
+// queues of different exports apart.
 func NewController(
 	gvr schema.GroupVersionResource,
+	isolator clusterscoped.Isolator,
+	policy *fieldsync.Policy,
+	statusSubresource bool,
+	deletionPolicy kubebindv1alpha1.DeletionPolicy,
 	consumerConfig *rest.Config,
 	consumerDynamicInformer informers.GenericInformer,
+	relatedInformers map[string]informers.GenericInformer,
+	providerInformers map[string]multinsinformer.GetterInformer,
+	queueName string,
+	rateLimiter workqueue.RateLimiter,
 	providerInfos []*konnectormodels.ProviderInfo,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter, controllerName+"-"+queueName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -81,18 +110,32 @@ func NewController(
 		return nil, err
 	}
 
+	konnectorManager := defaultKonnectorManager()
+
 	dynamicConsumerLister := dynamiclister.New(consumerDynamicInformer.Informer().GetIndexer(), gvr)
 	c := &controller{
 		queue: queue,
 
+		gvr: gvr,
+
 		consumerClient: consumerClient,
 
 		consumerDynamicLister:  dynamicConsumerLister,
 		consumerDynamicIndexer: consumerDynamicInformer.Informer().GetIndexer(),
 
 		providerInfos: providerInfos,
+		isolator:      isolator,
+
+		parked: map[string]sets.Set[string]{},
 
 		reconciler: reconciler{
+			isolator:          isolator,
+			policy:            policy,
+			statusSubresource: statusSubresource,
+			deletionPolicy:    deletionPolicy,
+			konnectorManager:  konnectorManager,
+			ownManagers:       []string{applyManager, legacyApplyManager, konnectorManager},
+
 			getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
 				anno := obj.GetAnnotations()
 				clusterID := anno[konnectormodels.AnnotationProviderClusterID]
@@ -116,78 +159,72 @@ func NewController(
 			createServiceNamespace: func(ctx context.Context, provider *konnectormodels.ProviderInfo, sn *kubebindv1alpha1.APIServiceNamespace) (*kubebindv1alpha1.APIServiceNamespace, error) {
 				return provider.BindClient.KubeBindV1alpha1().APIServiceNamespaces(provider.Namespace).Create(ctx, sn, metav1.CreateOptions{})
 			},
-			getProviderObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
-				if ns != "" {
-					obj, err := provider.ProviderDynamicInformer.Get(ns, name)
-					if err != nil {
-						return nil, err
-					}
-					return obj.(*unstructured.Unstructured), nil
-				}
-				got, err := provider.ProviderDynamicInformer.Get(ns, clusterscoped.Prepend(name, provider.Namespace))
+			getUpstreamObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+				obj, err := providerInformers[provider.ClusterID].Get(ns, name)
 				if err != nil {
 					return nil, err
 				}
-				obj := got.(*unstructured.Unstructured).DeepCopy()
-				err = clusterscoped.TranslateFromUpstream(obj)
-				if err != nil {
-					return nil, err
-				}
-				return obj, nil
+				return obj.(*unstructured.Unstructured), nil
 			},
-			createProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-				if ns := obj.GetNamespace(); ns != "" {
-					return provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
-				}
-				err := clusterscoped.TranslateFromDownstream(obj, provider.Namespace, provider.NamespaceUID)
-				if err != nil {
-					return nil, err
-				}
-				created, err := provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
-				if err != nil {
-					return nil, err
-				}
-				err = clusterscoped.TranslateFromUpstream(created)
+			applyUpstreamObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, force bool, subresources ...string) (*unstructured.Unstructured, error) {
+				data, err := json.Marshal(obj.Object)
 				if err != nil {
 					return nil, err
 				}
-				return created, nil
+				return provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).Patch(ctx,
+					obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: applyManager, Force: ptr.To(force)}, subresources...,
+				)
 			},
-			updateProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-				ns := obj.GetNamespace()
+			deleteProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
 				if ns == "" {
-					if err := clusterscoped.TranslateFromDownstream(obj, provider.Namespace, provider.NamespaceUID); err != nil {
-						return nil, err
-					}
+					ns, name = isolator.UpstreamKey(name, provider.Namespace)
 				}
-				data, err := json.Marshal(obj.Object)
-				if err != nil {
-					return nil, err
+				return provider.Client.Resource(gvr).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+			orphanProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
+				if ns == "" {
+					ns, name = isolator.UpstreamKey(name, provider.Namespace)
 				}
-				patched, err := provider.Client.Resource(gvr).Namespace(obj.GetNamespace()).Patch(ctx,
-					obj.GetName(), types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: applyManager, Force: ptr.To(true)},
-				)
+				patch, err := downstream.OrphanPatch(time.Now())
 				if err != nil {
-					return nil, err
+					return err
 				}
-				if ns == "" {
-					err = clusterscoped.TranslateFromUpstream(patched)
-					if err != nil {
-						return nil, err
-					}
-					return patched, nil
+				if _, err := provider.Client.Resource(gvr).Namespace(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+					return err
 				}
-				return patched, nil
+				klog.FromContext(ctx).Info("orphaned upstream object", "upstreamNamespace", ns, "upstreamName", name)
+				metrics.RecordOrphaned(gvr.GroupResource().String())
+				return nil
 			},
-			deleteProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
+			adoptProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
 				if ns == "" {
-					name = clusterscoped.Prepend(name, provider.Namespace)
+					ns, name = isolator.UpstreamKey(name, provider.Namespace)
 				}
-				return provider.Client.Resource(gvr).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
+				patch, err := downstream.AdoptPatch()
+				if err != nil {
+					return err
+				}
+				_, err = provider.Client.Resource(gvr).Namespace(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{})
+				return err
 			},
 			updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
 				return consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
 			},
+			updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				updated, err := consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+				if errors.IsNotFound(err) {
+					// no status subresource. Status is part of the main resource.
+					return consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
+				}
+				return updated, err
+			},
+			newRelatedSyncer: func(provider *konnectormodels.ProviderInfo) related.Syncer {
+				return related.Syncer{
+					Source: consumerClient,
+					Target: provider.Client,
+					Suffix: relatedSuffix,
+				}
+			},
 			requeue: func(obj *unstructured.Unstructured, after time.Duration) error {
 				key, err := cache.MetaNamespaceKeyFunc(obj)
 				if err != nil {
@@ -198,6 +235,37 @@ func NewController(
 			},
 		},
 	}
+	c.park = func(provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) error {
+		key, err := cache.MetaNamespaceKeyFunc(obj)
+		if err != nil {
+			return err
+		}
+		c.parkedLock.Lock()
+		defer c.parkedLock.Unlock()
+		if c.parked[provider.ClusterID] == nil {
+			c.parked[provider.ClusterID] = sets.New[string]()
+		}
+		c.parked[provider.ClusterID].Insert(key)
+		return nil
+	}
+
+	// objects are synced again when the related objects they reference change
+	for kind, inf := range relatedInformers {
+		_, err := inf.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				c.enqueueRelated(logger, kind, obj)
+			},
+			UpdateFunc: func(_, newObj interface{}) {
+				c.enqueueRelated(logger, kind, newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+				c.enqueueRelated(logger, kind, obj)
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	_, err = consumerDynamicInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
@@ -215,7 +283,7 @@ func NewController(
 	}
 
 	for _, provider := range providerInfos {
-		provider.ProviderDynamicInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		providerInformers[provider.ClusterID].AddEventHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				c.enqueueProvider(logger, provider, obj)
 			},
@@ -235,12 +303,20 @@ func NewController(
 type controller struct {
 	queue workqueue.RateLimitingInterface
 
+	gvr schema.GroupVersionResource
+
 	consumerClient dynamicclient.Interface
 
 	consumerDynamicLister  dynamiclister.Lister
 	consumerDynamicIndexer cache.Indexer
 
 	providerInfos []*konnectormodels.ProviderInfo
+	isolator      clusterscoped.Isolator
+
+	// parked are the keys held back by provider cluster ID while the provider
+	// is unreachable.
+	parkedLock sync.Mutex
+	parked     map[string]sets.Set[string]
 
 	reconciler
 }
@@ -256,6 +332,36 @@ func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
 	c.queue.Add(key)
 }
 
+func (c *controller) enqueueRelated(logger klog.Logger, kind string, obj interface{}) {
+	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	ns, _, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	// the consumer informer is shared with other syncers and cannot get an
+	// index of the references, hence the objects of the namespace are checked.
+	objs, err := c.consumerDynamicIndexer.ByIndex(cache.NamespaceIndex, ns)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	refs := c.policy.References(kubebindv1alpha1.SyncDirectionToProvider)
+	for _, obj := range objs {
+		if !slices.Contains(relatedObjectKeys(refs, obj), kind+"/"+key) {
+			continue
+		}
+		logger.V(2).Info("queueing Unstructured", "reason", "RelatedObjectChanged", "kind", kind, "related", key)
+		c.enqueueConsumer(logger, obj)
+	}
+}
+
 func (c *controller) enqueueProvider(logger klog.Logger, provider *konnectormodels.ProviderInfo, obj interface{}) {
 	upstreamKey, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
@@ -268,33 +374,33 @@ func (c *controller) enqueueProvider(logger klog.Logger, provider *konnectormode
 		return
 	}
 
-	if ns != "" {
-		sns, err := provider.DynamicServiceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, ns)
-		if err != nil {
-			if !errors.IsNotFound(err) {
-				runtime.HandleError(err)
-			}
+	if c.isolator != nil {
+		downstreamName, ok := c.isolator.DownstreamName(ns, name, provider.Namespace)
+		if !ok {
+			logger.V(3).Info("skipping because consumer mismatch", "upstreamKey", upstreamKey)
 			return
 		}
-		for _, obj := range sns {
-			sn := obj.(*kubebindv1alpha1.APIServiceNamespace)
-			if sn.Namespace == provider.Namespace {
-				key := fmt.Sprintf("%s/%s", sn.Name, name)
-				logger.V(2).Info("queueing Unstructured", "key", key)
-				c.queue.Add(key)
-				return
-			}
-		}
+		logger.V(2).Info("queueing Unstructured", "key", downstreamName)
+		c.queue.Add(downstreamName)
 		return
 	}
 
-	if clusterscoped.Behead(upstreamKey, provider.Namespace) == upstreamKey {
-		logger.V(3).Info("skipping because consumer mismatch", "upstreamKey", upstreamKey)
+	sns, err := provider.DynamicServiceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, ns)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			runtime.HandleError(err)
+		}
 		return
 	}
-	downstreamKey := clusterscoped.Behead(upstreamKey, provider.Namespace)
-	logger.V(2).Info("queueing Unstructured", "key", downstreamKey)
-	c.queue.Add(downstreamKey)
+	for _, obj := range sns {
+		sn := obj.(*kubebindv1alpha1.APIServiceNamespace)
+		if sn.Namespace == provider.Namespace {
+			key := fmt.Sprintf("%s/%s", sn.Name, name)
+			logger.V(2).Info("queueing Unstructured", "key", key)
+			c.queue.Add(key)
+			return
+		}
+	}
 }
 
 func (c *controller) enqueueServiceNamespace(logger klog.Logger, provider *konnectormodels.ProviderInfo, obj interface{}) {
@@ -352,6 +458,14 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 		})
 	}
 
+	for _, provider := range c.providerInfos {
+		provider.Reachability.Subscribe(ctx, func(reachable bool) {
+			if reachable {
+				c.unpark(logger, provider)
+			}
+		})
+	}
+
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
@@ -359,6 +473,21 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 	<-ctx.Done()
 }
 
+// unpark queues the keys held back while the provider was unreachable. They
+// go through the rate limiter such that the backlog is synced at a controlled
+// pace instead of all at once.
+func (c *controller) unpark(logger klog.Logger, provider *konnectormodels.ProviderInfo) {
+	c.parkedLock.Lock()
+	keys := c.parked[provider.ClusterID]
+	delete(c.parked, provider.ClusterID)
+	c.parkedLock.Unlock()
+
+	logger.V(1).Info("provider cluster reachable again, syncing held back objects", "clusterID", provider.ClusterID, "count", keys.Len())
+	for key := range keys {
+		c.queue.AddRateLimited(key)
+	}
+}
+
 func (c *controller) startWorker(ctx context.Context) {
 	defer runtime.HandleCrash()
 
@@ -382,7 +511,9 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	err := c.process(ctx, key)
+	metrics.RecordSync(controllerName, c.gvr.GroupResource().String(), err)
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		c.queue.AddRateLimited(key)
 		return true
@@ -415,3 +546,20 @@ func (c *controller) process(ctx context.Context, key string) error {
 
 	return c.reconcile(ctx, obj)
 }
+
+// relatedObjectKeys returns the kind, namespace and name of the related
+// objects a consumer object references.
+func relatedObjectKeys(refs []fieldsync.Reference, obj interface{}) []string {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok || u.GetNamespace() == "" {
+		return nil
+	}
+
+	var keys []string
+	for _, ref := range refs {
+		for _, name := range ref.Names(u.Object) {
+			keys = append(keys, ref.Kind+"/"+u.GetNamespace()+"/"+name)
+		}
+	}
+	return keys
+}
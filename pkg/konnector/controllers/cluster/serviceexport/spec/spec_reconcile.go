@@ -18,33 +18,65 @@ package spec
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"reflect"
+	"strings"
 	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/downstream"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/related"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/klog/v2"
+	conditionsapi "kmodules.xyz/client-go/api/v1"
 )
 
 type reconciler struct {
+	// konnectorManager is the field manager of the konnector's own non-apply
+	// requests. Fields it manages downstream are not consumer fields.
+	konnectorManager string
+	// ownManagers are the field managers upstream fields can be taken over from
+	// without reporting a conflict.
+	ownManagers []string
+
+	// isolator maps cluster-scoped objects onto their provider side representation.
+	// It is nil for namespaced resources.
+	isolator clusterscoped.Isolator
+
+	policy *fieldsync.Policy
+	// statusSubresource is true if the downstream resource has a status
+	// subresource. Otherwise, conditions are kept in an annotation.
+	statusSubresource bool
+	// deletionPolicy is the deletion policy of objects without deletion policy
+	// annotation.
+	deletionPolicy v1alpha1.DeletionPolicy
+
 	getProviderInfo        func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error)
 	getServiceNamespace    func(provider *konnectormodels.ProviderInfo, name string) (*v1alpha1.APIServiceNamespace, error)
 	createServiceNamespace func(ctx context.Context, provider *konnectormodels.ProviderInfo, sn *v1alpha1.APIServiceNamespace) (*v1alpha1.APIServiceNamespace, error)
 
-	getProviderObject    func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error)
-	createProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
-	updateProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	// getUpstreamObject and applyUpstreamObject work on the provider side
+	// representation of objects.
+	getUpstreamObject    func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error)
+	applyUpstreamObject  func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, force bool, subresources ...string) (*unstructured.Unstructured, error)
 	deleteProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error
+	orphanProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error
+	adoptProviderObject  func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error
+
+	updateConsumerObject       func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	updateConsumerObjectStatus func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
 
-	updateConsumerObject func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	newRelatedSyncer func(provider *konnectormodels.ProviderInfo) related.Syncer
 
 	requeue func(obj *unstructured.Unstructured, after time.Duration) error
+	// park holds back obj until the provider cluster is reachable again.
+	park func(provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) error
 }
 
 // reconcile syncs downstream objects (metadata and spec) with upstream objects.
@@ -59,6 +91,32 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 
 	klog.Infof(fmt.Sprintf("reconciling object %s/%s for provider %s", obj.GetNamespace(), obj.GetName(), provider.ClusterID))
 
+	if downstream.IsPaused(obj) {
+		logger.V(2).Info("sync is paused")
+		return r.updateConditions(ctx, obj, []conditionsapi.Condition{{
+			Type:     v1alpha1.DownstreamConditionPaused,
+			Status:   metav1.ConditionTrue,
+			Severity: conditionsapi.ConditionSeverityInfo,
+			Reason:   "Paused",
+			Message:  fmt.Sprintf("Sync is paused by the %s annotation.", v1alpha1.DownstreamPausedAnnotation),
+		}})
+	}
+
+	if !provider.Reachability.Reachable() {
+		logger.V(2).Info("provider cluster unreachable, holding back sync")
+		if err := r.park(provider, obj); err != nil {
+			return err
+		}
+		return r.updateConditions(ctx, obj, []conditionsapi.Condition{{
+			Type:               v1alpha1.DownstreamConditionSynced,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: obj.GetGeneration(),
+			Severity:           conditionsapi.ConditionSeverityWarning,
+			Reason:             "ProviderUnreachable",
+			Message:            fmt.Sprintf("Provider cluster %s is unreachable. The object is synced when it is reachable again.", provider.ClusterID),
+		}}, v1alpha1.DownstreamConditionPaused)
+	}
+
 	ns := obj.GetNamespace()
 	if ns != "" {
 		sn, err := r.getServiceNamespace(provider, ns)
@@ -73,7 +131,7 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 				},
 			})
 			if err != nil {
-				return err
+				return r.setSyncFailed(ctx, obj, "", "ServiceNamespaceFailed", err)
 			}
 		}
 		if sn.Status.Namespace == "" {
@@ -90,7 +148,14 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 	}
 
 	upstream, err := r.getProviderObject(provider, ns, obj.GetName())
-	if err != nil && !errors.IsNotFound(err) {
+	if clusterscoped.IsCollision(err) {
+		if obj.GetDeletionTimestamp() != nil && !obj.GetDeletionTimestamp().IsZero() {
+			logger.V(2).Info("upstream object belongs to another consumer, don't sync deletion")
+			_, err := r.removeDownstreamFinalizer(ctx, obj)
+			return err
+		}
+		return err
+	} else if err != nil && !errors.IsNotFound(err) {
 		return err
 	} else if errors.IsNotFound(err) {
 		if obj.GetDeletionTimestamp() != nil && !obj.GetDeletionTimestamp().IsZero() {
@@ -108,24 +173,8 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 			return err
 		}
 
-		// clean up object
-		upstream = obj.DeepCopy()
-		upstream.SetUID("")
-		upstream.SetResourceVersion("")
-		upstream.SetNamespace(ns)
-		upstream.SetManagedFields(nil)
-		upstream.SetDeletionTimestamp(nil)
-		upstream.SetDeletionGracePeriodSeconds(nil)
-		upstream.SetOwnerReferences(nil)
-		upstream.SetFinalizers(nil)
-		unstructured.RemoveNestedField(upstream.Object, "status")
-
 		logger.Info("Creating upstream object")
-		if _, err := r.createProviderObject(ctx, provider, upstream); err != nil && !errors.IsAlreadyExists(err) {
-			return err
-		} else if errors.IsAlreadyExists(err) {
-			logger.Info("Upstream object already exists. Waiting for requeue.") // the upstream object will lead to a requeue
-		}
+		return r.applyUpstream(ctx, provider, obj, ns, nil)
 	}
 
 	// here the upstream already exists. Update everything but the status.
@@ -136,61 +185,323 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 			return nil // we will get an event when the upstream is deleted
 		}
 
-		logger.V(1).Info("object is already deleting downstream, deleting upstream too")
-		if err := r.deleteProviderObject(ctx, provider, ns, obj.GetName()); err != nil && !errors.IsNotFound(err) {
-			return err
+		switch policy := downstream.DeletionPolicy(obj, r.deletionPolicy); policy {
+		case v1alpha1.DeletionPolicyOrphan:
+			logger.Info("object is already deleting downstream, orphaning upstream")
+			if err := r.orphanProviderObject(ctx, provider, ns, obj.GetName()); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+		case v1alpha1.DeletionPolicyRetain:
+			logger.V(1).Info("object is already deleting downstream, retaining upstream")
+		default:
+			logger.V(1).Info("object is already deleting downstream, deleting upstream too")
+			if err := r.deleteProviderObject(ctx, provider, ns, obj.GetName()); err != nil && !errors.IsNotFound(err) {
+				return err
+			}
 		}
 
 		if _, err := r.removeDownstreamFinalizer(ctx, obj); err != nil {
 			return err
 		}
 
-		logger.V(2).Info("upstream deleted, finalizer removed in downstream, waiting for downstream deletion to finish")
+		logger.V(2).Info("upstream handled, finalizer removed in downstream, waiting for downstream deletion to finish")
 		return nil // we will get an event when the upstream is deleted
 	}
 
+	if downstream.IsOrphaned(upstream) {
+		logger.Info("Adopting orphaned upstream object")
+		if err := r.adoptProviderObject(ctx, provider, ns, obj.GetName()); err != nil {
+			return err
+		}
+	}
+
 	// just in case, checking for finalizer
 	if obj, err = r.ensureDownstreamFinalizer(ctx, obj); err != nil {
 		klog.Errorf(err.Error())
 		return err
 	}
 
-	downstreamSpec, foundDownstreamSpec, err := unstructured.NestedFieldNoCopy(obj.Object, "spec")
+	return r.applyUpstream(ctx, provider, obj, ns, upstream)
+}
+
+// applyUpstream server-side applies the metadata and the spec fields the consumer
+// set on the downstream object to the upstream object, which is nil if it does
+// not exist yet. Field ownership conflicts with other field managers are reported
+// as condition on the downstream object.
+func (r *reconciler) applyUpstream(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, ns string, upstream *unstructured.Unstructured) error {
+	logger := klog.FromContext(ctx)
+
+	desired := &unstructured.Unstructured{}
+	desired.SetAPIVersion(obj.GetAPIVersion())
+	desired.SetKind(obj.GetKind())
+	desired.SetNamespace(ns)
+	desired.SetName(obj.GetName())
+	desired.SetLabels(r.policy.FilterLabels(obj.GetLabels(), v1alpha1.SyncDirectionToProvider))
+	annotations := r.policy.FilterAnnotations(obj.GetAnnotations(), v1alpha1.SyncDirectionToProvider)
+	if clusterID, found := obj.GetAnnotations()[konnectormodels.AnnotationProviderClusterID]; found {
+		// the status controller finds the provider by it
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[konnectormodels.AnnotationProviderClusterID] = clusterID
+	}
+	if policy, found := obj.GetAnnotations()[v1alpha1.DeletionPolicyAnnotation]; found {
+		// the status controller applies it when the downstream object is gone
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[v1alpha1.DeletionPolicyAnnotation] = policy
+	}
+	desired.SetAnnotations(annotations)
+
+	r.policy.Merge(desired.Object, providerSource(obj, obj, r.konnectorManager), v1alpha1.SyncDirectionToProvider)
+
+	// references to related objects of namespaced service objects point to their copies
+	syncer := r.newRelatedSyncer(provider)
+	refs := r.policy.References(v1alpha1.SyncDirectionToProvider)
+	if obj.GetNamespace() == "" {
+		refs = nil
+	}
+	relatedNames := make([][]string, len(refs))
+	for i, ref := range refs {
+		relatedNames[i] = syncer.Rewrite(ref, desired.Object)
+	}
+
+	upstreamKey := obj.GetName()
+	if ns != "" {
+		upstreamKey = ns + "/" + upstreamKey
+	}
+
+	// The apply is sent unconditionally: fields the consumer removed are only
+	// removed upstream by applying without them, and an apply without changes
+	// does not write the object.
+	logger.V(2).Info("Applying upstream object")
+	applied, conflicted, err := r.applyWithConflicts(ctx, provider, desired)
+	if conflicted {
+		return r.setFieldConflict(ctx, obj, upstreamKey, err)
+	} else if err != nil {
+		return r.setSyncFailed(ctx, obj, upstreamKey, "ApplyFailed", err)
+	}
+	owner := applied
+	written := isWritten(upstream, applied)
+
+	// status fields synced to the provider go through the status subresource
+	if _, found := desired.Object["status"]; found {
+		logger.V(2).Info("Applying upstream object status")
+		if appliedStatus, conflicted, err := r.applyWithConflicts(ctx, provider, desired, "status"); conflicted {
+			return r.setFieldConflict(ctx, obj, upstreamKey, err)
+		} else if err != nil && !errors.IsNotFound(err) {
+			// not found means there is no status subresource
+			return r.setSyncFailed(ctx, obj, upstreamKey, "ApplyFailed", err)
+		} else if err == nil {
+			written = written || isWritten(applied, appliedStatus)
+		}
+	}
+
+	for i, ref := range refs {
+		var stale []string
+		if upstream != nil {
+			stale = ref.Names(upstream.Object)
+		}
+		if err := syncer.Sync(ctx, ref, relatedNames[i], obj.GetNamespace(), owner, stale); err != nil {
+			return r.setSyncFailed(ctx, obj, upstreamKey, "RelatedResourceSyncFailed", err)
+		}
+	}
+
+	return r.setSynced(ctx, obj, upstreamKey, written)
+}
+
+// isWritten returns true if applying changed the upstream object, i.e. it did
+// not exist before or got a new resource version.
+func isWritten(before, after *unstructured.Unstructured) bool {
+	return before == nil || after == nil || before.GetResourceVersion() != after.GetResourceVersion()
+}
+
+// applyWithConflicts applies obj to the provider, taking over fields from previous
+// konnector field managers. It returns true with the conflict error if fields are
+// owned by other field managers.
+func (r *reconciler) applyWithConflicts(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, subresources ...string) (*unstructured.Unstructured, bool, error) {
+	logger := klog.FromContext(ctx)
+
+	applied, err := r.applyProviderObject(ctx, provider, obj.DeepCopy(), false, subresources...)
+	if errors.IsConflict(err) {
+		managers := conflictingManagers(err)
+		if len(managers) == 0 || !r.isOwnManager(managers...) {
+			logger.Info("Upstream fields are owned by other field managers", "managers", managers)
+			return nil, true, err
+		}
+
+		logger.V(1).Info("Taking over upstream fields from previous konnector field managers", "managers", managers)
+		applied, err = r.applyProviderObject(ctx, provider, obj.DeepCopy(), true, subresources...)
+	}
+	return applied, false, err
+}
+
+// getProviderObject returns the upstream object with the given upstream namespace
+// and name. Cluster-scoped objects are given and returned by their downstream name,
+// in their downstream representation.
+func (r *reconciler) getProviderObject(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+	if ns != "" || r.isolator == nil {
+		return r.getUpstreamObject(provider, ns, name)
+	}
+
+	upstreamNs, upstreamName := r.isolator.UpstreamKey(name, provider.Namespace)
+	obj, err := r.getUpstreamObject(provider, upstreamNs, upstreamName)
 	if err != nil {
-		logger.Error(err, "failed to get downstream spec")
-		return nil
+		return nil, err
+	}
+	obj = obj.DeepCopy()
+	if err := r.isolator.ToDownstream(obj, provider.Namespace); err != nil {
+		return nil, err
 	}
-	upstreamSpec, _, err := unstructured.NestedFieldNoCopy(upstream.Object, "spec")
+	return obj, nil
+}
+
+// applyProviderObject applies obj to the provider. Cluster-scoped objects are
+// given and returned in their downstream representation.
+func (r *reconciler) applyProviderObject(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, force bool, subresources ...string) (*unstructured.Unstructured, error) {
+	if obj.GetNamespace() != "" || r.isolator == nil {
+		return r.applyUpstreamObject(ctx, provider, obj, force, subresources...)
+	}
+
+	if err := r.isolator.ToUpstream(obj, provider.Namespace, provider.NamespaceUID); err != nil {
+		return nil, err
+	}
+	applied, err := r.applyUpstreamObject(ctx, provider, obj, force, subresources...)
 	if err != nil {
-		logger.Error(err, "failed to get upstream spec")
-		return nil
+		return nil, err
 	}
-	if reflect.DeepEqual(downstreamSpec, upstreamSpec) {
-		return nil // nothing to do
+	if err := r.isolator.ToDownstream(applied, provider.Namespace); err != nil {
+		return nil, err
 	}
+	return applied, nil
+}
 
-	upstream = upstream.DeepCopy()
-	if foundDownstreamSpec {
-		if err := unstructured.SetNestedField(upstream.Object, downstreamSpec, "spec"); err != nil {
-			bs, err := json.Marshal(downstreamSpec)
-			if err != nil {
-				logger.Error(err, "failed to marshal downstream spec", "spec", fmt.Sprintf("%s", downstreamSpec))
-				return nil // nothing we can do
-			}
-			logger.Error(err, "failed to set spec", "spec", string(bs))
-			return nil // nothing we can do
+func (r *reconciler) isOwnManager(managers ...string) bool {
+	for _, m := range managers {
+		if !isIgnoredManager(m, r.ownManagers) {
+			return false
 		}
-	} else {
-		unstructured.RemoveNestedField(upstream.Object, "spec")
 	}
+	return true
+}
+
+func (r *reconciler) setFieldConflict(ctx context.Context, obj *unstructured.Unstructured, upstreamKey string, conflict error) error {
+	return r.updateConditions(ctx, obj, []conditionsapi.Condition{
+		{
+			Type:     v1alpha1.DownstreamConditionFieldConflict,
+			Status:   metav1.ConditionTrue,
+			Severity: conditionsapi.ConditionSeverityWarning,
+			Reason:   "FieldManagerConflict",
+			Message:  conflict.Error(),
+		},
+		{
+			Type:               v1alpha1.DownstreamConditionSynced,
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: obj.GetGeneration(),
+			Severity:           conditionsapi.ConditionSeverityWarning,
+			Reason:             "FieldManagerConflict",
+			Message:            fmt.Sprintf("Failed to sync to upstream object %s: %v", upstreamKey, conflict),
+		},
+	}, v1alpha1.DownstreamConditionPaused)
+}
 
-	logger.Info("Updating upstream object")
-	upstream.SetManagedFields(nil) // server side apply does not want this
-	if _, err := r.updateProviderObject(ctx, provider, upstream); err != nil {
+// setSynced marks obj as synced to the upstream object. The sync time in the
+// message is only updated if the upstream object has been written, such that
+// the condition does not change on every reconciliation.
+func (r *reconciler) setSynced(ctx context.Context, obj *unstructured.Unstructured, upstreamKey string, written bool) error {
+	message := fmt.Sprintf("Synced to upstream object %s", upstreamKey)
+	if existing, err := r.getCondition(obj, v1alpha1.DownstreamConditionSynced); err != nil {
 		return err
+	} else if !written && existing != nil && existing.Status == metav1.ConditionTrue && strings.HasPrefix(existing.Message, message+" at ") {
+		message = existing.Message
+	} else {
+		message = fmt.Sprintf("%s at %s", message, time.Now().UTC().Format(time.RFC3339))
 	}
 
-	return nil
+	return r.updateConditions(ctx, obj, []conditionsapi.Condition{{
+		Type:               v1alpha1.DownstreamConditionSynced,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: obj.GetGeneration(),
+		Severity:           conditionsapi.ConditionSeverityNone,
+		Reason:             "Synced",
+		Message:            message,
+	}}, v1alpha1.DownstreamConditionFieldConflict, v1alpha1.DownstreamConditionPaused)
+}
+
+// setSyncFailed marks obj as not synced because of syncErr, which is returned.
+func (r *reconciler) setSyncFailed(ctx context.Context, obj *unstructured.Unstructured, upstreamKey, reason string, syncErr error) error {
+	message := fmt.Sprintf("Failed to sync: %v", syncErr)
+	if upstreamKey != "" {
+		message = fmt.Sprintf("Failed to sync to upstream object %s: %v", upstreamKey, syncErr)
+	}
+	if err := r.updateConditions(ctx, obj, []conditionsapi.Condition{{
+		Type:               v1alpha1.DownstreamConditionSynced,
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: obj.GetGeneration(),
+		Severity:           conditionsapi.ConditionSeverityError,
+		Reason:             reason,
+		Message:            message,
+	}}, v1alpha1.DownstreamConditionPaused); err != nil {
+		return utilerrors.NewAggregate([]error{syncErr, err})
+	}
+	return syncErr
+}
+
+func (r *reconciler) getCondition(obj *unstructured.Unstructured, t conditionsapi.ConditionType) (*conditionsapi.Condition, error) {
+	var conditions conditionsapi.Conditions
+	var err error
+	if r.statusSubresource {
+		conditions, err = downstream.GetConditions(obj)
+	} else {
+		conditions, err = downstream.GetAnnotationConditions(obj)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range conditions {
+		if conditions[i].Type == t {
+			return &conditions[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// updateConditions sets and removes conditions of the downstream object, in
+// its status if it has a status subresource, and in an annotation otherwise.
+func (r *reconciler) updateConditions(ctx context.Context, obj *unstructured.Unstructured, set []conditionsapi.Condition, remove ...conditionsapi.ConditionType) error {
+	setCondition, removeCondition := downstream.SetCondition, downstream.RemoveCondition
+	if !r.statusSubresource {
+		setCondition, removeCondition = downstream.SetAnnotationCondition, downstream.RemoveAnnotationCondition
+	}
+
+	obj = obj.DeepCopy()
+	changed := false
+	for _, c := range set {
+		ok, err := setCondition(obj, c)
+		if err != nil {
+			return err
+		}
+		changed = changed || ok
+	}
+	for _, t := range remove {
+		ok, err := removeCondition(obj, t)
+		if err != nil {
+			return err
+		}
+		changed = changed || ok
+	}
+	if !changed {
+		return nil
+	}
+
+	var err error
+	if r.statusSubresource {
+		_, err = r.updateConsumerObjectStatus(ctx, obj)
+	} else {
+		_, err = r.updateConsumerObject(ctx, obj)
+	}
+	return err
 }
 
 func (r *reconciler) ensureDownstreamFinalizer(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
@@ -0,0 +1,286 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package spec
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/downstream"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/related"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	conditionsapi "kmodules.xyz/client-go/api/v1"
+)
+
+func TestSyncedCondition(t *testing.T) {
+	tests := []struct {
+		name              string
+		statusSubresource bool
+	}{
+		{name: "status", statusSubresource: true},
+		{name: "annotation", statusSubresource: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updates int
+			var last *unstructured.Unstructured
+			update := func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				updates++
+				last = obj
+				return obj, nil
+			}
+			r := &reconciler{statusSubresource: tt.statusSubresource}
+			if tt.statusSubresource {
+				r.updateConsumerObjectStatus = update
+			} else {
+				r.updateConsumerObject = update
+			}
+
+			obj := &unstructured.Unstructured{}
+			obj.SetName("foo")
+			obj.SetGeneration(2)
+
+			// first sync sets the condition
+			require.NoError(t, r.setSynced(context.Background(), obj, "ns/foo", false))
+			require.Equal(t, 1, updates)
+			c := getSynced(t, r, last)
+			require.Equal(t, metav1.ConditionTrue, c.Status)
+			require.Equal(t, int64(2), c.ObservedGeneration)
+			require.Contains(t, c.Message, "Synced to upstream object ns/foo at ")
+
+			// nothing written, nothing changes
+			obj = last
+			require.NoError(t, r.setSynced(context.Background(), obj, "ns/foo", false))
+			require.Equal(t, 1, updates)
+
+			// failures carry the error
+			syncErr := errors.New("boom")
+			err := r.setSyncFailed(context.Background(), obj, "ns/foo", "ApplyFailed", syncErr)
+			require.ErrorIs(t, err, syncErr)
+			require.Equal(t, 2, updates)
+			c = getSynced(t, r, last)
+			require.Equal(t, metav1.ConditionFalse, c.Status)
+			require.Equal(t, "ApplyFailed", c.Reason)
+			require.Equal(t, "Failed to sync to upstream object ns/foo: boom", c.Message)
+		})
+	}
+}
+
+func getSynced(t *testing.T, r *reconciler, obj *unstructured.Unstructured) *conditionsapi.Condition {
+	if r.statusSubresource {
+		require.Empty(t, obj.GetAnnotations()[downstream.ConditionsAnnotation])
+	} else {
+		_, found := obj.Object["status"]
+		require.False(t, found)
+	}
+	c, err := r.getCondition(obj, v1alpha1.DownstreamConditionSynced)
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	return c
+}
+
+func TestPaused(t *testing.T) {
+	var last *unstructured.Unstructured
+	r := &reconciler{
+		statusSubresource: true,
+		getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
+			return &konnectormodels.ProviderInfo{ClusterID: "abc"}, nil
+		},
+		getServiceNamespace: func(provider *konnectormodels.ProviderInfo, name string) (*v1alpha1.APIServiceNamespace, error) {
+			t.Fatal("paused objects must not be synced")
+			return nil, nil
+		},
+		updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			last = obj
+			return obj, nil
+		},
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetNamespace("default")
+	obj.SetName("foo")
+	obj.SetAnnotations(map[string]string{v1alpha1.DownstreamPausedAnnotation: "true"})
+	require.NoError(t, r.reconcile(context.Background(), obj))
+
+	require.NotNil(t, last)
+	c, err := r.getCondition(last, v1alpha1.DownstreamConditionPaused)
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	require.Equal(t, metav1.ConditionTrue, c.Status)
+
+	// resuming removes the condition on the next sync
+	last.SetAnnotations(nil)
+	require.NoError(t, r.setSynced(context.Background(), last, "kube-bind-abc-default/foo", true))
+	c, err = r.getCondition(last, v1alpha1.DownstreamConditionPaused)
+	require.NoError(t, err)
+	require.Nil(t, c)
+}
+
+func TestDeletionPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		defaultPolicy v1alpha1.DeletionPolicy
+		annotation    string
+		paused        bool
+		want          string
+	}{
+		{name: "default", want: "delete"},
+		{name: "export delete", defaultPolicy: v1alpha1.DeletionPolicyDelete, want: "delete"},
+		{name: "export orphan", defaultPolicy: v1alpha1.DeletionPolicyOrphan, want: "orphan"},
+		{name: "export retain", defaultPolicy: v1alpha1.DeletionPolicyRetain, want: "retain"},
+		{name: "annotation overrides export", defaultPolicy: v1alpha1.DeletionPolicyDelete, annotation: "Orphan", want: "orphan"},
+		{name: "annotation retain", defaultPolicy: v1alpha1.DeletionPolicyOrphan, annotation: "Retain", want: "retain"},
+		{name: "invalid annotation", defaultPolicy: v1alpha1.DeletionPolicyOrphan, annotation: "Keep", want: "orphan"},
+		{name: "paused", defaultPolicy: v1alpha1.DeletionPolicyDelete, paused: true, want: "delete"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			finalizerRemoved := false
+			r := &reconciler{
+				deletionPolicy: tt.defaultPolicy,
+				getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
+					return &konnectormodels.ProviderInfo{ClusterID: "abc"}, nil
+				},
+				getUpstreamObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+					upstream := &unstructured.Unstructured{}
+					upstream.SetName(name)
+					return upstream, nil
+				},
+				deleteProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
+					got = "delete"
+					return nil
+				},
+				orphanProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
+					got = "orphan"
+					return nil
+				},
+				updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+					finalizerRemoved = len(obj.GetFinalizers()) == 0
+					return obj, nil
+				},
+			}
+
+			obj := &unstructured.Unstructured{}
+			obj.SetName("foo")
+			obj.SetFinalizers([]string{v1alpha1.DownstreamFinalizer})
+			obj.SetDeletionTimestamp(&metav1.Time{Time: time.Now()})
+			annotations := map[string]string{}
+			if tt.annotation != "" {
+				annotations[v1alpha1.DeletionPolicyAnnotation] = tt.annotation
+			}
+			if tt.paused {
+				annotations[v1alpha1.DownstreamPausedAnnotation] = "true"
+			}
+			obj.SetAnnotations(annotations)
+			require.NoError(t, r.reconcile(context.Background(), obj))
+
+			if tt.want == "retain" {
+				require.Empty(t, got)
+			} else {
+				require.Equal(t, tt.want, got)
+			}
+			require.True(t, finalizerRemoved)
+		})
+	}
+}
+
+func TestApplyRemovesFields(t *testing.T) {
+	policy, err := fieldsync.NewPolicy(&v1alpha1.APIServiceExportSpec{}, &v1alpha1.MetadataSync{
+		ToProvider: &v1alpha1.MetadataFilter{Labels: &v1alpha1.PrefixFilter{Include: []string{"*"}}},
+	})
+	require.NoError(t, err)
+
+	// the fake provider behaves like an API server with the konnector as the
+	// only field manager: the applied object replaces all but the status.
+	var upstream *unstructured.Unstructured
+	applies := 0
+	r := &reconciler{
+		policy:            policy,
+		statusSubresource: true,
+		getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
+			return &konnectormodels.ProviderInfo{ClusterID: "abc"}, nil
+		},
+		getUpstreamObject: func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error) {
+			if upstream == nil {
+				return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "mangodbs"}, name)
+			}
+			return upstream.DeepCopy(), nil
+		},
+		applyUpstreamObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, force bool, subresources ...string) (*unstructured.Unstructured, error) {
+			applies++
+			applied := obj.DeepCopy()
+			applied.SetResourceVersion("1")
+			if upstream != nil {
+				if status, found := upstream.Object["status"]; found {
+					applied.Object["status"] = status
+				}
+				applied.SetResourceVersion(upstream.GetResourceVersion())
+				if !equality.Semantic.DeepEqual(applied.Object, upstream.Object) {
+					applied.SetResourceVersion(upstream.GetResourceVersion() + "1")
+				}
+			}
+			upstream = applied
+			return applied.DeepCopy(), nil
+		},
+		newRelatedSyncer: func(provider *konnectormodels.ProviderInfo) related.Syncer {
+			return related.Syncer{}
+		},
+		updateConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			return obj, nil
+		},
+		updateConsumerObjectStatus: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+			return obj, nil
+		},
+	}
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "mangodb.com/v1alpha1",
+		"kind":       "MangoDB",
+		"spec":       map[string]interface{}{"size": int64(3), "tier": "gold"},
+	}}
+	obj.SetName("foo")
+	obj.SetLabels(map[string]string{"team": "a", "env": "prod"})
+	obj.SetFinalizers([]string{v1alpha1.DownstreamFinalizer})
+	require.NoError(t, r.reconcile(context.Background(), obj))
+	require.Equal(t, map[string]string{"team": "a", "env": "prod"}, upstream.GetLabels())
+	require.Equal(t, map[string]interface{}{"size": int64(3), "tier": "gold"}, upstream.Object["spec"])
+
+	// the consumer removes a label and a spec field
+	obj.SetLabels(map[string]string{"team": "a"})
+	unstructured.RemoveNestedField(obj.Object, "spec", "tier")
+	require.NoError(t, r.reconcile(context.Background(), obj))
+	require.Equal(t, 2, applies)
+	require.Equal(t, map[string]string{"team": "a"}, upstream.GetLabels())
+	require.Equal(t, map[string]interface{}{"size": int64(3)}, upstream.Object["spec"])
+	written := upstream.GetResourceVersion()
+
+	// nothing changed, nothing is written
+	require.NoError(t, r.reconcile(context.Background(), obj))
+	require.Equal(t, written, upstream.GetResourceVersion())
+}
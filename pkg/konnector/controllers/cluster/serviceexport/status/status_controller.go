@@ -25,14 +25,19 @@ import (
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
 	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	konnectordownstream "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/downstream"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/related"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
-	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	dynamicclient "k8s.io/client-go/dynamic"
@@ -42,24 +47,33 @@ import (
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
-	kmc "kmodules.xyz/client-go/client"
-	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	controllerName               = "kube-bind-konnector-cluster-status"
 	errorContextDeadlineExceeded = "context deadline exceeded"
-	errorAlreadyExists           = "already exists"
 )
 
 // NewController returns a new controller reconciling status of upstream to downstream.
+// The isolator is nil for namespaced resources. The deletion policy applies to
+// upstream objects without deletion policy annotation whose downstream object is gone.
+// With adopt, upstream objects labeled for adoption are created downstream. The
+// provider informers of the synced objects are by provider cluster ID. The queue
+// is named after the controller and the queue name.
 func NewController(
 	gvr schema.GroupVersionResource,
+	isolator clusterscoped.Isolator,
+	policy *fieldsync.Policy,
+	deletionPolicy v1alpha1.DeletionPolicy,
+	adopt bool,
 	consumerConfig *rest.Config,
 	consumerDynamicInformer informers.GenericInformer,
+	providerInformers map[string]multinsinformer.GetterInformer,
+	queueName string,
+	rateLimiter workqueue.RateLimiter,
 	providerInfos []*konnectormodels.ProviderInfo,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter, controllerName+"-"+queueName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -71,11 +85,6 @@ func NewController(
 		provider.Config = rest.AddUserAgent(provider.Config, controllerName)
 	}
 
-	consumerKubeClient, err := kmc.NewUncachedClient(consumerConfig)
-	if err != nil {
-		return nil, err
-	}
-
 	consumerClient, err := dynamicclient.NewForConfig(consumerConfig)
 	if err != nil {
 		return nil, err
@@ -93,17 +102,15 @@ func NewController(
 		consumerDynamicLister:  dynamicConsumerLister,
 		consumerDynamicIndexer: consumerDynamicInformer.Informer().GetIndexer(),
 
-		providerInfos: providerInfos,
+		providerInfos:     providerInfos,
+		providerInformers: providerInformers,
 
 		reconciler: reconciler{
-			getProviderInfo: func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error) {
-				anno := obj.GetAnnotations()
-				if clusterID := anno[konnectormodels.AnnotationProviderClusterID]; clusterID == "" {
-					return nil, fmt.Errorf("no cluster id found for object %s", obj.GetName())
-				} else {
-					return konnectormodels.GetProviderInfoWithClusterID(providerInfos, clusterID)
-				}
-			},
+			isolator:       isolator,
+			policy:         policy,
+			deletionPolicy: deletionPolicy,
+			adopt:          adopt,
+
 			getServiceNamespace: func(provider *konnectormodels.ProviderInfo, upstreamNamespace string) (*v1alpha1.APIServiceNamespace, error) {
 				sns, err := provider.DynamicServiceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, upstreamNamespace)
 				if err != nil {
@@ -118,100 +125,78 @@ func NewController(
 				if ns != "" {
 					return dynamicConsumerLister.Namespace(ns).Get(name)
 				}
-				got, err := dynamicConsumerLister.Get(clusterscoped.Behead(name, provider.Namespace))
+				got, err := dynamicConsumerLister.Get(name)
 				if err != nil {
 					return nil, err
 				}
 				obj := got.DeepCopy()
-				err = clusterscoped.TranslateFromDownstream(obj, provider.Namespace, provider.NamespaceUID)
+				err = isolator.ToUpstream(obj, provider.Namespace, provider.NamespaceUID)
 				if err != nil {
 					return nil, err
 				}
 				return obj, nil
 			},
-			updateConsumerObjectStatus: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
-				ns := obj.GetNamespace()
-				if ns == "" {
-					if err := clusterscoped.TranslateFromUpstream(obj); err != nil {
+			createConsumerObject: func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+				return consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).Create(ctx, obj, metav1.CreateOptions{})
+			},
+			updateConsumerObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, clusterScoped bool) (*unstructured.Unstructured, error) {
+				if clusterScoped {
+					if err := isolator.ToDownstream(obj, provider.Namespace); err != nil {
 						return nil, err
 					}
 				}
-				updated, err := consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
+				updated, err := consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).Update(ctx, obj, metav1.UpdateOptions{})
 				if err != nil {
 					return nil, err
 				}
-				if ns == "" {
-					err = clusterscoped.TranslateFromDownstream(updated, provider.Namespace, provider.NamespaceUID)
-					if err != nil {
+				if clusterScoped {
+					if err := isolator.ToUpstream(updated, provider.Namespace, provider.NamespaceUID); err != nil {
 						return nil, err
 					}
-					return updated, nil
 				}
 				return updated, nil
 			},
-			ensureStatusSecret: func(ctx context.Context, provider *konnectormodels.ProviderInfo, upstream, downstream *unstructured.Unstructured, status interface{}, providerNS string) (string, error) {
-				upSecretName, found, err := unstructured.NestedString(upstream.Object, "status", "secretRef", "name")
-				if err != nil {
-					return "", err
-				}
-
-				if !found {
-					klog.Warningf("no secretRef found in object %s status", upstream.GetName())
-					return "", nil
-				}
-				providerSecret, err := provider.KubeClient.CoreV1().Secrets(upstream.GetNamespace()).Get(ctx, upSecretName, metav1.GetOptions{})
-				if err != nil {
-					return "", err
-				}
-				consumerSecret := &corev1.Secret{
-					ObjectMeta: metav1.ObjectMeta{
-						Name:      providerSecret.Name + "-" + provider.ClusterID,
-						Namespace: downstream.GetNamespace(),
-						OwnerReferences: []metav1.OwnerReference{
-							{
-								APIVersion: downstream.GetAPIVersion(),
-								Kind:       downstream.GetKind(),
-								UID:        downstream.GetUID(),
-								Name:       downstream.GetName(),
-							},
-						},
-					},
-					Data: providerSecret.Data,
+			updateConsumerObjectStatus: func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, clusterScoped bool) (*unstructured.Unstructured, error) {
+				if clusterScoped {
+					if err := isolator.ToDownstream(obj, provider.Namespace); err != nil {
+						return nil, err
+					}
 				}
-
-				// delete backdated secret from consumer
-				oldSecretName, found, err := unstructured.NestedString(downstream.Object, "status", "secretRef", "name")
+				updated, err := consumerClient.Resource(gvr).Namespace(obj.GetNamespace()).UpdateStatus(ctx, obj, metav1.UpdateOptions{})
 				if err != nil {
-					return "", err
+					return nil, err
 				}
-				if found {
-					if err = consumerClient.Resource(schema.GroupVersionResource{
-						Group:    "",
-						Version:  "v1",
-						Resource: "secrets",
-					}).Namespace(downstream.GetNamespace()).Delete(ctx, oldSecretName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
-						return "", err
-					} else if errors.IsNotFound(err) {
-						klog.Infof("%s/%s secret not found in downstream", downstream.GetNamespace(), oldSecretName)
-					} else {
-						klog.Infof("secret %s/%s deleted", downstream.GetNamespace(), oldSecretName)
+				if clusterScoped {
+					err = isolator.ToUpstream(updated, provider.Namespace, provider.NamespaceUID)
+					if err != nil {
+						return nil, err
 					}
+					return updated, nil
 				}
-
-				if _, err = kmc.CreateOrPatch(ctx, consumerKubeClient, consumerSecret, func(_ client.Object, _ bool) client.Object {
-					return consumerSecret
-				}); err != nil && !strings.Contains(err.Error(), errorAlreadyExists) {
-					klog.Errorf(err.Error())
-					return "", err
+				return updated, nil
+			},
+			newRelatedSyncer: func(provider *konnectormodels.ProviderInfo) related.Syncer {
+				return related.Syncer{
+					Source: provider.Client,
+					Target: consumerClient,
+					Suffix: "-" + provider.ClusterID,
 				}
-
-				klog.Infof("secret %s/%s successfully created", consumerSecret.Namespace, consumerSecret.Name)
-
-				return consumerSecret.Name, nil
 			},
 			deleteProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
 				return provider.Client.Resource(gvr).Namespace(ns).Delete(ctx, name, metav1.DeleteOptions{})
 			},
+			orphanProviderObject: func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error {
+				patch, err := konnectordownstream.OrphanPatch(time.Now())
+				if err != nil {
+					return err
+				}
+				if _, err := provider.Client.Resource(gvr).Namespace(ns).Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+					return err
+				}
+				klog.FromContext(ctx).Info("orphaned upstream object", "upstreamNamespace", ns, "upstreamName", name)
+				metrics.RecordOrphaned(gvr.GroupResource().String())
+				return nil
+			},
 		},
 	}
 
@@ -231,7 +216,7 @@ func NewController(
 	}
 
 	for _, provider := range c.providerInfos {
-		provider.ProviderDynamicInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		c.providerInformers[provider.ClusterID].AddEventHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc: func(obj interface{}) {
 				c.enqueueProvider(logger, provider, obj)
 			},
@@ -258,13 +243,24 @@ type controller struct {
 	consumerDynamicLister  dynamiclister.Lister
 	consumerDynamicIndexer cache.Indexer
 
-	providerInfos []*konnectormodels.ProviderInfo
+	providerInfos     []*konnectormodels.ProviderInfo
+	providerInformers map[string]multinsinformer.GetterInformer
 
 	reconciler
 }
 
+// isAdoptable returns true if obj is an upstream object to be created downstream.
+// Its provider is the one of the informer it comes from.
+func (c *controller) isAdoptable(obj interface{}) bool {
+	if !c.adopt {
+		return false
+	}
+	unstr, ok := obj.(*unstructured.Unstructured)
+	return ok && konnectordownstream.IsAdoptable(unstr)
+}
+
 func (c *controller) enqueueProvider(logger klog.Logger, provider *konnectormodels.ProviderInfo, obj interface{}) {
-	if !konnectormodels.IsMatchProvider(provider, obj) {
+	if !konnectormodels.IsMatchProvider(provider, obj) && !c.isAdoptable(obj) {
 		return
 	}
 	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
@@ -272,35 +268,35 @@ func (c *controller) enqueueProvider(logger klog.Logger, provider *konnectormode
 		runtime.HandleError(err)
 		return
 	}
-	ns, _, err := cache.SplitMetaNamespaceKey(key)
+	ns, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
 		runtime.HandleError(err)
 		return
 	}
-	if ns != "" {
-		sns, err := provider.DynamicServiceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, ns)
-		if err != nil {
-			runtime.HandleError(err)
+	if c.isolator != nil {
+		if _, ok := c.isolator.DownstreamName(ns, name, provider.Namespace); !ok {
+			logger.V(3).Info("skipping because consumer mismatch", "key", key)
 			return
 		}
-		for _, obj := range sns {
-			sns := obj.(*v1alpha1.APIServiceNamespace)
-			if sns.Namespace == provider.Namespace {
-				logger.V(2).Info("queueing Unstructured", "key", key)
-				c.queue.Add(provider.ClusterID + "/" + key)
-				return
-			}
-		}
-		logger.V(3).Info("skipping because consumer mismatch", "key", key)
+		logger.V(2).Info("queueing Unstructured", "key", key)
+		c.queue.Add(provider.ClusterID + "/" + key)
 		return
 	}
 
-	if clusterscoped.Behead(key, provider.Namespace) == key {
-		logger.V(3).Info("skipping because consumer mismatch", "key", key)
+	sns, err := provider.DynamicServiceNamespaceInformer.Informer().GetIndexer().ByIndex(indexers.ServiceNamespaceByNamespace, ns)
+	if err != nil {
+		runtime.HandleError(err)
 		return
 	}
-	logger.V(2).Info("queueing Unstructured", "key", key)
-	c.queue.Add(provider.ClusterID + "/" + key)
+	for _, obj := range sns {
+		sns := obj.(*v1alpha1.APIServiceNamespace)
+		if sns.Namespace == provider.Namespace {
+			logger.V(2).Info("queueing Unstructured", "key", key)
+			c.queue.Add(provider.ClusterID + "/" + key)
+			return
+		}
+	}
+	logger.V(3).Info("skipping because consumer mismatch", "key", key)
 }
 
 func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
@@ -338,7 +334,11 @@ func (c *controller) enqueueConsumer(logger klog.Logger, obj interface{}) {
 		return
 	}
 
-	upstreamKey := clusterscoped.Prepend(downstreamKey, provider.Namespace)
+	upstreamNs, upstreamName := c.isolator.UpstreamKey(name, provider.Namespace)
+	upstreamKey := upstreamName
+	if upstreamNs != "" {
+		upstreamKey = upstreamNs + "/" + upstreamName
+	}
 	logger.V(2).Info("queueing Unstructured", "key", upstreamKey)
 	c.queue.Add(provider.ClusterID + "/" + upstreamKey)
 }
@@ -366,7 +366,7 @@ func (c *controller) enqueueServiceNamespace(logger klog.Logger, provider *konne
 	if sn.Status.Namespace == "" {
 		return // not ready
 	}
-	objs, err := provider.ProviderDynamicInformer.List(sn.Status.Namespace)
+	objs, err := c.providerInformers[provider.ClusterID].List(sn.Status.Namespace)
 	if err != nil {
 		runtime.HandleError(err)
 		return
@@ -435,7 +435,9 @@ func (c *controller) processNextWorkItem(ctx context.Context) bool {
 	// other workers.
 	defer c.queue.Done(key)
 
-	if err := c.process(ctx, key); err != nil {
+	err := c.process(ctx, key)
+	metrics.RecordSync(controllerName, c.gvr.GroupResource().String(), err)
+	if err != nil {
 		runtime.HandleError(fmt.Errorf("%q controller failed to sync %q, err: %w", controllerName, key, err))
 		klog.Errorf(err.Error())
 		c.queue.AddRateLimited(key)
@@ -479,7 +481,7 @@ func (c *controller) process(ctx context.Context, key string) error {
 
 	var obj k8sruntime.Object
 	err = wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, 10*time.Second, true, func(ctx context.Context) (done bool, err error) {
-		obj, err = provider.ProviderDynamicInformer.Get(ns, name)
+		obj, err = c.providerInformers[provider.ClusterID].Get(ns, name)
 		if err != nil && !errors.IsNotFound(err) {
 			return false, err
 		} else if errors.IsNotFound(err) {
@@ -494,13 +496,26 @@ func (c *controller) process(ctx context.Context, key string) error {
 	} else if err != nil && (errors.IsNotFound(err) || strings.Contains(err.Error(), errorContextDeadlineExceeded)) {
 		logger.V(2).Info("Upstream object disappeared")
 
-		downstream, err := c.consumerDynamicLister.Namespace(ns).Get(name)
+		var downstream *unstructured.Unstructured
+		if c.isolator != nil {
+			downstreamName, ok := c.isolator.DownstreamName(ns, name, provider.Namespace)
+			if !ok {
+				return nil
+			}
+			downstream, err = c.consumerDynamicLister.Get(downstreamName)
+		} else {
+			downstream, err = c.consumerDynamicLister.Namespace(ns).Get(name)
+		}
 		if err != nil && !errors.IsNotFound(err) {
 			return err
 		} else if err == nil {
 			if downstream.GetAnnotations()[konnectormodels.AnnotationProviderClusterID] != provider.ClusterID {
 				return nil
 			}
+			if konnectordownstream.IsPaused(downstream) {
+				logger.V(2).Info("sync is paused")
+				return nil
+			}
 			if _, err := c.removeDownstreamFinalizer(ctx, downstream); err != nil {
 				return err
 			}
@@ -509,7 +524,7 @@ func (c *controller) process(ctx context.Context, key string) error {
 		return nil
 	}
 
-	return c.reconcile(ctx, obj.(*unstructured.Unstructured))
+	return c.reconcile(ctx, provider, obj.(*unstructured.Unstructured))
 }
 
 func (c *controller) removeDownstreamFinalizer(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
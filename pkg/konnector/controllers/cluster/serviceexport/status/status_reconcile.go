@@ -21,6 +21,10 @@ import (
 	"reflect"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	clusterscoped "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/cluster-scoped"
+	konnectordownstream "go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/downstream"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/fieldsync"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/related"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -30,30 +34,44 @@ import (
 )
 
 type reconciler struct {
-	getProviderInfo func(obj *unstructured.Unstructured) (*konnectormodels.ProviderInfo, error)
+	isolator clusterscoped.Isolator
+	policy   *fieldsync.Policy
+	// deletionPolicy is the deletion policy of upstream objects without deletion
+	// policy annotation.
+	deletionPolicy kubebindv1alpha1.DeletionPolicy
+	// adopt is true if upstream objects labeled for adoption are created downstream.
+	adopt bool
 
 	getServiceNamespace func(provider *konnectormodels.ProviderInfo, upstreamNamespace string) (*kubebindv1alpha1.APIServiceNamespace, error)
 
 	getConsumerObject          func(provider *konnectormodels.ProviderInfo, ns, name string) (*unstructured.Unstructured, error)
-	updateConsumerObjectStatus func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	createConsumerObject       func(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, error)
+	updateConsumerObject       func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, clusterScoped bool) (*unstructured.Unstructured, error)
+	updateConsumerObjectStatus func(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured, clusterScoped bool) (*unstructured.Unstructured, error)
 
-	ensureStatusSecret func(ctx context.Context, provider *konnectormodels.ProviderInfo, upstream, downstream *unstructured.Unstructured, status interface{}, providerNS string) (string, error)
+	newRelatedSyncer func(provider *konnectormodels.ProviderInfo) related.Syncer
 
 	deleteProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error
+	orphanProviderObject func(ctx context.Context, provider *konnectormodels.ProviderInfo, ns, name string) error
 }
 
-// reconcile syncs upstream status to consumer objects.
-func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructured) error {
+// reconcile syncs upstream status and the other fields, labels and annotations
+// synced to the consumer to consumer objects of the given provider. Upstream
+// objects labeled for adoption are created downstream if missing.
+func (r *reconciler) reconcile(ctx context.Context, provider *konnectormodels.ProviderInfo, obj *unstructured.Unstructured) error {
 	logger := klog.FromContext(ctx)
 
-	provider, err := r.getProviderInfo(obj)
-	if err != nil {
-		klog.ErrorS(err, "failed to get provider information")
-		return err
-	}
+	ns, name := obj.GetNamespace(), obj.GetName()
+	if r.isolator != nil {
+		downstreamName, ok := r.isolator.DownstreamName(ns, name, provider.Namespace)
+		if !ok || !clusterscoped.IsOwnedBy(obj, provider.Namespace) {
+			logger.V(2).Info("skipping upstream object of another consumer")
+			return nil
+		}
 
-	ns := obj.GetNamespace()
-	if ns != "" {
+		// continue with downstream name
+		ns, name = "", downstreamName
+	} else {
 		sn, err := r.getServiceNamespace(provider, ns)
 		if err != nil && !errors.IsNotFound(err) {
 			return err
@@ -73,53 +91,88 @@ func (r *reconciler) reconcile(ctx context.Context, obj *unstructured.Unstructur
 		ns = sn.Name
 	}
 
-	downstream, err := r.getConsumerObject(provider, ns, obj.GetName())
+	downstream, err := r.getConsumerObject(provider, ns, name)
 	if err != nil && !errors.IsNotFound(err) {
-		logger.Info("failed to get downstream object", "error", err, "downstreamNamespace", ns, "downstreamName", obj.GetName())
+		logger.Info("failed to get downstream object", "error", err, "downstreamNamespace", ns, "downstreamName", name)
 		return err
+	} else if errors.IsNotFound(err) && r.adopt && konnectordownstream.IsAdoptable(obj) {
+		logger.Info("Adopting upstream object", "downstreamNamespace", ns, "downstreamName", name)
+		adopted := konnectordownstream.NewAdopted(obj, ns, name, provider.ClusterID)
+		r.policy.MergeMetadata(adopted, obj, kubebindv1alpha1.SyncDirectionToConsumer)
+		if _, err := r.createConsumerObject(ctx, adopted); err != nil && !errors.IsAlreadyExists(err) {
+			return err
+		}
+		return nil // the spec controller takes over from here, and the status follows with the next upstream event
 	} else if errors.IsNotFound(err) {
-		// downstream is gone. Delete upstream too. Note that we cannot rely on the spec controller because
-		// due to konnector restart it might have missed the deletion event.
-		logger.Info("Deleting upstream object because downstream is gone", "downstreamNamespace", ns, "downstreamName", obj.GetName())
+		// downstream is gone. Delete upstream too, unless orphaned or retained. Note that we cannot rely on
+		// the spec controller because due to konnector restart it might have missed the deletion event.
+		switch policy := konnectordownstream.DeletionPolicy(obj, r.deletionPolicy); policy {
+		case kubebindv1alpha1.DeletionPolicyOrphan:
+			logger.Info("Orphaning upstream object because downstream is gone", "downstreamNamespace", ns, "downstreamName", name)
+			return r.orphanProviderObject(ctx, provider, obj.GetNamespace(), obj.GetName())
+		case kubebindv1alpha1.DeletionPolicyRetain:
+			logger.V(2).Info("Retaining upstream object although downstream is gone", "downstreamNamespace", ns, "downstreamName", name)
+			return nil
+		}
+		logger.Info("Deleting upstream object because downstream is gone", "downstreamNamespace", ns, "downstreamName", name)
 		if err := r.deleteProviderObject(ctx, provider, obj.GetNamespace(), obj.GetName()); err != nil {
 			return err
 		}
 		return nil
 	}
 
+	if konnectordownstream.IsPaused(downstream) {
+		logger.V(2).Info("sync is paused", "downstreamNamespace", ns, "downstreamName", name)
+		return nil
+	}
+
 	orig := downstream
 	downstream = downstream.DeepCopy()
-	newStatus, found, err := unstructured.NestedFieldNoCopy(obj.Object, "status")
-	if err != nil {
+	r.policy.Merge(downstream.Object, obj.Object, kubebindv1alpha1.SyncDirectionToConsumer)
+	r.policy.MergeMetadata(downstream, obj, kubebindv1alpha1.SyncDirectionToConsumer)
+	if downstream.GetNamespace() != "" {
+		// copy the related objects of namespaced service objects
+		syncer := r.newRelatedSyncer(provider)
+		for _, ref := range r.policy.References(kubebindv1alpha1.SyncDirectionToConsumer) {
+			stale := ref.Names(orig.Object)
+			names := syncer.Rewrite(ref, downstream.Object)
+			if err := syncer.Sync(ctx, ref, names, obj.GetNamespace(), downstream, stale); err != nil {
+				return err
+			}
+		}
+	}
+	// keep the conditions the konnector sets on the downstream object itself
+	if err := konnectordownstream.PreserveConditions(orig, downstream); err != nil {
 		runtime.HandleError(err)
 		return nil // nothing we can do here
 	}
-	if found {
-		newSecretRefName, err := r.ensureStatusSecret(ctx, provider, obj.DeepCopy(), downstream, newStatus, ns)
+	if !reflect.DeepEqual(withoutStatus(orig), withoutStatus(downstream)) {
+		logger.Info("Updating downstream object")
+		updated, err := r.updateConsumerObject(ctx, provider, downstream.DeepCopy(), r.isolator != nil)
 		if err != nil {
-			klog.Errorf(err.Error())
 			return err
 		}
-		if err := unstructured.SetNestedField(downstream.Object, newStatus, "status"); err != nil {
-			runtime.HandleError(err)
-			klog.Errorf(err.Error())
-			return nil // nothing we can do here
-		}
-		if newSecretRefName != "" {
-			if err = unstructured.SetNestedField(downstream.Object, newSecretRefName, "status", "secretRef", "name"); err != nil {
-				klog.Errorf(err.Error())
-				return err
-			}
-		}
-	} else {
-		unstructured.RemoveNestedField(downstream.Object, "status")
+		orig = updated
+		downstream.SetResourceVersion(updated.GetResourceVersion())
 	}
-	if !reflect.DeepEqual(orig, downstream) {
+	if !reflect.DeepEqual(orig.Object["status"], downstream.Object["status"]) {
 		logger.Info("Updating downstream object status")
-		if _, err := r.updateConsumerObjectStatus(ctx, provider, downstream); err != nil {
+		if _, err := r.updateConsumerObjectStatus(ctx, provider, downstream, r.isolator != nil); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// withoutStatus returns the fields of obj other than status, i.e. those not
+// written through the status subresource.
+func withoutStatus(obj *unstructured.Unstructured) map[string]interface{} {
+	fields := make(map[string]interface{}, len(obj.Object))
+	for k, v := range obj.Object {
+		if k != "status" {
+			fields[k] = v
+		}
+	}
+	return fields
+}
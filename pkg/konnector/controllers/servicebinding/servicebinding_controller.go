@@ -27,9 +27,11 @@ import (
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/pkg/committer"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -45,13 +47,16 @@ const (
 	controllerName = "kube-bind-konnector-servicebinding"
 )
 
-// NewController returns a new controller for ServiceBindings.
+// NewController returns a new controller for ServiceBindings. Only the
+// ServiceBindings owned by the sharder are reconciled.
 func NewController(
 	consumerConfig *rest.Config,
 	serviceBindingInformer bindinformers.APIServiceBindingInformer,
 	consumerSecretInformer coreinformers.SecretInformer,
+	sharder *sharding.Sharder,
+	rateLimiter workqueue.RateLimiter,
 ) (*controller, error) {
-	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
+	queue := workqueue.NewNamedRateLimitingQueue(rateLimiter, controllerName)
 
 	logger := klog.Background().WithValues("controller", controllerName)
 
@@ -71,6 +76,8 @@ func NewController(
 
 		consumerSecretLister: consumerSecretInformer.Lister(),
 
+		sharder: sharder,
+
 		reconciler: reconciler{
 			getConsumerSecret: func(ns, name string) (*corev1.Secret, error) {
 				return consumerSecretInformer.Lister().Secrets(ns).Get(name)
@@ -138,6 +145,8 @@ type controller struct {
 
 	consumerSecretLister corelisters.SecretLister
 
+	sharder *sharding.Sharder
+
 	reconciler
 
 	commit CommitFunc
@@ -191,6 +200,17 @@ func (c *controller) Start(ctx context.Context, numThreads int) {
 	logger.Info("Starting controller")
 	defer logger.Info("Shutting down controller")
 
+	c.sharder.Subscribe(ctx, func() {
+		bindings, err := c.serviceBindingLister.List(labels.Everything())
+		if err != nil {
+			runtime.HandleError(err)
+			return
+		}
+		for _, binding := range bindings {
+			c.enqueueServiceBinding(logger, binding)
+		}
+	})
+
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, c.startWorker, time.Second)
 	}
@@ -246,6 +266,9 @@ func (c *controller) process(ctx context.Context, key string) error {
 		logger.Error(err, "APIServiceBinding disappeared")
 		return nil
 	}
+	if !c.sharder.Owns(sharding.Key(obj)) {
+		return nil // another replica handles it
+	}
 
 	old := obj
 	obj = obj.DeepCopy()
@@ -0,0 +1,74 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synctuning
+
+import (
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Merge returns the tuning of the APIServiceBinding with unset fields taken
+// from the konnector defaults.
+func Merge(defaults, overrides *v1alpha1.SyncTuning) v1alpha1.SyncTuning {
+	var merged v1alpha1.SyncTuning
+	for _, t := range []*v1alpha1.SyncTuning{defaults, overrides} {
+		if t == nil {
+			continue
+		}
+		if t.Workers > 0 {
+			merged.Workers = t.Workers
+		}
+		if t.ProviderQPS > 0 {
+			merged.ProviderQPS = t.ProviderQPS
+		}
+		if t.ProviderBurst > 0 {
+			merged.ProviderBurst = t.ProviderBurst
+		}
+		if t.RetryBaseDelay != nil {
+			merged.RetryBaseDelay = t.RetryBaseDelay.DeepCopy()
+		}
+		if t.RetryMaxDelay != nil {
+			merged.RetryMaxDelay = t.RetryMaxDelay.DeepCopy()
+		}
+	}
+	if merged.Workers == 0 {
+		merged.Workers = 1
+	}
+	return merged
+}
+
+// NewRateLimiter returns a queue rate limiter like
+// workqueue.DefaultControllerRateLimiter with the retry delays of the tuning.
+func NewRateLimiter(tuning v1alpha1.SyncTuning) workqueue.RateLimiter {
+	baseDelay, maxDelay := 5*time.Millisecond, 1000*time.Second
+	if tuning.RetryBaseDelay != nil {
+		baseDelay = tuning.RetryBaseDelay.Duration
+	}
+	if tuning.RetryMaxDelay != nil {
+		maxDelay = tuning.RetryMaxDelay.Duration
+	}
+
+	return workqueue.NewMaxOfRateLimiter(
+		workqueue.NewItemExponentialFailureRateLimiter(baseDelay, maxDelay),
+		// overall rate limiting, not per item
+		&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(10), 100)},
+	)
+}
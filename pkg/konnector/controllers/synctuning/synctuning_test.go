@@ -0,0 +1,74 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package synctuning
+
+import (
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestMerge(t *testing.T) {
+	defaults := &kubebindv1alpha1.SyncTuning{
+		Workers:        1,
+		ProviderQPS:    5,
+		RetryBaseDelay: &metav1.Duration{Duration: 5 * time.Millisecond},
+		RetryMaxDelay:  &metav1.Duration{Duration: 1000 * time.Second},
+	}
+
+	tests := []struct {
+		name      string
+		defaults  *kubebindv1alpha1.SyncTuning
+		overrides *kubebindv1alpha1.SyncTuning
+		want      kubebindv1alpha1.SyncTuning
+	}{
+		{
+			name: "nothing set",
+			want: kubebindv1alpha1.SyncTuning{Workers: 1},
+		},
+		{
+			name:     "defaults only",
+			defaults: defaults,
+			want:     *defaults,
+		},
+		{
+			name:     "overrides",
+			defaults: defaults,
+			overrides: &kubebindv1alpha1.SyncTuning{
+				Workers:       4,
+				ProviderBurst: 50,
+				RetryMaxDelay: &metav1.Duration{Duration: time.Minute},
+			},
+			want: kubebindv1alpha1.SyncTuning{
+				Workers:        4,
+				ProviderQPS:    5,
+				ProviderBurst:  50,
+				RetryBaseDelay: &metav1.Duration{Duration: 5 * time.Millisecond},
+				RetryMaxDelay:  &metav1.Duration{Duration: time.Minute},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, Merge(tt.defaults, tt.overrides))
+		})
+	}
+}
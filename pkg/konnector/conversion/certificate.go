@@ -0,0 +1,147 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	cryptorand "crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetesclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/cert"
+	"k8s.io/client-go/util/keyutil"
+	"k8s.io/klog/v2"
+)
+
+// certificateLifetime matches the lifetime of the CA created by
+// cert.NewSelfSignedCACert.
+const certificateLifetime = 10 * 365 * 24 * time.Hour
+
+// EnsureServingCertificate returns the serving certificate of the conversion
+// webhook reachable through the given service, and the CA bundle verifying it.
+// They are kept in a secret, such that all replicas of the konnector serve the
+// same certificate, and are created on first use.
+func EnsureServingCertificate(ctx context.Context, client kubernetesclient.Interface, ns, secretName, serviceName string) (*tls.Certificate, []byte, error) {
+	logger := klog.FromContext(ctx)
+
+	secret, err := client.CoreV1().Secrets(ns).Get(ctx, secretName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		logger.Info("Creating conversion webhook serving certificate", "namespace", ns, "name", secretName)
+		secret, err = newServingCertificateSecret(ns, secretName, serviceName)
+		if err != nil {
+			return nil, nil, err
+		}
+		secret, err = client.CoreV1().Secrets(ns).Create(ctx, secret, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			// another replica was faster
+			secret, err = client.CoreV1().Secrets(ns).Get(ctx, secretName, metav1.GetOptions{})
+		}
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get conversion webhook serving certificate secret %s/%s: %w", ns, secretName, err)
+	}
+
+	servingCert, err := tlsKeyPair(secret)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid conversion webhook serving certificate secret %s/%s: %w", ns, secretName, err)
+	}
+	caBundle := secret.Data[corev1.ServiceAccountRootCAKey]
+	if len(caBundle) == 0 {
+		return nil, nil, fmt.Errorf("conversion webhook serving certificate secret %s/%s has no %s", ns, secretName, corev1.ServiceAccountRootCAKey)
+	}
+
+	return &servingCert, caBundle, nil
+}
+
+func tlsKeyPair(secret *corev1.Secret) (tls.Certificate, error) {
+	return tls.X509KeyPair(secret.Data[corev1.TLSCertKey], secret.Data[corev1.TLSPrivateKeyKey])
+}
+
+// newServingCertificateSecret creates a self-signed CA and a serving
+// certificate signed by it for the DNS names of the given service.
+func newServingCertificateSecret(ns, secretName, serviceName string) (*corev1.Secret, error) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	caCert, err := cert.NewSelfSignedCACert(cert.Config{CommonName: serviceName + "-ca"}, caKey)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), cryptorand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	host := fmt.Sprintf("%s.%s.svc", serviceName, ns)
+	servingCert, err := newSignedCert(host, []string{host, host + ".cluster.local"}, key, caCert, caKey)
+	if err != nil {
+		return nil, err
+	}
+	keyPEM, err := keyutil.MarshalPrivateKeyToPEM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      secretName,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:              pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: servingCert.Raw}),
+			corev1.TLSPrivateKeyKey:        keyPEM,
+			corev1.ServiceAccountRootCAKey: pem.EncodeToMemory(&pem.Block{Type: cert.CertificateBlockType, Bytes: caCert.Raw}),
+		},
+	}, nil
+}
+
+func newSignedCert(commonName string, dnsNames []string, key crypto.Signer, caCert *x509.Certificate, caKey crypto.Signer) (*x509.Certificate, error) {
+	serial, err := cryptorand.Int(cryptorand.Reader, new(big.Int).SetInt64(math.MaxInt64-1))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	tmpl := x509.Certificate{
+		SerialNumber: new(big.Int).Add(serial, big.NewInt(1)),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    now.Add(-time.Hour).UTC(), // tolerate clock skew
+		NotAfter:     now.Add(certificateLifetime).UTC(),
+		KeyUsage:     x509.KeyUsageKeyEncipherment | x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(cryptorand.Reader, &tmpl, caCert, key.Public(), caKey)
+	if err != nil {
+		return nil, err
+	}
+	return x509.ParseCertificate(der)
+}
@@ -0,0 +1,363 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	kubernetesclient "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// maxReviewSize bounds the size of the ConversionReviews read from the
+	// API server and from provider webhooks.
+	maxReviewSize = 32 << 20
+	// providerTimeout bounds the calls of provider conversion webhooks.
+	providerTimeout = 25 * time.Second
+)
+
+// Webhook converts the objects of the consumer CRDs between their versions.
+// The conversion is delegated to the service provider: None conversions only
+// change the apiVersion, webhook conversions are sent to the webhook of the
+// service provider, through the service proxy of the provider API server for
+// webhooks referencing a service.
+//
+// The CRD is taken from the request path, see
+// helpers.ConversionWebhookPathPrefix.
+type Webhook struct {
+	getCRD            func(name string) (*apiextensionsv1.CustomResourceDefinition, error)
+	getServiceBinding func(name string) (*kubebindv1alpha1.APIServiceBinding, error)
+	getSecret         func(ns, name string) (*corev1.Secret, error)
+
+	lock sync.Mutex
+	// providerClients are the clients of the provider API servers, by
+	// kubeconfig secret.
+	providerClients map[string]*providerClient
+}
+
+type providerClient struct {
+	resourceVersion string
+	client          rest.Interface
+}
+
+func NewWebhook(
+	getCRD func(name string) (*apiextensionsv1.CustomResourceDefinition, error),
+	getServiceBinding func(name string) (*kubebindv1alpha1.APIServiceBinding, error),
+	getSecret func(ns, name string) (*corev1.Secret, error),
+) *Webhook {
+	return &Webhook{
+		getCRD:            getCRD,
+		getServiceBinding: getServiceBinding,
+		getSecret:         getSecret,
+		providerClients:   map[string]*providerClient{},
+	}
+}
+
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("path", r.URL.Path)
+
+	if r.Method != http.MethodPost {
+		http.Error(rw, "only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, helpers.ConversionWebhookPathPrefix)
+	if name == "" || strings.Contains(name, "/") {
+		http.Error(rw, "invalid path", http.StatusNotFound)
+		return
+	}
+
+	var review apiextensionsv1.ConversionReview
+	if err := json.NewDecoder(io.LimitReader(r.Body, maxReviewSize)).Decode(&review); err != nil {
+		logger.Info("failed to decode ConversionReview", "error", err)
+		http.Error(rw, fmt.Sprintf("failed to decode ConversionReview: %v", err), http.StatusBadRequest)
+		return
+	}
+	if review.Request == nil {
+		http.Error(rw, "ConversionReview has no request", http.StatusBadRequest)
+		return
+	}
+
+	response := &apiextensionsv1.ConversionResponse{UID: review.Request.UID}
+	converted, err := w.convert(r.Context(), name, review.Request.DesiredAPIVersion, review.Request.Objects)
+	if err != nil {
+		logger.Info("failed to convert objects", "crd", name, "desiredAPIVersion", review.Request.DesiredAPIVersion, "error", err)
+		response.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+	} else {
+		response.ConvertedObjects = converted
+		response.Result = metav1.Status{Status: metav1.StatusSuccess}
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(&apiextensionsv1.ConversionReview{
+		TypeMeta: review.TypeMeta,
+		Response: response,
+	}); err != nil {
+		logger.Error(err, "failed to encode ConversionReview")
+	}
+}
+
+// convert converts the objects of the given consumer CRD to the desired
+// version through the conversion of the service provider.
+func (w *Webhook) convert(ctx context.Context, crdName, desiredAPIVersion string, objects []runtime.RawExtension) ([]runtime.RawExtension, error) {
+	crd, err := w.getCRD(crdName)
+	if err != nil {
+		return nil, err
+	}
+	conversion, err := helpers.ProviderConversion(crd)
+	if err != nil {
+		return nil, err
+	}
+	if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter {
+		return setAPIVersion(objects, desiredAPIVersion)
+	}
+	if conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil {
+		return nil, fmt.Errorf("conversion webhook of the service provider of %s is not configured", crdName)
+	}
+
+	binding, err := w.getServiceBinding(crdName)
+	if err != nil {
+		return nil, err
+	}
+	if len(binding.Spec.Providers) == 0 {
+		return nil, fmt.Errorf("APIServiceBinding %s has no provider", binding.Name)
+	}
+
+	// objects are converted by the provider they are synced with
+	byProvider := make([][]int, len(binding.Spec.Providers))
+	for i := range objects {
+		p, err := providerIndex(binding, objects[i])
+		if err != nil {
+			return nil, err
+		}
+		byProvider[p] = append(byProvider[p], i)
+	}
+
+	converted := make([]runtime.RawExtension, len(objects))
+	for p, indexes := range byProvider {
+		if len(indexes) == 0 {
+			continue
+		}
+		batch := make([]runtime.RawExtension, 0, len(indexes))
+		for _, i := range indexes {
+			batch = append(batch, objects[i])
+		}
+		result, err := w.callProviderWebhook(ctx, &binding.Spec.Providers[p], conversion.Webhook, desiredAPIVersion, batch)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range indexes {
+			converted[i] = result[j]
+		}
+	}
+	return converted, nil
+}
+
+// setAPIVersion converts like the None strategy does.
+func setAPIVersion(objects []runtime.RawExtension, apiVersion string) ([]runtime.RawExtension, error) {
+	converted := make([]runtime.RawExtension, 0, len(objects))
+	for _, object := range objects {
+		u := &unstructured.Unstructured{}
+		if err := u.UnmarshalJSON(object.Raw); err != nil {
+			return nil, fmt.Errorf("failed to decode object: %w", err)
+		}
+		u.SetAPIVersion(apiVersion)
+		raw, err := u.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+		converted = append(converted, runtime.RawExtension{Raw: raw})
+	}
+	return converted, nil
+}
+
+// providerIndex returns the provider of the binding the given object is
+// synced with, defaulting to the first provider.
+func providerIndex(binding *kubebindv1alpha1.APIServiceBinding, object runtime.RawExtension) (int, error) {
+	var partial metav1.PartialObjectMetadata
+	if err := json.Unmarshal(object.Raw, &partial); err != nil {
+		return 0, fmt.Errorf("failed to decode object: %w", err)
+	}
+	clusterID := partial.Annotations[konnectormodels.AnnotationProviderClusterID]
+	for i, p := range binding.Spec.Providers {
+		if clusterID != "" && p.ClusterUID == clusterID {
+			return i, nil
+		}
+	}
+	return 0, nil
+}
+
+// callProviderWebhook sends the objects to the conversion webhook of the
+// service provider.
+func (w *Webhook) callProviderWebhook(ctx context.Context, provider *kubebindv1alpha1.Provider, webhook *apiextensionsv1.WebhookConversion, desiredAPIVersion string, objects []runtime.RawExtension) ([]runtime.RawExtension, error) {
+	supported := false
+	for _, v := range webhook.ConversionReviewVersions {
+		supported = supported || v == "v1"
+	}
+	if !supported {
+		return nil, fmt.Errorf("conversion webhook of the service provider does not support ConversionReview v1")
+	}
+
+	request := &apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: apiextensionsv1.SchemeGroupVersion.String(), Kind: "ConversionReview"},
+		Request: &apiextensionsv1.ConversionRequest{
+			UID:               uuid.NewUUID(),
+			DesiredAPIVersion: desiredAPIVersion,
+			Objects:           objects,
+		},
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, providerTimeout)
+	defer cancel()
+
+	var raw []byte
+	clientConfig := webhook.ClientConfig
+	switch {
+	case clientConfig.Service != nil:
+		client, err := w.providerClient(provider)
+		if err != nil {
+			return nil, err
+		}
+		svc := clientConfig.Service
+		port := int32(443)
+		if svc.Port != nil {
+			port = *svc.Port
+		}
+		path := ""
+		if svc.Path != nil {
+			path = *svc.Path
+		}
+		raw, err = client.Post().
+			AbsPath("/api/v1/namespaces", svc.Namespace, "services", fmt.Sprintf("https:%s:%d", svc.Name, port), "proxy", path).
+			SetHeader("Content-Type", "application/json").
+			Body(body).
+			DoRaw(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call conversion webhook of the service provider: %w", err)
+		}
+	case clientConfig.URL != nil:
+		raw, err = postURL(ctx, *clientConfig.URL, clientConfig.CABundle, body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call conversion webhook of the service provider: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("conversion webhook of the service provider has neither service nor url")
+	}
+
+	var response apiextensionsv1.ConversionReview
+	if err := json.Unmarshal(raw, &response); err != nil {
+		return nil, fmt.Errorf("failed to decode ConversionReview of the service provider: %w", err)
+	}
+	if response.Response == nil {
+		return nil, fmt.Errorf("ConversionReview of the service provider has no response")
+	}
+	if response.Response.Result.Status != metav1.StatusSuccess {
+		return nil, fmt.Errorf("conversion by the service provider failed: %s", response.Response.Result.Message)
+	}
+	if len(response.Response.ConvertedObjects) != len(objects) {
+		return nil, fmt.Errorf("conversion webhook of the service provider returned %d objects, expected %d", len(response.Response.ConvertedObjects), len(objects))
+	}
+	return response.Response.ConvertedObjects, nil
+}
+
+// providerClient returns a client of the provider API server, recreated when
+// the kubeconfig secret changes, e.g. with renewed credentials.
+func (w *Webhook) providerClient(provider *kubebindv1alpha1.Provider) (rest.Interface, error) {
+	ref := provider.Kubeconfig
+	secret, err := w.getSecret(ref.Namespace, ref.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubeconfig secret %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	key := ref.Namespace + "/" + ref.Name
+
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if c, found := w.providerClients[key]; found && c.resourceVersion == secret.ResourceVersion {
+		return c.client, nil
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(secret.Data[ref.Key])
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in secret %s: %w", key, err)
+	}
+	config = rest.AddUserAgent(config, "konnector-conversion-webhook")
+	client, err := kubernetesclient.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	w.providerClients[key] = &providerClient{resourceVersion: secret.ResourceVersion, client: client.CoreV1().RESTClient()}
+	return client.CoreV1().RESTClient(), nil
+}
+
+func postURL(ctx context.Context, url string, caBundle []byte, body []byte) ([]byte, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("invalid caBundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxReviewSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, string(raw))
+	}
+	return raw, nil
+}
@@ -0,0 +1,174 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestWebhook(t *testing.T) {
+	// the provider webhook renames spec.size to spec.capacity in v2
+	var providerCalls int
+	provider := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		providerCalls++
+		var review apiextensionsv1.ConversionReview
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&review))
+		review.Response = &apiextensionsv1.ConversionResponse{
+			UID:    review.Request.UID,
+			Result: metav1.Status{Status: metav1.StatusSuccess},
+		}
+		for _, object := range review.Request.Objects {
+			u := &unstructured.Unstructured{}
+			require.NoError(t, u.UnmarshalJSON(object.Raw))
+			size, _, _ := unstructured.NestedString(u.Object, "spec", "size")
+			unstructured.RemoveNestedField(u.Object, "spec", "size")
+			require.NoError(t, unstructured.SetNestedField(u.Object, size, "spec", "capacity"))
+			u.SetAPIVersion(review.Request.DesiredAPIVersion)
+			raw, err := u.MarshalJSON()
+			require.NoError(t, err)
+			review.Response.ConvertedObjects = append(review.Response.ConvertedObjects, runtime.RawExtension{Raw: raw})
+		}
+		review.Request = nil
+		require.NoError(t, json.NewEncoder(w).Encode(&review))
+	}))
+	defer provider.Close()
+	caBundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: provider.Certificate().Raw})
+
+	crds := map[string]*apiextensionsv1.CustomResourceDefinition{
+		"nones.mangodb.com": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "nones.mangodb.com",
+				Annotations: map[string]string{kubebindv1alpha1.ProviderConversionAnnotation: `{"strategy":"None"}`},
+			},
+		},
+		"webhooks.mangodb.com": {
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "webhooks.mangodb.com",
+				Annotations: map[string]string{kubebindv1alpha1.ProviderConversionAnnotation: mustMarshal(t, &apiextensionsv1.CustomResourceConversion{
+					Strategy: apiextensionsv1.WebhookConverter,
+					Webhook: &apiextensionsv1.WebhookConversion{
+						ClientConfig:             &apiextensionsv1.WebhookClientConfig{URL: &provider.URL, CABundle: caBundle},
+						ConversionReviewVersions: []string{"v1"},
+					},
+				})},
+			},
+		},
+	}
+	binding := &kubebindv1alpha1.APIServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhooks.mangodb.com"},
+		Spec: kubebindv1alpha1.APIServiceBindingSpec{
+			Providers: []kubebindv1alpha1.Provider{
+				{ClusterIdentity: kubebindv1alpha1.ClusterIdentity{ClusterUID: "first"}},
+				{ClusterIdentity: kubebindv1alpha1.ClusterIdentity{ClusterUID: "second"}},
+			},
+		},
+	}
+	webhook := NewWebhook(
+		func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return crds[name], nil
+		},
+		func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
+			return binding, nil
+		},
+		func(ns, name string) (*corev1.Secret, error) {
+			t.Fatal("no provider client expected for URL webhooks")
+			return nil, nil
+		},
+	)
+
+	objects := []runtime.RawExtension{
+		{Raw: []byte(`{"apiVersion":"mangodb.com/v1","kind":"MangoDB","metadata":{"name":"a","annotations":{"` + konnectormodels.AnnotationProviderClusterID + `":"second"}},"spec":{"size":"1Gi"}}`)},
+		{Raw: []byte(`{"apiVersion":"mangodb.com/v1","kind":"MangoDB","metadata":{"name":"b"},"spec":{"size":"2Gi"}}`)},
+	}
+
+	// None conversions only change the apiVersion
+	response := review(t, webhook, "/convert/nones.mangodb.com", objects)
+	require.Equal(t, metav1.StatusSuccess, response.Result.Status, response.Result.Message)
+	require.Len(t, response.ConvertedObjects, 2)
+	for i, object := range response.ConvertedObjects {
+		u := &unstructured.Unstructured{}
+		require.NoError(t, u.UnmarshalJSON(object.Raw))
+		require.Equal(t, "mangodb.com/v2", u.GetAPIVersion())
+		size, _, _ := unstructured.NestedString(u.Object, "spec", "size")
+		require.Equal(t, []string{"1Gi", "2Gi"}[i], size)
+	}
+	require.Zero(t, providerCalls)
+
+	// webhook conversions are done by the provider of each object, in order
+	response = review(t, webhook, "/convert/webhooks.mangodb.com", objects)
+	require.Equal(t, metav1.StatusSuccess, response.Result.Status, response.Result.Message)
+	require.Len(t, response.ConvertedObjects, 2)
+	for i, object := range response.ConvertedObjects {
+		u := &unstructured.Unstructured{}
+		require.NoError(t, u.UnmarshalJSON(object.Raw))
+		require.Equal(t, "mangodb.com/v2", u.GetAPIVersion())
+		require.Equal(t, []string{"a", "b"}[i], u.GetName())
+		capacity, _, _ := unstructured.NestedString(u.Object, "spec", "capacity")
+		require.Equal(t, []string{"1Gi", "2Gi"}[i], capacity)
+	}
+	require.Equal(t, 2, providerCalls, "one call per provider")
+
+	// failures of the provider are reported
+	crds["webhooks.mangodb.com"].Annotations[kubebindv1alpha1.ProviderConversionAnnotation] = `{"strategy":"Webhook","webhook":{"clientConfig":{"url":"https://127.0.0.1:1"},"conversionReviewVersions":["v1"]}}`
+	response = review(t, webhook, "/convert/webhooks.mangodb.com", objects)
+	require.Equal(t, metav1.StatusFailure, response.Result.Status)
+}
+
+func review(t *testing.T, webhook http.Handler, path string, objects []runtime.RawExtension) *apiextensionsv1.ConversionResponse {
+	t.Helper()
+
+	body, err := json.Marshal(&apiextensionsv1.ConversionReview{
+		TypeMeta: metav1.TypeMeta{APIVersion: "apiextensions.k8s.io/v1", Kind: "ConversionReview"},
+		Request: &apiextensionsv1.ConversionRequest{
+			UID:               types.UID("uid"),
+			DesiredAPIVersion: "mangodb.com/v2",
+			Objects:           objects,
+		},
+	})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	webhook.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var response apiextensionsv1.ConversionReview
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	require.Equal(t, types.UID("uid"), response.Response.UID)
+	return response.Response
+}
+
+func mustMarshal(t *testing.T, obj interface{}) string {
+	bs, err := json.Marshal(obj)
+	require.NoError(t, err)
+	return string(bs)
+}
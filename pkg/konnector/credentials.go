@@ -0,0 +1,111 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// kubeconfigWithoutTokens returns the kubeconfig with its bearer tokens
+// removed. The service provider renews the tokens before they expire, which
+// must not restart the cluster controller and relist its informers.
+func kubeconfigWithoutTokens(kubeconfig []byte) string {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return string(kubeconfig) // the caller reports the invalid kubeconfig
+	}
+	for _, authInfo := range cfg.AuthInfos {
+		authInfo.Token = ""
+	}
+	bs, err := clientcmd.Write(*cfg)
+	if err != nil {
+		return string(kubeconfig)
+	}
+	return string(bs)
+}
+
+// rotatingBearerToken makes the rest config authenticate with the bearer token
+// currently found in the kubeconfig secret, instead of the one it was created
+// with.
+func rotatingBearerToken(config *rest.Config, getSecret func(ns, name string) (*corev1.Secret, error), ns, name, key string) {
+	if config.BearerToken == "" || config.BearerTokenFile != "" {
+		return
+	}
+	source := &secretTokenSource{
+		getSecret: getSecret,
+		namespace: ns,
+		name:      name,
+		key:       key,
+		token:     config.BearerToken,
+	}
+	config.BearerToken = ""
+	config.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &bearerTokenRoundTripper{source: source, rt: rt}
+	})
+}
+
+// secretTokenSource reads the bearer token from a kubeconfig secret, parsing
+// the kubeconfig only when the secret changes.
+type secretTokenSource struct {
+	getSecret       func(ns, name string) (*corev1.Secret, error)
+	namespace, name string
+	key             string
+	lock            sync.Mutex
+	resourceVersion string
+	token           string
+}
+
+func (s *secretTokenSource) Token() string {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	secret, err := s.getSecret(s.namespace, s.name)
+	if err != nil || secret.ResourceVersion == s.resourceVersion {
+		return s.token // keep the last known token
+	}
+	s.resourceVersion = secret.ResourceVersion
+
+	config, err := clientcmd.RESTConfigFromKubeConfig(secret.Data[s.key])
+	if err != nil || config.BearerToken == "" {
+		return s.token
+	}
+	s.token = config.BearerToken
+	return s.token
+}
+
+type bearerTokenRoundTripper struct {
+	source *secretTokenSource
+	rt     http.RoundTripper
+}
+
+func (b *bearerTokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if len(req.Header.Get("Authorization")) != 0 {
+		return b.rt.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", b.source.Token()))
+	return b.rt.RoundTrip(req)
+}
+
+func (b *bearerTokenRoundTripper) WrappedRoundTripper() http.RoundTripper { return b.rt }
@@ -0,0 +1,89 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// Checker is a named health check. It matches the leader election
+// HealthzAdaptor of client-go.
+type Checker interface {
+	Name() string
+	Check(req *http.Request) error
+}
+
+type namedCheck struct {
+	name  string
+	check func(req *http.Request) error
+}
+
+// NamedCheck returns a Checker running the given function.
+func NamedCheck(name string, check func(req *http.Request) error) Checker {
+	return &namedCheck{name: name, check: check}
+}
+
+func (c *namedCheck) Name() string {
+	return c.name
+}
+
+func (c *namedCheck) Check(req *http.Request) error {
+	return c.check(req)
+}
+
+// PingCheck always succeeds.
+var PingCheck = NamedCheck("ping", func(_ *http.Request) error { return nil })
+
+// Handler returns a handler running all checks. It responds with 200 if all
+// checks succeed, and with 500 otherwise. With the verbose query parameter,
+// or on failure, every check is listed with the reason of its failure.
+func Handler(name string, checks ...Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		var out bytes.Buffer
+		var failed []string
+		for _, c := range checks {
+			if err := c.Check(req); err != nil {
+				fmt.Fprintf(&out, "[-]%s failed: %v\n", c.Name(), err)
+				failed = append(failed, c.Name())
+			} else {
+				fmt.Fprintf(&out, "[+]%s ok\n", c.Name())
+			}
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		if len(failed) > 0 {
+			klog.V(2).InfoS("health check failed", "endpoint", name, "checks", strings.Join(failed, ","))
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprintf(&out, "%s check failed\n", name)
+			w.Write(out.Bytes()) // nolint:errcheck
+			return
+		}
+
+		if _, found := req.URL.Query()["verbose"]; found {
+			fmt.Fprintf(&out, "%s check passed\n", name)
+			w.Write(out.Bytes()) // nolint:errcheck
+			return
+		}
+		fmt.Fprint(w, "ok") // nolint:errcheck
+	})
+}
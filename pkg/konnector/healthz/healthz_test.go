@@ -0,0 +1,70 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package healthz
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	failing := NamedCheck("providers", func(_ *http.Request) error {
+		return errors.New("APIServiceBinding foo: Heartbeating: provider unreachable")
+	})
+
+	tests := []struct {
+		name         string
+		checks       []Checker
+		url          string
+		expectedCode int
+		expectedBody string
+	}{
+		{
+			name:         "ok",
+			checks:       []Checker{PingCheck},
+			url:          "/readyz",
+			expectedCode: http.StatusOK,
+			expectedBody: "ok",
+		},
+		{
+			name:         "verbose",
+			checks:       []Checker{PingCheck},
+			url:          "/readyz?verbose",
+			expectedCode: http.StatusOK,
+			expectedBody: "[+]ping ok\nreadyz check passed\n",
+		},
+		{
+			name:         "failing",
+			checks:       []Checker{PingCheck, failing},
+			url:          "/readyz",
+			expectedCode: http.StatusInternalServerError,
+			expectedBody: "[+]ping ok\n[-]providers failed: APIServiceBinding foo: Heartbeating: provider unreachable\nreadyz check failed\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			Handler("readyz", tt.checks...).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			require.Equal(t, tt.expectedCode, rec.Code)
+			require.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
@@ -27,15 +27,20 @@ import (
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/pkg/committer"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/bindingrequest"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/servicebinding"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/synctuning"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
 
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	crdinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions/apiextensions/v1"
 	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -54,10 +59,15 @@ const (
 // New returns a konnector controller.
 func New(
 	consumerConfig *rest.Config,
+	metadataSync *kubebindv1alpha1.MetadataSync,
+	syncTuning *kubebindv1alpha1.SyncTuning,
+	sharder *sharding.Sharder,
 	serviceBindingInformer bindinformers.APIServiceBindingInformer,
+	bindingRequestInformer bindinformers.BindingRequestInformer,
 	secretInformer coreinformers.SecretInformer,
 	namespaceInformer coreinformers.NamespaceInformer,
 	crdInformer crdinformers.CustomResourceDefinitionInformer,
+	conversionWebhook *apiextensionsv1.WebhookClientConfig,
 ) (*Controller, error) {
 	// queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), controllerName)
 	queue := workqueue.NewRateLimitingQueueWithConfig(workqueue.DefaultControllerRateLimiter(), workqueue.RateLimitingQueueConfig{
@@ -74,7 +84,12 @@ func New(
 		return nil, err
 	}
 
-	servicebindingCtrl, err := servicebinding.NewController(consumerConfig, serviceBindingInformer, secretInformer)
+	servicebindingCtrl, err := servicebinding.NewController(consumerConfig, serviceBindingInformer, secretInformer, sharder, synctuning.NewRateLimiter(synctuning.Merge(syncTuning, nil)))
+	if err != nil {
+		return nil, err
+	}
+
+	bindingrequestCtrl, err := bindingrequest.NewController(consumerConfig, bindingRequestInformer, serviceBindingInformer, secretInformer, namespaceInformer, sharder)
 	if err != nil {
 		return nil, err
 	}
@@ -87,6 +102,7 @@ func New(
 
 		consumerConfig: consumerConfig,
 		bindClient:     bindClient,
+		sharder:        sharder,
 
 		serviceBindingLister:  serviceBindingInformer.Lister(),
 		serviceBindingIndexer: serviceBindingInformer.Informer().GetIndexer(),
@@ -95,6 +111,7 @@ func New(
 		secretIndexer: secretInformer.Informer().GetIndexer(),
 
 		ServiceBindingCtrl: servicebindingCtrl,
+		BindingRequestCtrl: bindingrequestCtrl,
 
 		reconciler: reconciler{
 			controllers: map[string]*controllerContext{},
@@ -106,15 +123,25 @@ func New(
 				for _, provider := range providerInfos {
 					provider.Config = rest.CopyConfig(provider.Config)
 					provider.Config = rest.AddUserAgent(provider.Config, controllerName)
+					if syncTuning.ProviderQPS > 0 {
+						provider.Config.QPS = float32(syncTuning.ProviderQPS)
+					}
+					if syncTuning.ProviderBurst > 0 {
+						provider.Config.Burst = int(syncTuning.ProviderBurst)
+					}
 				}
 
 				return cluster.NewController(
 					reconcileServiceBinding,
 					consumerConfig,
+					metadataSync,
+					syncTuning,
+					sharder,
 					providerInfos,
 					namespaceDynamicInformer,
 					serviceBindingDynamicInformer,
 					crdDynamicInformer,
+					conversionWebhook,
 				)
 			},
 		},
@@ -185,6 +212,7 @@ type Controller struct {
 
 	consumerConfig *rest.Config
 	bindClient     bindclient.Interface
+	sharder        *sharding.Sharder
 
 	serviceBindingLister  bindlisters.APIServiceBindingLister
 	serviceBindingIndexer cache.Indexer
@@ -193,6 +221,7 @@ type Controller struct {
 	secretIndexer cache.Indexer
 
 	ServiceBindingCtrl GenericController
+	BindingRequestCtrl GenericController
 
 	reconciler
 
@@ -210,6 +239,18 @@ func (c *Controller) enqueueServiceBinding(logger klog.Logger, obj interface{})
 	c.queue.Add(key)
 }
 
+func (c *Controller) enqueueAllServiceBindings(logger klog.Logger) {
+	bindings, err := c.serviceBindingLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, binding := range bindings {
+		logger.V(2).Info("queueing APIServiceBinding", "key", binding.Name, "reason", "ShardsChanged")
+		c.queue.Add(binding.Name)
+	}
+}
+
 func (c *Controller) enqueueSecret(logger klog.Logger, obj interface{}) {
 	key, err := cache.DeletionHandlingMetaNamespaceKeyFunc(obj)
 	if err != nil {
@@ -252,11 +293,17 @@ func (k *Controller) Start(ctx context.Context, numThreads int) {
 	logger.Info("Starting Controller")
 	defer logger.Info("Shutting down Controller")
 
+	// start and stop cluster controllers when shards move
+	k.sharder.Subscribe(ctx, func() {
+		k.enqueueAllServiceBindings(logger)
+	})
+
 	for i := 0; i < numThreads; i++ {
 		go wait.UntilWithContext(ctx, k.startWorker, time.Second)
 	}
 
 	go k.ServiceBindingCtrl.Start(ctx, numThreads)
+	go k.BindingRequestCtrl.Start(ctx, numThreads)
 
 	<-ctx.Done()
 }
@@ -307,6 +354,11 @@ func (c *Controller) process(ctx context.Context, key string) error {
 		// update remote condition
 		return nil
 	}
+	if !c.sharder.Owns(sharding.Key(obj)) {
+		// another replica handles it
+		c.removeServiceBinding(ctx, name)
+		return nil
+	}
 
 	old := obj
 	obj = obj.DeepCopy()
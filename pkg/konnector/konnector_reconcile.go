@@ -23,6 +23,7 @@ import (
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/sharding"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -48,12 +49,13 @@ type reconciler struct {
 
 type controllerContext struct {
 	kubeconfig      []string
+	shardKey        string // bindings of different shards must not share a Controller
 	cancel          func()
 	serviceBindings sets.Set[string] // when this is empty, the Controller should be stopped by closing the context
 }
 
 type providerIdentifier struct {
-	kubeconfig, secretRefName, secretRefNamespace, clusterUID string
+	kubeconfig, secretRefName, secretRefNamespace, secretRefKey, clusterUID string
 }
 
 func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.APIServiceBinding) error {
@@ -69,11 +71,13 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 		} else if errors.IsNotFound(err) {
 			logger.V(2).Info("secret not found", "secret", p.Kubeconfig.Namespace+"/"+p.Kubeconfig.Name)
 		} else {
-			kubeconfigs = append(kubeconfigs, string(secret.Data[p.Kubeconfig.Key])+p.ClusterName+p.ClusterUID)
+			// renewed tokens are picked up by the running controller, see rotatingBearerToken
+			kubeconfigs = append(kubeconfigs, kubeconfigWithoutTokens(secret.Data[p.Kubeconfig.Key])+p.ClusterName+p.ClusterUID)
 			idf := providerIdentifier{
 				kubeconfig:         string(secret.Data[p.Kubeconfig.Key]),
 				secretRefName:      p.Kubeconfig.Name,
 				secretRefNamespace: p.Kubeconfig.Namespace,
+				secretRefKey:       p.Kubeconfig.Key,
 			}
 			if p.ClusterUID != "" {
 				idf.clusterUID = p.ClusterUID
@@ -86,9 +90,10 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 	r.lock.Lock()
 	defer r.lock.Unlock()
 	ctrlContext, found := r.controllers[binding.Name]
+	shardKey := sharding.Key(binding)
 
 	// stop existing with old kubeconfig
-	if found && !reflect.DeepEqual(ctrlContext.kubeconfig, kubeconfigs) {
+	if found && (!reflect.DeepEqual(ctrlContext.kubeconfig, kubeconfigs) || ctrlContext.shardKey != shardKey) {
 		logger.V(2).Info("stopping old Controller for APIServiceBinding", "apiservicebinding", binding.Namespace+"/"+binding.Name)
 		ctrlContext.serviceBindings.Delete(binding.Name)
 		if len(ctrlContext.serviceBindings) == 0 {
@@ -105,7 +110,7 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 	// find existing with new kubeconfig
 	// no need to match with the old controller context, create a new instead
 	for _, ctrlContext := range r.controllers {
-		if reflect.DeepEqual(ctrlContext.kubeconfig, kubeconfigs) {
+		if reflect.DeepEqual(ctrlContext.kubeconfig, kubeconfigs) && ctrlContext.shardKey == shardKey {
 			// add to it
 			logger.V(2).Info("adding to existing Controller", "secret", binding.Namespace+"/"+binding.Name)
 			r.controllers[binding.Name] = ctrlContext
@@ -139,7 +144,9 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 			logger.Error(err, "invalid kubeconfig in secret", "namespace", identifier.secretRefNamespace, "name", identifier.secretRefName)
 			return nil // nothing we can do here. The APIServiceBinding Controller will set a condition
 		}
+		rotatingBearerToken(provider.Config, r.getSecret, identifier.secretRefNamespace, identifier.secretRefName, identifier.secretRefKey)
 		provider.ConsumerSecretRefKey = identifier.secretRefNamespace + "/" + identifier.secretRefName
+		provider.ShardKey = shardKey
 
 		provider.ClusterID = identifier.clusterUID
 
@@ -149,6 +156,7 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 	ctrlCtx, cancel := context.WithCancel(ctx)
 	r.controllers[binding.Name] = &controllerContext{
 		kubeconfig:      kubeconfigs,
+		shardKey:        shardKey,
 		cancel:          cancel,
 		serviceBindings: sets.New[string](binding.Name),
 	}
@@ -172,3 +180,23 @@ func (r *reconciler) reconcile(ctx context.Context, binding *kubebindv1alpha1.AP
 
 	return nil
 }
+
+// removeServiceBinding removes the APIServiceBinding from its cluster
+// Controller, which is stopped if no other APIServiceBinding uses it.
+func (r *reconciler) removeServiceBinding(ctx context.Context, name string) {
+	logger := klog.FromContext(ctx)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	ctrlContext, found := r.controllers[name]
+	if !found {
+		return
+	}
+
+	logger.V(2).Info("removing APIServiceBinding from Controller", "apiservicebinding", name)
+	ctrlContext.serviceBindings.Delete(name)
+	if len(ctrlContext.serviceBindings) == 0 {
+		ctrlContext.cancel()
+	}
+	delete(r.controllers, name)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package konnector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	konnectormodels "go.bytebuilders.dev/kube-bind/pkg/konnector/models"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+type fakeController struct{}
+
+func (fakeController) Start(ctx context.Context) {}
+
+func kubeconfigSecret(t *testing.T, host, token, resourceVersion string) *corev1.Secret {
+	t.Helper()
+
+	bs, err := clientcmd.Write(clientcmdapi.Config{
+		Clusters:       map[string]*clientcmdapi.Cluster{"provider": {Server: host, InsecureSkipTLSVerify: true}},
+		AuthInfos:      map[string]*clientcmdapi.AuthInfo{"provider": {Token: token}},
+		Contexts:       map[string]*clientcmdapi.Context{"provider": {Cluster: "provider", AuthInfo: "provider", Namespace: "kube-bind-abc"}},
+		CurrentContext: "provider",
+	})
+	require.NoError(t, err)
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-bind", Name: "kubeconfig", ResourceVersion: resourceVersion},
+		Data:       map[string][]byte{"kubeconfig": bs},
+	}
+}
+
+func TestReconcileRenewedToken(t *testing.T) {
+	var tokens []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tokens = append(tokens, req.Header.Get("Authorization"))
+	}))
+	defer server.Close()
+
+	secret := kubeconfigSecret(t, server.URL, "first", "1")
+	var started []*konnectormodels.ProviderInfo
+	r := &reconciler{
+		controllers: map[string]*controllerContext{},
+		getSecret: func(ns, name string) (*corev1.Secret, error) {
+			return secret, nil
+		},
+		newClusterController: func(providerInfos []*konnectormodels.ProviderInfo, _ func(*kubebindv1alpha1.APIServiceBinding) bool) (startable, error) {
+			started = append(started, providerInfos...)
+			return fakeController{}, nil
+		},
+	}
+	binding := &kubebindv1alpha1.APIServiceBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: "foos"},
+		Spec: kubebindv1alpha1.APIServiceBindingSpec{
+			Providers: []kubebindv1alpha1.Provider{{
+				Kubeconfig: kubebindv1alpha1.ClusterSecretKeyRef{
+					LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: "kubeconfig", Key: "kubeconfig"},
+					Namespace:         "kube-bind",
+				},
+			}},
+		},
+	}
+
+	ctx := context.Background()
+	require.NoError(t, r.reconcile(ctx, binding))
+	require.Len(t, started, 1)
+
+	request := func() {
+		client, err := rest.HTTPClientFor(started[0].Config)
+		require.NoError(t, err)
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+	request()
+
+	// a renewed token is used without restarting the controller
+	secret = kubeconfigSecret(t, server.URL, "second", "2")
+	require.NoError(t, r.reconcile(ctx, binding))
+	require.Len(t, started, 1)
+	request()
+	require.Equal(t, []string{"Bearer first", "Bearer second"}, tokens)
+
+	// other changes restart it
+	secret = kubeconfigSecret(t, server.URL+"/other", "second", "3")
+	require.NoError(t, r.reconcile(ctx, binding))
+	require.Len(t, started, 2)
+}
@@ -0,0 +1,288 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+	_ "k8s.io/component-base/metrics/prometheus/workqueue" // register the workqueue metrics
+)
+
+const (
+	namespace = "kube_bind"
+	subsystem = "konnector"
+
+	// ResultSuccess and ResultError are the values of the result label.
+	ResultSuccess = "success"
+	ResultError   = "error"
+)
+
+var (
+	// Syncs counts the objects synced by the spec and status controllers, by
+	// controller and exported resource.
+	Syncs = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      namespace,
+		Subsystem:      subsystem,
+		Name:           "syncs_total",
+		Help:           "Number of objects synced per controller and exported resource.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"controller", "resource"})
+
+	// SyncErrors counts the failed syncs of the spec and status controllers,
+	// by controller and exported resource.
+	SyncErrors = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      namespace,
+		Subsystem:      subsystem,
+		Name:           "sync_errors_total",
+		Help:           "Number of failed object syncs per controller and exported resource.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"controller", "resource"})
+
+	// Heartbeats counts the ClusterBinding heartbeats by provider cluster and
+	// result.
+	Heartbeats = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      namespace,
+		Subsystem:      subsystem,
+		Name:           "heartbeats_total",
+		Help:           "Number of ClusterBinding heartbeats per provider cluster and result.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"provider", "result"})
+
+	// Orphaned counts the upstream objects orphaned by the Orphan deletion
+	// policy, by exported resource.
+	Orphaned = metrics.NewCounterVec(&metrics.CounterOpts{
+		Namespace:      namespace,
+		Subsystem:      subsystem,
+		Name:           "orphaned_objects_total",
+		Help:           "Number of upstream objects orphaned instead of deleted per exported resource.",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"resource"})
+
+	// ShardsHeld is the number of APIServiceBinding shards held by this replica
+	// in sharded mode.
+	ShardsHeld = metrics.NewGauge(&metrics.GaugeOpts{
+		Namespace:      namespace,
+		Subsystem:      subsystem,
+		Name:           "shards_held",
+		Help:           "Number of APIServiceBinding shards held by this konnector replica in sharded mode.",
+		StabilityLevel: metrics.ALPHA,
+	})
+
+	// ProviderReachable is 1 if the API server of a provider cluster is
+	// reachable, and 0 otherwise.
+	ProviderReachable = metrics.NewGaugeVec(&metrics.GaugeOpts{
+		Namespace:      namespace,
+		Subsystem:      subsystem,
+		Name:           "provider_reachable",
+		Help:           "Whether the API server of a provider cluster is reachable (1) or not (0).",
+		StabilityLevel: metrics.ALPHA,
+	}, []string{"provider"})
+
+	heartbeatAgeDesc = metrics.NewDesc(
+		metrics.BuildFQName(namespace, subsystem, "heartbeat_age_seconds"),
+		"Seconds since the last successful ClusterBinding heartbeat per provider cluster.",
+		[]string{"provider"}, nil,
+		metrics.ALPHA, "",
+	)
+	lastHeartbeatDesc = metrics.NewDesc(
+		metrics.BuildFQName(namespace, subsystem, "last_heartbeat_timestamp_seconds"),
+		"Unix time of the last successful ClusterBinding heartbeat per provider cluster.",
+		[]string{"provider"}, nil,
+		metrics.ALPHA, "",
+	)
+
+	informersSyncedDesc = metrics.NewDesc(
+		metrics.BuildFQName(namespace, subsystem, "informers_synced"),
+		"Whether the informers of a provider cluster are synced (1) or not (0), per informer.",
+		[]string{"provider", "informer"}, nil,
+		metrics.ALPHA, "",
+	)
+
+	heartbeats      = &heartbeatCollector{last: map[string]time.Time{}}
+	informersSynced = &informersSyncedCollector{trackers: map[*InformersSynced]struct{}{}}
+)
+
+func init() {
+	legacyregistry.MustRegister(Syncs, SyncErrors, Heartbeats, Orphaned, ShardsHeld, ProviderReachable)
+	legacyregistry.CustomMustRegister(heartbeats, informersSynced)
+}
+
+// RecordSync records the result of an object sync.
+func RecordSync(controller, resource string, err error) {
+	Syncs.WithLabelValues(controller, resource).Inc()
+	if err != nil {
+		SyncErrors.WithLabelValues(controller, resource).Inc()
+	}
+}
+
+// RecordOrphaned records an upstream object orphaned by the Orphan deletion policy.
+func RecordOrphaned(resource string) {
+	Orphaned.WithLabelValues(resource).Inc()
+}
+
+// RecordHeartbeat records the result of a heartbeat of the given provider
+// cluster, with last being the heartbeat time on success.
+func RecordHeartbeat(provider string, last time.Time, err error) {
+	if err != nil {
+		Heartbeats.WithLabelValues(provider, ResultError).Inc()
+		return
+	}
+	Heartbeats.WithLabelValues(provider, ResultSuccess).Inc()
+	heartbeats.set(provider, last)
+}
+
+// InformersSynced records whether the informers of a syncer or controller are
+// synced. Its series are exposed until Forget is called when the informers
+// stop, such that the series of a replacement are not touched.
+type InformersSynced struct {
+	collector *informersSyncedCollector
+
+	lock      sync.RWMutex
+	synced    map[[2]string]bool
+	forgotten bool
+}
+
+// NewInformersSynced returns a new InformersSynced, exposed until Forget is called.
+func NewInformersSynced() *InformersSynced {
+	return informersSynced.newTracker()
+}
+
+// Record records whether the informer of a provider cluster is synced. It is
+// a no-op after Forget.
+func (s *InformersSynced) Record(provider, informer string, synced bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if !s.forgotten {
+		s.synced[[2]string{provider, informer}] = synced
+	}
+}
+
+// Forget removes the series of s.
+func (s *InformersSynced) Forget() {
+	s.lock.Lock()
+	s.forgotten = true
+	s.lock.Unlock()
+	s.collector.remove(s)
+}
+
+// RecordProviderReachable records whether the API server of a provider
+// cluster is reachable.
+func RecordProviderReachable(provider string, reachable bool) {
+	value := 0.0
+	if reachable {
+		value = 1
+	}
+	ProviderReachable.WithLabelValues(provider).Set(value)
+}
+
+// RecordShardsHeld records the number of shards held by this replica.
+func RecordShardsHeld(n int) {
+	ShardsHeld.Set(float64(n))
+}
+
+// heartbeatCollector exposes the time and age of the last heartbeat. The age
+// is computed on scraping such that a stuck heartbeat shows up.
+type heartbeatCollector struct {
+	metrics.BaseStableCollector
+
+	lock sync.RWMutex
+	last map[string]time.Time
+}
+
+var _ metrics.StableCollector = &heartbeatCollector{}
+
+func (c *heartbeatCollector) set(provider string, last time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.last[provider] = last
+}
+
+func (c *heartbeatCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- heartbeatAgeDesc
+	ch <- lastHeartbeatDesc
+}
+
+func (c *heartbeatCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	now := time.Now()
+	for provider, last := range c.last {
+		ch <- metrics.NewLazyConstMetric(heartbeatAgeDesc, metrics.GaugeValue, now.Sub(last).Seconds(), provider)
+		ch <- metrics.NewLazyConstMetric(lastHeartbeatDesc, metrics.GaugeValue, float64(last.Unix()), provider)
+	}
+}
+
+// informersSyncedCollector exposes the informers synced of all trackers not
+// forgotten. An informer recorded by multiple trackers, e.g. while a syncer is
+// replaced, is synced if any of them is.
+type informersSyncedCollector struct {
+	metrics.BaseStableCollector
+
+	lock     sync.RWMutex
+	trackers map[*InformersSynced]struct{}
+}
+
+var _ metrics.StableCollector = &informersSyncedCollector{}
+
+func (c *informersSyncedCollector) newTracker() *InformersSynced {
+	s := &InformersSynced{collector: c, synced: map[[2]string]bool{}}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.trackers[s] = struct{}{}
+	return s
+}
+
+func (c *informersSyncedCollector) remove(s *InformersSynced) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.trackers, s)
+}
+
+func (c *informersSyncedCollector) DescribeWithStability(ch chan<- *metrics.Desc) {
+	ch <- informersSyncedDesc
+}
+
+func (c *informersSyncedCollector) CollectWithStability(ch chan<- metrics.Metric) {
+	c.lock.RLock()
+	synced := map[[2]string]bool{}
+	for s := range c.trackers {
+		s.lock.RLock()
+		for labels, v := range s.synced {
+			synced[labels] = synced[labels] || v
+		}
+		s.lock.RUnlock()
+	}
+	c.lock.RUnlock()
+
+	for labels, v := range synced {
+		value := 0.0
+		if v {
+			value = 1
+		}
+		ch <- metrics.NewLazyConstMetric(informersSyncedDesc, metrics.GaugeValue, value, labels[0], labels[1])
+	}
+}
+
+// Handler returns the handler serving the metrics.
+func Handler() http.Handler {
+	return legacyregistry.Handler()
+}
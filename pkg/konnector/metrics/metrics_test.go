@@ -0,0 +1,61 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/component-base/metrics/testutil"
+)
+
+func TestInformersSynced(t *testing.T) {
+	expect := func(series ...string) string {
+		s := `
+# HELP kube_bind_konnector_informers_synced [ALPHA] Whether the informers of a provider cluster are synced (1) or not (0), per informer.
+# TYPE kube_bind_konnector_informers_synced gauge
+`
+		for _, line := range series {
+			s += line + "\n"
+		}
+		return s
+	}
+	collector := &informersSyncedCollector{trackers: map[*InformersSynced]struct{}{}}
+	compare := func(expected string) {
+		t.Helper()
+		// a fresh copy, as a collector can only be registered once
+		c := &informersSyncedCollector{trackers: collector.trackers}
+		require.NoError(t, testutil.CustomCollectAndCompare(c, strings.NewReader(expected), "kube_bind_konnector_informers_synced"))
+	}
+
+	old := collector.newTracker()
+	old.Record("abc", "mangodbs.example.com", true)
+	compare(expect(`kube_bind_konnector_informers_synced{informer="mangodbs.example.com",provider="abc"} 1`))
+
+	// a replacement is not affected by the old tracker being forgotten
+	replacement := collector.newTracker()
+	replacement.Record("abc", "mangodbs.example.com", false)
+	compare(expect(`kube_bind_konnector_informers_synced{informer="mangodbs.example.com",provider="abc"} 1`))
+	old.Forget()
+	old.Record("abc", "mangodbs.example.com", true)
+	compare(expect(`kube_bind_konnector_informers_synced{informer="mangodbs.example.com",provider="abc"} 0`))
+
+	// stopped informers disappear
+	replacement.Forget()
+	compare("")
+}
@@ -22,7 +22,7 @@ import (
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions"
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
-	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/serviceexport/multinsinformer"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/cluster/reachability"
 	"go.bytebuilders.dev/kube-bind/pkg/konnector/controllers/dynamic"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -42,7 +42,10 @@ type ProviderInfo struct {
 	BindInformer                                             bindinformers.SharedInformerFactory
 	KubeInformer                                             kubernetesinformers.SharedInformerFactory
 	DynamicServiceNamespaceInformer                          dynamic.Informer[bindlisters.APIServiceNamespaceLister]
-	ProviderDynamicInformer                                  multinsinformer.GetterInformer
+	Reachability                                             *reachability.Tracker
+
+	// ShardKey is the shard key of the APIServiceBindings served through this provider.
+	ShardKey string
 }
 
 func GetProviderInfoWithClusterID(providerInfos []*ProviderInfo, clusterID string) (*ProviderInfo, error) {
@@ -20,8 +20,14 @@ import (
 	"fmt"
 	"math/rand"
 	"os"
+	"strings"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/models"
 
 	"github.com/spf13/pflag"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/component-base/logs"
 	logsv1 "k8s.io/component-base/logs/api/v1"
 )
@@ -38,12 +44,38 @@ type ExtraOptions struct {
 	LeaseLockName      string
 	LeaseLockNamespace string
 	LeaseLockIdentity  string
+	Shards             int
+
+	MetricsBindAddress     string
+	HealthProbeBindAddress string
+
+	ConversionWebhookBindAddress string
+	ConversionWebhookService     string
+	ConversionWebhookNamespace   string
+
+	LabelsToProvider      []string
+	AnnotationsToProvider []string
+	LabelsToConsumer      []string
+	AnnotationsToConsumer []string
+
+	SyncWorkers        int32
+	ProviderQPS        int32
+	ProviderBurst      int32
+	SyncRetryBaseDelay time.Duration
+	SyncRetryMaxDelay  time.Duration
 }
 
 type completedOptions struct {
 	Logs *logs.Options
 
 	ExtraOptions
+
+	// MetadataSync holds the default label and annotation filters of
+	// APIServiceExports.
+	MetadataSync *kubebindv1alpha1.MetadataSync
+	// SyncTuning holds the default tuning of the sync controllers of
+	// APIServiceBindings.
+	SyncTuning *kubebindv1alpha1.SyncTuning
 }
 
 type CompletedOptions struct {
@@ -62,12 +94,43 @@ func NewOptions() *Options {
 			LeaseLockName:      "kube-bind",
 			LeaseLockNamespace: os.Getenv("POD_NAMESPACE"),
 			LeaseLockIdentity:  os.Getenv("POD_NAME"),
+
+			MetricsBindAddress:     ":8080",
+			HealthProbeBindAddress: ":8081",
+
+			ConversionWebhookBindAddress: ":9443",
+			ConversionWebhookService:     "konnector",
+			ConversionWebhookNamespace:   os.Getenv("POD_NAMESPACE"),
+
+			// keep GitOps and client tool metadata on the consumer side
+			LabelsToProvider: []string{
+				"*",
+				"-argocd.argoproj.io/",
+				"-kustomize.toolkit.fluxcd.io/",
+				"-helm.toolkit.fluxcd.io/",
+			},
+			AnnotationsToProvider: []string{
+				"*",
+				"-kubectl.kubernetes.io/",
+				"-argocd.argoproj.io/",
+				"-kustomize.toolkit.fluxcd.io/",
+				"-helm.toolkit.fluxcd.io/",
+				"-meta.helm.sh/",
+			},
+
+			// the defaults of workqueue.DefaultControllerRateLimiter
+			SyncWorkers:        1,
+			SyncRetryBaseDelay: 5 * time.Millisecond,
+			SyncRetryMaxDelay:  1000 * time.Second,
 		},
 	}
 
 	if opts.LeaseLockNamespace == "" {
 		opts.LeaseLockNamespace = "kube-system"
 	}
+	if opts.ConversionWebhookNamespace == "" {
+		opts.ConversionWebhookNamespace = models.KonnectorNamespace
+	}
 
 	return opts
 }
@@ -78,6 +141,23 @@ func (options *Options) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&options.KubeConfigPath, "kubeconfig", options.KubeConfigPath, "Kubeconfig file for the local cluster.")
 	fs.StringVar(&options.LeaseLockName, "lease-name", options.LeaseLockName, "Name of lease lock")
 	fs.StringVar(&options.LeaseLockNamespace, "lease-namespace", options.LeaseLockNamespace, "Name of lease lock namespace")
+	fs.IntVar(&options.Shards, "shards", options.Shards, "Number of shards the APIServiceBindings are split into between the replicas, keeping the bindings of one provider kubeconfig secret together. With 0, a single replica elected as leader handles all APIServiceBindings. Shard leases are prefixed with the lease name.")
+	fs.StringVar(&options.MetricsBindAddress, "metrics-bind-address", options.MetricsBindAddress, "The address the Prometheus metrics endpoint binds to. Set to \"0\" to disable serving metrics.")
+	fs.StringVar(&options.HealthProbeBindAddress, "health-probe-bind-address", options.HealthProbeBindAddress, "The address the /healthz and /readyz probe endpoints bind to. Set to \"0\" to disable serving probes.")
+	fs.StringVar(&options.ConversionWebhookBindAddress, "conversion-webhook-bind-address", options.ConversionWebhookBindAddress, "The address the conversion webhook of the bound CRDs binds to. Set to \"0\" to disable the webhook, such that bound CRDs only serve the version objects are synced through.")
+	fs.StringVar(&options.ConversionWebhookService, "conversion-webhook-service", options.ConversionWebhookService, "Name of the service in front of the conversion webhook, called by the API server.")
+	fs.StringVar(&options.ConversionWebhookNamespace, "conversion-webhook-namespace", options.ConversionWebhookNamespace, "Namespace of the conversion webhook service and of the secret holding its serving certificate.")
+
+	fs.StringSliceVar(&options.LabelsToProvider, "sync-labels-to-provider", options.LabelsToProvider, "Key prefixes of labels synced from consumer to provider objects, unless overridden by the APIServiceExport. Prefixes starting with '-' are excluded, '*' matches all keys.")
+	fs.StringSliceVar(&options.AnnotationsToProvider, "sync-annotations-to-provider", options.AnnotationsToProvider, "Key prefixes of annotations synced from consumer to provider objects, unless overridden by the APIServiceExport. Prefixes starting with '-' are excluded, '*' matches all keys.")
+	fs.StringSliceVar(&options.LabelsToConsumer, "sync-labels-to-consumer", options.LabelsToConsumer, "Key prefixes of labels synced from provider to consumer objects, unless overridden by the APIServiceExport. Prefixes starting with '-' are excluded, '*' matches all keys.")
+	fs.StringSliceVar(&options.AnnotationsToConsumer, "sync-annotations-to-consumer", options.AnnotationsToConsumer, "Key prefixes of annotations synced from provider to consumer objects, unless overridden by the APIServiceExport. Prefixes starting with '-' are excluded, '*' matches all keys.")
+
+	fs.Int32Var(&options.SyncWorkers, "sync-workers", options.SyncWorkers, "Number of objects reconciled in parallel by each sync controller of a bound resource, unless overridden by the APIServiceBinding.")
+	fs.Int32Var(&options.ProviderQPS, "provider-qps", options.ProviderQPS, "Maximum requests per second towards each provider cluster, unless overridden for the sync controllers by the APIServiceBinding. 0 uses the client default.")
+	fs.Int32Var(&options.ProviderBurst, "provider-burst", options.ProviderBurst, "Maximum burst of requests towards each provider cluster, unless overridden for the sync controllers by the APIServiceBinding. 0 uses the client default.")
+	fs.DurationVar(&options.SyncRetryBaseDelay, "sync-retry-base-delay", options.SyncRetryBaseDelay, "Delay of the first retry of a failed object in the konnector controllers, doubling with every further failure. The sync controllers of a bound resource use the APIServiceBinding override if set.")
+	fs.DurationVar(&options.SyncRetryMaxDelay, "sync-retry-max-delay", options.SyncRetryMaxDelay, "Maximum delay between retries of a failed object in the konnector controllers. The sync controllers of a bound resource use the APIServiceBinding override if set.")
 }
 
 func (options *Options) Complete() (*CompletedOptions, error) {
@@ -89,10 +169,56 @@ func (options *Options) Complete() (*CompletedOptions, error) {
 		completedOptions: &completedOptions{
 			Logs:         options.Logs,
 			ExtraOptions: options.ExtraOptions,
+			MetadataSync: &kubebindv1alpha1.MetadataSync{
+				ToProvider: &kubebindv1alpha1.MetadataFilter{
+					Labels:      parsePrefixFilter(options.LabelsToProvider),
+					Annotations: parsePrefixFilter(options.AnnotationsToProvider),
+				},
+				ToConsumer: &kubebindv1alpha1.MetadataFilter{
+					Labels:      parsePrefixFilter(options.LabelsToConsumer),
+					Annotations: parsePrefixFilter(options.AnnotationsToConsumer),
+				},
+			},
+			SyncTuning: &kubebindv1alpha1.SyncTuning{
+				Workers:        options.SyncWorkers,
+				ProviderQPS:    options.ProviderQPS,
+				ProviderBurst:  options.ProviderBurst,
+				RetryBaseDelay: &metav1.Duration{Duration: options.SyncRetryBaseDelay},
+				RetryMaxDelay:  &metav1.Duration{Duration: options.SyncRetryMaxDelay},
+			},
 		},
 	}, nil
 }
 
+// parsePrefixFilter turns a list of included prefixes and excluded prefixes
+// starting with '-' into a PrefixFilter.
+func parsePrefixFilter(prefixes []string) *kubebindv1alpha1.PrefixFilter {
+	filter := &kubebindv1alpha1.PrefixFilter{}
+	for _, prefix := range prefixes {
+		if excluded, found := strings.CutPrefix(prefix, "-"); found {
+			filter.Exclude = append(filter.Exclude, excluded)
+		} else {
+			filter.Include = append(filter.Include, prefix)
+		}
+	}
+	return filter
+}
+
 func (options *CompletedOptions) Validate() error {
+	if options.Shards < 0 {
+		return fmt.Errorf("--shards must not be negative")
+	}
+	if options.SyncWorkers < 1 {
+		return fmt.Errorf("--sync-workers must be at least 1")
+	}
+	if options.ProviderQPS < 0 {
+		return fmt.Errorf("--provider-qps must not be negative")
+	}
+	if options.ProviderBurst < 0 {
+		return fmt.Errorf("--provider-burst must not be negative")
+	}
+	if options.SyncRetryBaseDelay <= 0 || options.SyncRetryMaxDelay < options.SyncRetryBaseDelay {
+		return fmt.Errorf("--sync-retry-base-delay must be positive and not exceed --sync-retry-max-delay")
+	}
 	return nil
 }
@@ -18,27 +18,76 @@ package konnector
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"time"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
-
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1/helpers"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/conversion"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/healthz"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
+
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/client-go/tools/leaderelection"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
 	"kmodules.xyz/client-go/apiextensions"
 )
 
+// leaderElectionTimeout is how long the lease may not be renewed by the
+// leader before the liveness probe fails.
+const leaderElectionTimeout = 20 * time.Second
+
 type Server struct {
 	Config     *Config
 	Controller *Controller
+
+	// LeaderElection reports the leader election state to the liveness probe.
+	LeaderElection *leaderelection.HealthzAdaptor
+
+	// conversionWebhookCert is the serving certificate of the conversion
+	// webhook. It is nil if the webhook is disabled.
+	conversionWebhookCert *tls.Certificate
 }
 
-func NewServer(config *Config) (*Server, error) {
+func NewServer(ctx context.Context, config *Config) (*Server, error) {
+	// the conversion webhook is called by the API server through the service
+	var conversionWebhook *apiextensionsv1.WebhookClientConfig
+	var conversionWebhookCert *tls.Certificate
+	if config.ConversionWebhookBindAddress != "" && config.ConversionWebhookBindAddress != "0" {
+		var caBundle []byte
+		var err error
+		conversionWebhookCert, caBundle, err = conversion.EnsureServingCertificate(ctx, config.KubeClient, config.ConversionWebhookNamespace, config.ConversionWebhookService+"-conversion-webhook", config.ConversionWebhookService)
+		if err != nil {
+			return nil, err
+		}
+		conversionWebhook = &apiextensionsv1.WebhookClientConfig{
+			Service: &apiextensionsv1.ServiceReference{
+				Namespace: config.ConversionWebhookNamespace,
+				Name:      config.ConversionWebhookService,
+				Port:      ptr.To[int32](443),
+			},
+			CABundle: caBundle,
+		}
+	}
+
 	// construct controllers
 	k, err := New(
 		config.ClientConfig,
+		config.MetadataSync,
+		config.SyncTuning,
+		config.Sharder,
 		config.BindInformers.KubeBind().V1alpha1().APIServiceBindings(),
+		config.BindInformers.KubeBind().V1alpha1().BindingRequests(),
 		config.KubeInformers.Core().V1().Secrets(), // TODO(sttts): watch individual secrets for security and memory consumption
 		config.KubeInformers.Core().V1().Namespaces(),
 		config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
+		conversionWebhook,
 	)
 	if err != nil {
 		return nil, err
@@ -47,6 +96,10 @@ func NewServer(config *Config) (*Server, error) {
 	s := &Server{
 		Config:     config,
 		Controller: k,
+
+		LeaderElection: leaderelection.NewLeaderHealthzAdaptor(leaderElectionTimeout),
+
+		conversionWebhookCert: conversionWebhookCert,
 	}
 
 	return s, nil
@@ -64,6 +117,7 @@ func (s *Server) PrepareRun(ctx context.Context) (Prepared, error) {
 	// install/upgrade CRDs
 	if err := apiextensions.RegisterCRDs(s.Config.ApiextensionsClient, []*apiextensions.CustomResourceDefinition{
 		kubebindv1alpha1.APIServiceBinding{}.CustomResourceDefinition(),
+		kubebindv1alpha1.BindingRequest{}.CustomResourceDefinition(),
 	}); err != nil {
 		return Prepared{}, err
 	}
@@ -93,6 +147,93 @@ func (s *Prepared) OptionallyStartInformers(ctx context.Context) {
 	)
 }
 
+// OptionallyStartMetricsServer serves the Prometheus metrics unless disabled
+// with bind address "0".
+func (s *Prepared) OptionallyStartMetricsServer(ctx context.Context) error {
+	if s.Config.MetricsBindAddress == "" || s.Config.MetricsBindAddress == "0" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	return serve(ctx, "metrics", s.Config.MetricsBindAddress, mux, nil)
+}
+
+// OptionallyStartConversionWebhookServer serves the conversion webhook of the
+// bound CRDs unless disabled with bind address "0". All replicas serve it,
+// independently of leader election and sharding.
+func (s *Prepared) OptionallyStartConversionWebhookServer(ctx context.Context) error {
+	if s.conversionWebhookCert == nil {
+		return nil
+	}
+
+	webhook := conversion.NewWebhook(
+		func(name string) (*apiextensionsv1.CustomResourceDefinition, error) {
+			return s.Config.ApiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions().Lister().Get(name)
+		},
+		func(name string) (*kubebindv1alpha1.APIServiceBinding, error) {
+			return s.Config.BindInformers.KubeBind().V1alpha1().APIServiceBindings().Lister().Get(name)
+		},
+		func(ns, name string) (*corev1.Secret, error) {
+			return s.Config.KubeInformers.Core().V1().Secrets().Lister().Secrets(ns).Get(name)
+		},
+	)
+	mux := http.NewServeMux()
+	mux.Handle(helpers.ConversionWebhookPathPrefix, webhook)
+	return serve(ctx, "conversion webhook", s.Config.ConversionWebhookBindAddress, mux, &tls.Config{
+		Certificates: []tls.Certificate{*s.conversionWebhookCert},
+		MinVersion:   tls.VersionTLS12,
+	})
+}
+
+// OptionallyStartHealthProbeServer serves the /healthz and /readyz probes
+// unless disabled with bind address "0". Liveness reflects the leader
+// election, readiness the consumer informers and the provider connectivity of
+// the APIServiceBindings.
+func (s *Prepared) OptionallyStartHealthProbeServer(ctx context.Context) error {
+	if s.Config.HealthProbeBindAddress == "" || s.Config.HealthProbeBindAddress == "0" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", healthz.Handler("healthz", healthz.PingCheck, s.LeaderElection))
+	mux.Handle("/readyz", healthz.Handler("readyz", healthz.PingCheck, s.informersSyncedCheck(), s.providersCheck()))
+	return serve(ctx, "health probes", s.Config.HealthProbeBindAddress, mux, nil)
+}
+
+// serve serves the handler on the given address until ctx is done, over TLS
+// if a TLS config is given. Listening errors are returned right away.
+func serve(ctx context.Context, name, address string, handler http.Handler, tlsConfig *tls.Config) error {
+	logger := klog.FromContext(ctx)
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return fmt.Errorf("failed to serve %s: %w", name, err)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	server := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx) // nolint:errcheck
+	}()
+	go func() {
+		logger.Info("serving "+name, "address", listener.Addr().String())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Error(err, "failed to serve "+name)
+		}
+	}()
+
+	return nil
+}
+
 func (s Prepared) Run(ctx context.Context) error {
 	s.Controller.Start(ctx, 2)
 	return nil
@@ -0,0 +1,387 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/konnector/metrics"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coordinationv1client "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// MemberLabel is set on the member leases of the replicas to the lease
+	// name of the konnector.
+	MemberLabel = "kube-bind.appscode.com/konnector-member"
+
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// Sharder splits APIServiceBindings between konnector replicas. Bindings are
+// hashed by their shard key onto a fixed number of shards, such that bindings
+// sharing a provider kubeconfig secret, and hence the per-provider cluster
+// controllers, are handled by one replica. Every shard is assigned to one of the
+// live replicas through rendezvous hashing, and is only worked on while its
+// shard lease is held. Replicas announce themselves through member leases, such
+// that shards are rebalanced when replicas join or leave.
+//
+// A nil Sharder owns all bindings.
+type Sharder struct {
+	client    coordinationv1client.LeasesGetter
+	namespace string
+	name      string
+	identity  string
+	shards    int
+
+	lock sync.RWMutex
+	// held are the shards whose lease is held, with the last renew time.
+	held     map[int]time.Time
+	handlers map[*func()]struct{}
+}
+
+// New returns a sharder with the given number of shards. The leases are
+// created in the given namespace, prefixed with the given name.
+func New(client coordinationv1client.LeasesGetter, namespace, name, identity string, shards int) *Sharder {
+	return &Sharder{
+		client:    client,
+		namespace: namespace,
+		name:      name,
+		identity:  identity,
+		shards:    shards,
+
+		held:     map[int]time.Time{},
+		handlers: map[*func()]struct{}{},
+	}
+}
+
+// Key returns the shard key of the APIServiceBinding. This is the
+// namespace/name of the kubeconfig secret of its first provider, or the
+// binding name if it has no provider.
+func Key(binding *kubebindv1alpha1.APIServiceBinding) string {
+	if len(binding.Spec.Providers) == 0 {
+		return binding.Name
+	}
+	return SecretKey(binding.Spec.Providers[0].Kubeconfig.Namespace, binding.Spec.Providers[0].Kubeconfig.Name)
+}
+
+// SecretKey returns the shard key of the APIServiceBindings using the given
+// provider kubeconfig secret.
+func SecretKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// Shard returns the shard of the given shard key.
+func Shard(key string, shards int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key)) // nolint:errcheck
+	return int(h.Sum32() % uint32(shards))
+}
+
+// Owns returns true if this replica holds the shard of the given shard key.
+func (s *Sharder) Owns(key string) bool {
+	if s == nil {
+		return true
+	}
+
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	_, found := s.held[Shard(key, s.shards)]
+	return found
+}
+
+// Subscribe calls the handler whenever shards are gained or lost, until ctx
+// is done.
+func (s *Sharder) Subscribe(ctx context.Context, handler func()) {
+	if s == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.handlers[&handler] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		s.lock.Lock()
+		defer s.lock.Unlock()
+		delete(s.handlers, &handler)
+	}()
+}
+
+// Run announces this replica and acquires, renews and releases shard leases
+// until ctx is done. Then all leases of the replica are released.
+func (s *Sharder) Run(ctx context.Context) {
+	logger := klog.FromContext(ctx).WithValues("identity", s.identity, "shards", s.shards)
+	ctx = klog.NewContext(ctx, logger)
+
+	logger.Info("starting sharder")
+	defer logger.Info("stopped sharder")
+
+	// shards given up are released one period later, when their work is stopped.
+	releasing := sets.New[int]()
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		for shard := range releasing {
+			if err := s.releaseShard(ctx, shard); err != nil {
+				logger.Error(err, "failed to release shard lease", "shard", shard)
+				continue
+			}
+			releasing.Delete(shard)
+		}
+
+		// without knowing the members, keep the held shards as long as they can be renewed
+		assigned := s.heldShards()
+		members, err := s.renewMember(ctx)
+		if err != nil {
+			logger.Error(err, "failed to renew member lease")
+		} else {
+			assigned = sets.New[int]()
+			for shard := 0; shard < s.shards; shard++ {
+				if Assign(shard, members) == s.identity {
+					assigned.Insert(shard)
+				}
+			}
+		}
+
+		var gained, lost []int
+		for shard := 0; shard < s.shards; shard++ {
+			lastRenew, held := s.heldSince(shard)
+			switch {
+			case assigned.Has(shard):
+				if err := s.acquireShard(ctx, shard); err != nil {
+					if held && time.Since(lastRenew) > renewDeadline {
+						logger.Error(err, "failed to renew shard lease", "shard", shard)
+						lost = append(lost, shard)
+					} else if !held {
+						logger.V(4).Info("shard lease not acquired", "shard", shard, "reason", err)
+					}
+					continue
+				}
+				if !held {
+					gained = append(gained, shard)
+				}
+				s.setHeld(shard, time.Now())
+			case held && !assigned.Has(shard):
+				lost = append(lost, shard)
+				releasing.Insert(shard)
+			}
+		}
+		if len(gained) > 0 || len(lost) > 0 {
+			logger.Info("shards rebalanced", "gained", gained, "lost", lost, "members", len(members))
+			s.update(gained, lost)
+		}
+	}, retryPeriod)
+
+	// release everything on shutdown, with a fresh context
+	s.update(nil, sets.List(s.heldShards()))
+	releaseCtx, cancel := context.WithTimeout(context.Background(), renewDeadline)
+	defer cancel()
+	for shard := 0; shard < s.shards; shard++ {
+		if err := s.releaseShard(releaseCtx, shard); err != nil {
+			logger.Error(err, "failed to release shard lease", "shard", shard)
+		}
+	}
+	if err := s.client.Leases(s.namespace).Delete(releaseCtx, s.memberLeaseName(), metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		logger.Error(err, "failed to delete member lease")
+	}
+}
+
+// Assign returns the member the shard is assigned to through rendezvous
+// hashing. Only the shards of a leaving member move, and a joining member only
+// takes shards from others.
+func Assign(shard int, members []string) string {
+	var owner string
+	var maxScore uint64
+	for _, member := range members {
+		h := fnv.New64a()
+		fmt.Fprintf(h, "%s/%d", member, shard)
+		if score := mix(h.Sum64()); owner == "" || score > maxScore || (score == maxScore && member < owner) {
+			owner, maxScore = member, score
+		}
+	}
+	return owner
+}
+
+// mix is the splitmix64 finalizer. FNV alone spreads short keys which differ
+// only in a few bytes badly.
+func mix(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// renewMember renews the member lease of this replica and returns the
+// identities of all live members.
+func (s *Sharder) renewMember(ctx context.Context) ([]string, error) {
+	if err := s.renewLease(ctx, s.memberLeaseName(), map[string]string{MemberLabel: s.name}, true); err != nil {
+		return nil, err
+	}
+
+	leases, err := s.client.Leases(s.namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{MemberLabel: s.name}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	members := sets.New[string](s.identity)
+	for i := range leases.Items {
+		if lease := &leases.Items[i]; !expired(lease) && lease.Spec.HolderIdentity != nil {
+			members.Insert(*lease.Spec.HolderIdentity)
+		}
+	}
+	return sets.List(members), nil
+}
+
+// acquireShard acquires or renews the lease of the shard. It fails if another
+// member holds an unexpired lease.
+func (s *Sharder) acquireShard(ctx context.Context, shard int) error {
+	return s.renewLease(ctx, s.shardLeaseName(shard), nil, false)
+}
+
+func (s *Sharder) renewLease(ctx context.Context, name string, labels map[string]string, force bool) error {
+	now := metav1.NewMicroTime(time.Now())
+	lease, err := s.client.Leases(s.namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		_, err = s.client.Leases(s.namespace).Create(ctx, &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: s.namespace,
+				Labels:    labels,
+			},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(s.identity),
+				LeaseDurationSeconds: ptr.To(int32(leaseDuration.Seconds())),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}, metav1.CreateOptions{})
+		return err
+	} else if err != nil {
+		return err
+	}
+
+	holder := ptr.Deref(lease.Spec.HolderIdentity, "")
+	if holder != s.identity {
+		if !force && holder != "" && !expired(lease) {
+			return fmt.Errorf("lease %s is held by %s", name, holder)
+		}
+		lease.Spec.AcquireTime = &now
+		lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+	}
+	lease.Spec.HolderIdentity = ptr.To(s.identity)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(leaseDuration.Seconds()))
+	lease.Spec.RenewTime = &now
+	_, err = s.client.Leases(s.namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+// releaseShard clears the holder of the shard lease if held by this replica,
+// such that the next member can acquire it without waiting for expiry.
+func (s *Sharder) releaseShard(ctx context.Context, shard int) error {
+	lease, err := s.client.Leases(s.namespace).Get(ctx, s.shardLeaseName(shard), metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != s.identity {
+		return nil
+	}
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.RenewTime = nil
+	_, err = s.client.Leases(s.namespace).Update(ctx, lease, metav1.UpdateOptions{})
+	return err
+}
+
+func expired(lease *coordinationv1.Lease) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second).Before(time.Now())
+}
+
+func (s *Sharder) memberLeaseName() string {
+	return fmt.Sprintf("%s-member-%s", s.name, s.identity)
+}
+
+func (s *Sharder) shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-shard-%d", s.name, shard)
+}
+
+func (s *Sharder) heldSince(shard int) (time.Time, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	t, found := s.held[shard]
+	return t, found
+}
+
+func (s *Sharder) heldShards() sets.Set[int] {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	held := sets.New[int]()
+	for shard := range s.held {
+		held.Insert(shard)
+	}
+	return held
+}
+
+func (s *Sharder) setHeld(shard int, renewed time.Time) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.held[shard] = renewed
+}
+
+// update marks the lost shards as not held and notifies the subscribers if
+// anything changed. Gained shards are already marked as held.
+func (s *Sharder) update(gained, lost []int) {
+	s.lock.Lock()
+	for _, shard := range lost {
+		delete(s.held, shard)
+	}
+	handlers := make([]func(), 0, len(s.handlers))
+	for h := range s.handlers {
+		handlers = append(handlers, *h)
+	}
+	held := len(s.held)
+	s.lock.Unlock()
+
+	metrics.RecordShardsHeld(held)
+	if len(gained) == 0 && len(lost) == 0 {
+		return
+	}
+	for _, h := range handlers {
+		h()
+	}
+}
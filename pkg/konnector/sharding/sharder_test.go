@@ -0,0 +1,117 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestAssign(t *testing.T) {
+	const shards = 64
+
+	assign := func(members ...string) map[int]string {
+		owners := map[int]string{}
+		for shard := 0; shard < shards; shard++ {
+			owners[shard] = Assign(shard, members)
+		}
+		return owners
+	}
+
+	require.Equal(t, "", Assign(0, nil))
+
+	before := assign("a", "b", "c")
+	counts := map[string]int{}
+	for _, owner := range before {
+		counts[owner]++
+	}
+	for _, member := range []string{"a", "b", "c"} {
+		require.NotZero(t, counts[member], "member %s got no shard", member)
+	}
+
+	// order of members does not matter
+	require.Equal(t, before, assign("c", "a", "b"))
+
+	// a joining member only takes shards from others
+	joined := assign("a", "b", "c", "d")
+	for shard, owner := range joined {
+		if owner != "d" {
+			require.Equal(t, before[shard], owner, "shard %d moved between remaining members", shard)
+		}
+	}
+
+	// only the shards of a leaving member move
+	left := assign("a", "c")
+	for shard, owner := range before {
+		if owner != "b" {
+			require.Equal(t, owner, left[shard], "shard %d moved between remaining members", shard)
+		}
+	}
+}
+
+func TestShard(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		name := fmt.Sprintf("binding-%d", i)
+		shard := Shard(name, 7)
+		require.GreaterOrEqual(t, shard, 0)
+		require.Less(t, shard, 7)
+		require.Equal(t, shard, Shard(name, 7), "shard must be stable")
+	}
+}
+
+func TestKey(t *testing.T) {
+	binding := func(name, secret string) *kubebindv1alpha1.APIServiceBinding {
+		b := &kubebindv1alpha1.APIServiceBinding{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if secret != "" {
+			b.Spec.Providers = []kubebindv1alpha1.Provider{{
+				Kubeconfig: kubebindv1alpha1.ClusterSecretKeyRef{
+					LocalSecretKeyRef: kubebindv1alpha1.LocalSecretKeyRef{Name: secret, Key: "kubeconfig"},
+					Namespace:         "ace",
+				},
+			}}
+		}
+		return b
+	}
+
+	require.Equal(t, "foo", Key(binding("foo", "")), "a binding without provider is keyed by name")
+	require.Equal(t, Key(binding("foo", "kubeconfig-1")), Key(binding("bar", "kubeconfig-1")), "bindings sharing a kubeconfig secret share a key")
+	require.NotEqual(t, Key(binding("foo", "kubeconfig-1")), Key(binding("foo", "kubeconfig-2")))
+	require.Equal(t, SecretKey("ace", "kubeconfig-1"), Key(binding("foo", "kubeconfig-1")))
+}
+
+func TestOwns(t *testing.T) {
+	var nilSharder *Sharder
+	require.True(t, nilSharder.Owns("foo"), "a nil sharder owns everything")
+
+	s := New(nil, "ns", "kube-bind", "a", 4)
+	require.False(t, s.Owns("foo"))
+	s.setHeld(Shard("foo", 4), time.Now())
+	require.True(t, s.Owns("foo"))
+
+	notified := 0
+	s.Subscribe(context.Background(), func() { notified++ })
+	s.update(nil, []int{Shard("foo", 4)})
+	require.False(t, s.Owns("foo"))
+	require.Equal(t, 1, notified)
+}
@@ -0,0 +1,141 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const defaultDevicePollInterval = 5 * time.Second
+
+// DeviceCodeAuthenticator authenticates through the OAuth2 device
+// authorization grant. The user authenticates in a web browser on any device
+// while the authenticator polls the service provider for the response.
+type DeviceCodeAuthenticator struct {
+	grant  *kubebindv1alpha1.OAuth2DeviceGrant
+	client *http.Client
+
+	authorization *kubebindv1alpha1.OAuth2DeviceAuthorization
+}
+
+func NewDeviceCodeAuthenticator(grant *kubebindv1alpha1.OAuth2DeviceGrant) *DeviceCodeAuthenticator {
+	return &DeviceCodeAuthenticator{
+		grant:  grant,
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// Start requests a device and user code for the given session and cluster.
+// The resources, if any, are preselected to skip the resource picker.
+func (d *DeviceCodeAuthenticator) Start(ctx context.Context, sessionID, clusterID, clusterName, user string, resources []string) (*kubebindv1alpha1.OAuth2DeviceAuthorization, error) {
+	if d.authorization != nil {
+		return nil, fmt.Errorf("already started")
+	}
+
+	values := url.Values{
+		"s": {sessionID},
+		"c": {clusterID},
+		"n": {clusterName},
+		"o": {user},
+		"r": resources,
+	}
+	resp, err := postForm(ctx, d.client, d.grant.DeviceAuthorizationURL, values, "")
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to request device code: %s: %s", resp.Status, strings.TrimSpace(string(resp.Body)))
+	}
+
+	authorization := &kubebindv1alpha1.OAuth2DeviceAuthorization{}
+	if err := json.Unmarshal(resp.Body, authorization); err != nil {
+		return nil, fmt.Errorf("failed to decode device authorization: %w", err)
+	}
+	if authorization.DeviceCode == "" || authorization.UserCode == "" || authorization.VerificationURI == "" {
+		return nil, fmt.Errorf("invalid device authorization: missing device code, user code or verification uri")
+	}
+	d.authorization = authorization
+
+	return authorization, nil
+}
+
+// WaitForResponse polls the service provider until the user has authenticated
+// and the response is returned. Start() must be called prior to this.
+func (d *DeviceCodeAuthenticator) WaitForResponse(ctx context.Context) (runtime.Object, *schema.GroupVersionKind, error) {
+	if d.authorization == nil {
+		return nil, nil, fmt.Errorf("not started")
+	}
+
+	interval := defaultDevicePollInterval
+	if d.authorization.Interval > 0 {
+		interval = time.Duration(d.authorization.Interval) * time.Second
+	}
+	if d.authorization.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(d.authorization.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("error while waiting for response: %w", ctx.Err())
+		case <-time.After(interval):
+		}
+
+		resp, err := postForm(ctx, d.client, d.grant.TokenURL, url.Values{"device_code": {d.authorization.DeviceCode}}, "")
+		if err != nil {
+			// transient network errors must not fail the flow
+			continue
+		}
+
+		if resp.StatusCode == http.StatusOK {
+			response, gvk, err := kubebindCodecs.UniversalDeserializer().Decode(resp.Body, nil, nil)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+			}
+			return response, gvk, nil
+		}
+
+		var tokenErr kubebindv1alpha1.OAuth2DeviceTokenError
+		if err := json.Unmarshal(resp.Body, &tokenErr); err != nil || tokenErr.Error == "" {
+			return nil, nil, fmt.Errorf("unexpected response polling for device authorization: %s", resp.Status)
+		}
+		switch tokenErr.Error {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "access_denied":
+			return nil, nil, fmt.Errorf("authorization denied")
+		case "expired_token":
+			return nil, nil, fmt.Errorf("device code expired, please try again")
+		default:
+			return nil, nil, fmt.Errorf("device authorization failed: %s %s", tokenErr.Error, tokenErr.ErrorDescription)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package authenticator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TokenAuthenticator exchanges a bind token minted by the service provider
+// for a response, without interactive login. It is usable by controllers
+// binding on behalf of the consumer cluster as well.
+type TokenAuthenticator struct {
+	exchange *kubebindv1alpha1.TokenExchange
+	token    string
+	client   *http.Client
+}
+
+func NewTokenAuthenticator(exchange *kubebindv1alpha1.TokenExchange, token string) *TokenAuthenticator {
+	return &TokenAuthenticator{
+		exchange: exchange,
+		token:    strings.TrimSpace(token),
+		client:   &http.Client{Timeout: 2 * time.Minute},
+	}
+}
+
+// Exchange exchanges the token for the response binding the given session and
+// cluster. If resources are given, only those of the token are bound.
+func (t *TokenAuthenticator) Exchange(ctx context.Context, sessionID, clusterID, clusterName, user string, resources []string) (runtime.Object, *schema.GroupVersionKind, error) {
+	values := url.Values{
+		"s": {sessionID},
+		"c": {clusterID},
+		"n": {clusterName},
+		"o": {user},
+		"r": resources,
+	}
+	resp, err := postForm(ctx, t.client, t.exchange.ExchangeURL, values, t.token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to exchange bind token: %s: %s", resp.Status, strings.TrimSpace(string(resp.Body)))
+	}
+
+	response, gvk, err := kubebindCodecs.UniversalDeserializer().Decode(resp.Body, nil, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return response, gvk, nil
+}
+
+type formResponse struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+}
+
+// postForm posts the values to the url, with the bearer token if not empty.
+func postForm(ctx context.Context, client *http.Client, u string, values url.Values, bearer string) (*formResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(values.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &formResponse{StatusCode: resp.StatusCode, Status: resp.Status, Body: body}, nil
+}
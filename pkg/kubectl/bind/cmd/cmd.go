@@ -34,6 +34,10 @@ var bindExampleUses = `
 	# select a kube-bind.appscode.com compatible service from the given URL, e.g. an API service.
 	%[1]s bind https://mangodb.com/exports
 
+	# list the services that can be bound, and bind one without picking it in the browser.
+	%[1]s bind https://mangodb.com/exports --list-resources
+	%[1]s bind https://mangodb.com/exports --resource mangodbs.mangodb.com
+
 	# authenticate and configure the services to bind, but don't actually bind them.
 	%[1]s bind https://mangodb.com/exports --dry-run -o yaml > apiservice-export-requests.yaml
 
@@ -17,6 +17,7 @@ limitations under the License.
 package plugin
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,13 +25,17 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"strings"
 
 	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/kubectl/bind/authenticator"
 	"go.bytebuilders.dev/kube-bind/pkg/version"
 
 	"github.com/blang/semver/v4"
 	"github.com/mdp/qrterminal/v3"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientgoversion "k8s.io/client-go/pkg/version"
 )
 
@@ -88,14 +93,58 @@ func validateProviderVersion(providerVersion string) error {
 	return nil
 }
 
-func (b *BindOptions) authenticate(provider *kubebindv1alpha1.BindingProvider, callback, sessionID, clusterID, clusterName, user string, urlCh chan<- string) error {
-	var oauth2Method *kubebindv1alpha1.OAuth2CodeGrant
+// codeGrant returns the OAuth2 code grant method of the provider, or nil.
+func codeGrant(provider *kubebindv1alpha1.BindingProvider) *kubebindv1alpha1.OAuth2CodeGrant {
+	for _, m := range provider.AuthenticationMethods {
+		if m.Method == "OAuth2CodeGrant" && m.OAuth2CodeGrant != nil {
+			return m.OAuth2CodeGrant
+		}
+	}
+	return nil
+}
+
+// deviceGrant returns the OAuth2 device grant method of the provider, or nil.
+func deviceGrant(provider *kubebindv1alpha1.BindingProvider) *kubebindv1alpha1.OAuth2DeviceGrant {
 	for _, m := range provider.AuthenticationMethods {
-		if m.Method == "OAuth2CodeGrant" {
-			oauth2Method = m.OAuth2CodeGrant
-			break
+		if m.Method == "OAuth2DeviceGrant" && m.OAuth2DeviceGrant != nil {
+			return m.OAuth2DeviceGrant
 		}
 	}
+	return nil
+}
+
+// tokenExchange returns the token method of the provider, or nil.
+func tokenExchange(provider *kubebindv1alpha1.BindingProvider) *kubebindv1alpha1.TokenExchange {
+	for _, m := range provider.AuthenticationMethods {
+		if m.Method == "Token" && m.Token != nil {
+			return m.Token
+		}
+	}
+	return nil
+}
+
+// verifyAuthentication checks that the authentication data of a BindingResponse
+// belongs to the session.
+func verifyAuthentication(auth kubebindv1alpha1.BindingResponseAuthentication, sessionID string) error {
+	var sid string
+	switch {
+	case auth.OAuth2CodeGrant != nil:
+		sid = auth.OAuth2CodeGrant.SessionID
+	case auth.OAuth2DeviceGrant != nil:
+		sid = auth.OAuth2DeviceGrant.SessionID
+	case auth.Token != nil:
+		sid = auth.Token.SessionID
+	default:
+		return errors.New("authentication.oauth2CodeGrant, authentication.oauth2DeviceGrant and authentication.token are nil")
+	}
+	if sid != sessionID {
+		return errors.New("sessionID does not match")
+	}
+	return nil
+}
+
+func (b *BindOptions) authenticate(provider *kubebindv1alpha1.BindingProvider, callback, sessionID, clusterID, clusterName, user string, urlCh chan<- string) error {
+	oauth2Method := codeGrant(provider)
 	if oauth2Method == nil {
 		return errors.New("server does not support OAuth2 code grant flow")
 	}
@@ -120,6 +169,9 @@ func (b *BindOptions) authenticate(provider *kubebindv1alpha1.BindingProvider, c
 	values.Add("c", clusterID)
 	values.Add("n", clusterName)
 	values.Add("o", user)
+	for _, r := range b.Resources {
+		values.Add("r", r)
+	}
 	u.RawQuery = values.Encode()
 
 	fmt.Fprintf(b.Options.ErrOut, "\nTo authenticate, visit in your browser:\n\n\t%s\n", u.String()) // nolint: errcheck
@@ -142,3 +194,36 @@ func (b *BindOptions) authenticate(provider *kubebindv1alpha1.BindingProvider, c
 
 	return nil
 }
+
+func (b *BindOptions) authenticateWithDeviceCode(ctx context.Context, grant *kubebindv1alpha1.OAuth2DeviceGrant, sessionID, clusterID, clusterName, user string, urlCh chan<- string) (runtime.Object, *schema.GroupVersionKind, error) {
+	auth := authenticator.NewDeviceCodeAuthenticator(grant)
+	authorization, err := auth.Start(ctx, sessionID, clusterID, clusterName, user, b.Resources)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fmt.Fprintf(b.Options.ErrOut, "\nTo authenticate, visit in a browser on any device:\n\n\t%s\n\nand enter the code:\n\n\t%s\n\n", authorization.VerificationURI, authorization.UserCode) // nolint: errcheck
+	if urlCh != nil {
+		u := authorization.VerificationURIComplete
+		if u == "" {
+			u = authorization.VerificationURI
+		}
+		urlCh <- u
+	}
+
+	fmt.Fprintf(b.Options.ErrOut, "⏳ Waiting for the authentication to complete...\n") // nolint: errcheck
+	return auth.WaitForResponse(ctx)
+}
+
+func (b *BindOptions) authenticateWithToken(ctx context.Context, provider *kubebindv1alpha1.BindingProvider, sessionID, clusterID, clusterName, user string) (runtime.Object, *schema.GroupVersionKind, error) {
+	exchange := tokenExchange(provider)
+	if exchange == nil {
+		return nil, nil, errors.New("server does not support bind tokens")
+	}
+	token, err := os.ReadFile(b.TokenFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read bind token: %w", err)
+	}
+
+	return authenticator.NewTokenAuthenticator(exchange, string(token)).Exchange(ctx, sessionID, clusterID, clusterName, user, b.Resources)
+}
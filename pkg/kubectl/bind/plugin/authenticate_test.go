@@ -18,6 +18,8 @@ package plugin
 
 import (
 	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 )
 
 func TestValidateVersion(t *testing.T) {
@@ -42,3 +44,35 @@ func TestValidateVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestVerifyAuthentication(t *testing.T) {
+	tests := []struct {
+		name    string
+		auth    kubebindv1alpha1.BindingResponseAuthentication
+		wantErr bool
+	}{
+		{"empty", kubebindv1alpha1.BindingResponseAuthentication{}, true},
+		{"code grant", kubebindv1alpha1.BindingResponseAuthentication{
+			OAuth2CodeGrant: &kubebindv1alpha1.BindingResponseAuthenticationOAuth2CodeGrant{SessionID: "sid"},
+		}, false},
+		{"code grant of other session", kubebindv1alpha1.BindingResponseAuthentication{
+			OAuth2CodeGrant: &kubebindv1alpha1.BindingResponseAuthenticationOAuth2CodeGrant{SessionID: "other"},
+		}, true},
+		{"device grant", kubebindv1alpha1.BindingResponseAuthentication{
+			OAuth2DeviceGrant: &kubebindv1alpha1.BindingResponseAuthenticationOAuth2DeviceGrant{SessionID: "sid"},
+		}, false},
+		{"device grant of other session", kubebindv1alpha1.BindingResponseAuthentication{
+			OAuth2DeviceGrant: &kubebindv1alpha1.BindingResponseAuthenticationOAuth2DeviceGrant{SessionID: "other"},
+		}, true},
+		{"token", kubebindv1alpha1.BindingResponseAuthentication{
+			Token: &kubebindv1alpha1.BindingResponseAuthenticationToken{SessionID: "sid"},
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := verifyAuthentication(tt.auth, "sid"); (err != nil) != tt.wantErr {
+				t.Errorf("verifyAuthentication() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -41,6 +41,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 	kubeclient "k8s.io/client-go/kubernetes"
@@ -67,6 +69,21 @@ type BindOptions struct {
 	// The konnector image to use and override default konnector image
 	KonnectorImageOverride string
 
+	// DeviceCode authenticates with the OAuth2 device authorization grant
+	// instead of a callback to localhost.
+	DeviceCode bool
+
+	// TokenFile is the path of a bind token minted by the service provider,
+	// exchanged for the binding without interactive login.
+	TokenFile string
+
+	// Resources are the resources to bind, of the form <resource>.<group>. If
+	// set, the resource picker of the service provider is skipped.
+	Resources []string
+
+	// ListResources lists the resources of the service provider instead of binding.
+	ListResources bool
+
 	// Runner is runs the command. It can be replaced in tests.
 	Runner func(cmd *exec.Cmd) error
 
@@ -99,6 +116,10 @@ func (b *BindOptions) AddCmdFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVar(&b.SkipKonnector, "skip-konnector", b.SkipKonnector, "Skip the deployment of the konnector")
 	cmd.Flags().BoolVarP(&b.DryRun, "dry-run", "d", b.DryRun, "If true, only print the requests that would be sent to the service provider after authentication, without actually binding.")
 	cmd.Flags().StringVar(&b.KonnectorImageOverride, "konnector-image", b.KonnectorImageOverride, "The konnector image to use")
+	cmd.Flags().BoolVar(&b.DeviceCode, "device-code", b.DeviceCode, "Authenticate by entering a code in a web browser on any device, instead of a browser on this machine. Useful over SSH, in CI or in a pod.")
+	cmd.Flags().StringVar(&b.TokenFile, "token-file", b.TokenFile, "Path of a bind token minted by the service provider, to bind without interactive login.")
+	cmd.Flags().StringSliceVar(&b.Resources, "resource", b.Resources, "Resource to bind, e.g. mangodbs.mangodb.com, instead of picking it in the browser. Can be repeated.")
+	cmd.Flags().BoolVar(&b.ListResources, "list-resources", b.ListResources, "List the resources of the service provider that can be bound, and exit.")
 }
 
 // Complete ensures all fields are initialized.
@@ -131,6 +152,16 @@ func (b *BindOptions) Validate() error {
 		return fmt.Errorf("invalid url %q: %w", b.URL, err)
 	}
 
+	if b.DeviceCode && b.TokenFile != "" {
+		return errors.New("--device-code and --token-file are mutually exclusive")
+	}
+
+	for _, r := range b.Resources {
+		if gr := schema.ParseGroupResource(r); gr.Resource == "" {
+			return fmt.Errorf("invalid resource %q, expected <resource>.<group>", r)
+		}
+	}
+
 	return b.Options.Validate()
 }
 
@@ -157,12 +188,6 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 		return err // should never happen because we test this in Validate()
 	}
 
-	providerClusterName := exportURL.Query().Get("cluster")
-	user := exportURL.Query().Get("user")
-	if user == "" {
-		return fmt.Errorf("missing user in the connect url")
-	}
-
 	provider, err := getProvider(exportURL.String())
 	if err != nil {
 		return fmt.Errorf("failed to fetch authentication url %q: %v", exportURL, err)
@@ -172,6 +197,28 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 		return fmt.Errorf("unsupported binding provider version: %q", provider.APIVersion)
 	}
 
+	if b.ListResources || len(b.Resources) > 0 {
+		if provider.CatalogURL == "" {
+			return errors.New("server does not serve a catalog of its resources")
+		}
+		catalog, err := getCatalog(provider.CatalogURL)
+		if err != nil {
+			return fmt.Errorf("failed to fetch catalog %q: %v", provider.CatalogURL, err)
+		}
+		if b.ListResources {
+			return printCatalog(b.IOStreams.Out, catalog)
+		}
+		if err := validateResources(catalog, b.Resources); err != nil {
+			return err
+		}
+	}
+
+	providerClusterName := exportURL.Query().Get("cluster")
+	user := exportURL.Query().Get("user")
+	if user == "" {
+		return fmt.Errorf("missing user in the connect url")
+	}
+
 	ns, err := kubeClient.CoreV1().Namespaces().Get(ctx, models.KonnectorNamespace, metav1.GetOptions{})
 	if err != nil && !apierrors.IsNotFound(err) {
 		return err
@@ -188,23 +235,41 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 		}
 	}
 
-	auth := authenticator.NewLocalhostCallbackAuthenticator(redirectUrl(exportURL.Host, user, providerClusterName))
-	err = auth.Start()
-	fmt.Fprintf(b.Options.ErrOut, "\n\n")
-	if err != nil {
-		return err
-	}
-
 	sessionID := SessionID()
-	if err := b.authenticate(provider, auth.Endpoint(), sessionID, ClusterID(ns), providerClusterName, user, urlCh); err != nil {
-		return err
-	}
+	var response runtime.Object
+	var gvk *schema.GroupVersionKind
+	if b.TokenFile != "" {
+		response, gvk, err = b.authenticateWithToken(ctx, provider, sessionID, ClusterID(ns), providerClusterName, user)
+		if err != nil {
+			return err
+		}
+	} else if deviceGrant := deviceGrant(provider); deviceGrant != nil && (b.DeviceCode || codeGrant(provider) == nil) {
+		response, gvk, err = b.authenticateWithDeviceCode(ctx, deviceGrant, sessionID, ClusterID(ns), providerClusterName, user, urlCh)
+		if err != nil {
+			return err
+		}
+	} else {
+		if b.DeviceCode {
+			return errors.New("server does not support the OAuth2 device grant flow")
+		}
 
-	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
-	defer cancel()
-	response, gvk, err := auth.WaitForResponse(timeoutCtx)
-	if err != nil {
-		return err
+		auth := authenticator.NewLocalhostCallbackAuthenticator(redirectUrl(exportURL.Host, user, providerClusterName))
+		err = auth.Start()
+		fmt.Fprintf(b.Options.ErrOut, "\n\n")
+		if err != nil {
+			return err
+		}
+
+		if err := b.authenticate(provider, auth.Endpoint(), sessionID, ClusterID(ns), providerClusterName, user, urlCh); err != nil {
+			return err
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+		defer cancel()
+		response, gvk, err = auth.WaitForResponse(timeoutCtx)
+		if err != nil {
+			return err
+		}
 	}
 
 	fmt.Fprintf(b.IOStreams.ErrOut, "🔑 Successfully authenticated to %s\n", exportURL.String()) // nolint: errcheck
@@ -217,11 +282,8 @@ func (b *BindOptions) Run(ctx context.Context, urlCh chan<- string) error {
 	if !ok {
 		return fmt.Errorf("unexpected response type %T", response)
 	}
-	if bindingResponse.Authentication.OAuth2CodeGrant == nil {
-		return fmt.Errorf("unexpected response: authentication.oauth2CodeGrant is nil")
-	}
-	if bindingResponse.Authentication.OAuth2CodeGrant.SessionID != sessionID {
-		return fmt.Errorf("unexpected response: sessionID does not match")
+	if err := verifyAuthentication(bindingResponse.Authentication, sessionID); err != nil {
+		return fmt.Errorf("unexpected response: %w", err)
 	}
 
 	// extract the requests
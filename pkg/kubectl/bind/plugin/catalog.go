@@ -0,0 +1,90 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/cli-runtime/pkg/printers"
+)
+
+func getCatalog(url string) (*kubebindv1alpha1.ExportCatalog, error) {
+	resp, err := http.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	blob, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(blob)))
+	}
+
+	catalog := &kubebindv1alpha1.ExportCatalog{}
+	if err := json.Unmarshal(blob, catalog); err != nil {
+		return nil, err
+	}
+	if catalog.APIVersion != kubebindv1alpha1.GroupVersion || catalog.Kind != "ExportCatalog" {
+		return nil, fmt.Errorf("unsupported catalog %s, kind %s", catalog.APIVersion, catalog.Kind)
+	}
+	return catalog, nil
+}
+
+// validateResources checks that the resources, of the form <resource>.<group>,
+// are in the catalog.
+func validateResources(catalog *kubebindv1alpha1.ExportCatalog, resources []string) error {
+	available := sets.New[string]()
+	for _, r := range catalog.Resources {
+		available.Insert(resourceName(r.GroupResource))
+	}
+	for _, r := range resources {
+		gr := schema.ParseGroupResource(r)
+		if name := resourceName(kubebindv1alpha1.GroupResource{Group: gr.Group, Resource: gr.Resource}); !available.Has(name) {
+			return fmt.Errorf("resource %q is not offered by the service provider, available are: %s", r, strings.Join(sets.List(available), ", "))
+		}
+	}
+	return nil
+}
+
+// printCatalog prints the resources of the catalog as a table.
+func printCatalog(out io.Writer, catalog *kubebindv1alpha1.ExportCatalog) error {
+	w := printers.GetNewTabWriter(out)
+	fmt.Fprintln(w, "NAME\tKIND\tSCOPE\tVERSIONS\tDESCRIPTION") // nolint: errcheck
+	for _, r := range catalog.Resources {
+		description, _, _ := strings.Cut(strings.TrimSpace(r.Description), "\n")
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", resourceName(r.GroupResource), r.Kind, r.Scope, strings.Join(r.Versions, ","), description) // nolint: errcheck
+	}
+	return w.Flush()
+}
+
+func resourceName(gr kubebindv1alpha1.GroupResource) string {
+	if gr.Group == "" {
+		return gr.Resource
+	}
+	return gr.Resource + "." + gr.Group
+}
@@ -0,0 +1,55 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"testing"
+
+	kubebindv1alpha1 "go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateResources(t *testing.T) {
+	catalog := &kubebindv1alpha1.ExportCatalog{
+		Resources: []kubebindv1alpha1.ExportCatalogResource{
+			{GroupResource: kubebindv1alpha1.GroupResource{Group: "mangodb.com", Resource: "mangodbs"}},
+			{GroupResource: kubebindv1alpha1.GroupResource{Group: "", Resource: "configmaps"}},
+		},
+	}
+
+	require.NoError(t, validateResources(catalog, nil))
+	require.NoError(t, validateResources(catalog, []string{"mangodbs.mangodb.com", "configmaps"}))
+	err := validateResources(catalog, []string{"mangodbs.mangodb.com", "foos.example.com"})
+	require.ErrorContains(t, err, `resource "foos.example.com" is not offered by the service provider, available are: configmaps, mangodbs.mangodb.com`)
+}
+
+func TestPrintCatalog(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, printCatalog(&out, &kubebindv1alpha1.ExportCatalog{
+		Resources: []kubebindv1alpha1.ExportCatalogResource{{
+			GroupResource: kubebindv1alpha1.GroupResource{Group: "mangodb.com", Resource: "mangodbs"},
+			Kind:          "MangoDB",
+			Scope:         "Namespaced",
+			Versions:      []string{"v1beta1", "v1"},
+			Description:   "MangoDB is a database.\nIt is managed.",
+		}},
+	}))
+	require.Equal(t, "NAME                   KIND      SCOPE        VERSIONS     DESCRIPTION\n"+
+		"mangodbs.mangodb.com   MangoDB   Namespaced   v1beta1,v1   MangoDB is a database.\n", out.String())
+}
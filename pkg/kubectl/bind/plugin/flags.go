@@ -40,6 +40,10 @@ var (
 	// passOnEnvVars are the flags we DO NOT pass to downstream commands like kubectl-bind-apiservice.
 	LocalFlags = sets.New[string](
 		"d",
+		"device-code",
 		"dry-run",
+		"list-resources",
+		"resource",
+		"token-file",
 	)
 )
@@ -0,0 +1,163 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+	"go.bytebuilders.dev/kube-bind/pkg/kubectl/base"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/component-base/logs"
+	logsv1 "k8s.io/component-base/logs/api/v1"
+)
+
+// DriftOptions are the options for the kubectl-bind-drift command.
+type DriftOptions struct {
+	Options *base.Options
+	Logs    *logs.Options
+
+	// ShowObjects lists the drifted objects below each binding.
+	ShowObjects bool
+
+	name string
+}
+
+// NewDriftOptions returns new DriftOptions.
+func NewDriftOptions(streams genericclioptions.IOStreams) *DriftOptions {
+	return &DriftOptions{
+		Options: base.NewOptions(streams),
+		Logs:    logs.NewOptions(),
+	}
+}
+
+// AddCmdFlags binds fields to cmd's flagset.
+func (d *DriftOptions) AddCmdFlags(cmd *cobra.Command) {
+	d.Options.BindFlags(cmd)
+	logsv1.AddFlags(d.Logs, cmd.Flags())
+
+	cmd.Flags().BoolVar(&d.ShowObjects, "show-objects", d.ShowObjects, "List the drifted objects")
+}
+
+// Complete ensures all fields are initialized.
+func (d *DriftOptions) Complete(args []string) error {
+	if err := d.Options.Complete(); err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		d.name = args[0]
+	}
+	return nil
+}
+
+// Validate validates the DriftOptions are complete and usable.
+func (d *DriftOptions) Validate() error {
+	return d.Options.Validate()
+}
+
+// Run prints the drift reported in the APIServiceBindings.
+func (d *DriftOptions) Run(ctx context.Context) error {
+	config, err := d.Options.ClientConfig.ClientConfig()
+	if err != nil {
+		return err
+	}
+	bindClient, err := bindclient.NewForConfig(config)
+	if err != nil {
+		return err
+	}
+
+	var bindings []v1alpha1.APIServiceBinding
+	if d.name != "" {
+		binding, err := bindClient.KubeBindV1alpha1().APIServiceBindings().Get(ctx, d.name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		bindings = append(bindings, *binding)
+	} else {
+		list, err := bindClient.KubeBindV1alpha1().APIServiceBindings().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		bindings = list.Items
+	}
+	if len(bindings) == 0 {
+		fmt.Fprintln(d.Options.ErrOut, "No APIServiceBindings found.") // nolint: errcheck
+		return nil
+	}
+
+	return printDrift(d.Options.Out, bindings, d.ShowObjects, time.Now())
+}
+
+func printDrift(out io.Writer, bindings []v1alpha1.APIServiceBinding, showObjects bool, now time.Time) error {
+	w := printers.GetNewTabWriter(out)
+	fmt.Fprintln(w, "BINDING\tPROVIDER\tMISSING UPSTREAM\tMISSING DOWNSTREAM\tSPEC MISMATCH\tSTALE FINALIZERS\tLAST CHECK") // nolint: errcheck
+	for _, binding := range bindings {
+		if len(binding.Status.Drift) == 0 {
+			fmt.Fprintf(w, "%s\t<unknown>\t-\t-\t-\t-\t-\n", binding.Name) // nolint: errcheck
+			continue
+		}
+		for _, drift := range binding.Status.Drift {
+			fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%d\t%d\t%s ago\n", // nolint: errcheck
+				binding.Name,
+				drift.ClusterUID,
+				drift.MissingUpstream.Count,
+				drift.MissingDownstream.Count,
+				drift.SpecMismatch.Count,
+				drift.StaleFinalizers.Count,
+				duration.HumanDuration(now.Sub(drift.LastCheckTime.Time)),
+			)
+		}
+	}
+	if err := w.Flush(); err != nil || !showObjects {
+		return err
+	}
+
+	for _, binding := range bindings {
+		for _, drift := range binding.Status.Drift {
+			for _, drifted := range []struct {
+				title string
+				objs  v1alpha1.DriftedObjects
+			}{
+				{"missing upstream", drift.MissingUpstream},
+				{"missing downstream", drift.MissingDownstream},
+				{"spec mismatch", drift.SpecMismatch},
+				{"stale finalizers", drift.StaleFinalizers},
+			} {
+				if drifted.objs.Count == 0 {
+					continue
+				}
+				more := ""
+				if n := int(drifted.objs.Count) - len(drifted.objs.Objects); n > 0 {
+					more = fmt.Sprintf(" and %d more", n)
+				}
+				fmt.Fprintf(out, "\n%s (provider %s) %s: %s%s\n", binding.Name, drift.ClusterUID, drifted.title, strings.Join(drifted.objs.Objects, ", "), more) // nolint: errcheck
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPrintDrift(t *testing.T) {
+	now := time.Now()
+	bindings := []v1alpha1.APIServiceBinding{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "foos.example.com"},
+			Status: v1alpha1.APIServiceBindingStatus{
+				Drift: []v1alpha1.ProviderDrift{{
+					ClusterUID:      "abc",
+					LastCheckTime:   metav1.NewTime(now.Add(-2 * time.Minute)),
+					MissingUpstream: v1alpha1.DriftedObjects{Count: 12, Objects: []string{"default/a", "default/b"}},
+					SpecMismatch:    v1alpha1.DriftedObjects{Count: 1, Objects: []string{"default/c"}},
+				}},
+			},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Name: "bars.example.com"}},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, printDrift(&out, bindings, true, now))
+	require.Equal(t, `BINDING            PROVIDER    MISSING UPSTREAM   MISSING DOWNSTREAM   SPEC MISMATCH   STALE FINALIZERS   LAST CHECK
+foos.example.com   abc         12                 0                    1               0                  2m ago
+bars.example.com   <unknown>   -                  -                    -               -                  -
+
+foos.example.com (provider abc) missing upstream: default/a, default/b and 10 more
+
+foos.example.com (provider abc) spec mismatch: default/c
+`, out.String())
+}
@@ -0,0 +1,183 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// bindTokenIssuer is the issuer of the sessions authenticated by bind tokens.
+const bindTokenIssuer = "kube-bind.appscode.com/bindtoken"
+
+var errInvalidBindToken = errors.New("invalid or expired bind token")
+
+// BindTokens mints the bind tokens consumers exchange for a BindingResponse
+// without interactive login, and verifies them. Minted tokens are recorded as
+// BindToken objects in the service provider cluster, storing only the hash of
+// their secret.
+type BindTokens struct {
+	kubeClient kubernetes.Interface
+	bindClient bindclient.Interface
+}
+
+// NewBindTokens returns bind tokens recorded in the given service provider
+// cluster. Minting is allowed to users of that cluster who may create
+// BindTokens.
+func NewBindTokens(kubeClient kubernetes.Interface, bindClient bindclient.Interface) *BindTokens {
+	return &BindTokens{
+		kubeClient: kubeClient,
+		bindClient: bindClient,
+	}
+}
+
+// Mint records a new bind token and returns it. The token is not stored and
+// cannot be retrieved later.
+func (t *BindTokens) Mint(ctx context.Context, subject string, resources []v1alpha1.GroupResource, expiration time.Duration) (string, *v1alpha1.BindToken, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", nil, err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b[:])
+
+	bt, err := t.bindClient.KubeBindV1alpha1().BindTokens().Create(ctx, &v1alpha1.BindToken{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "bt-",
+		},
+		Spec: v1alpha1.BindTokenSpec{
+			Subject:        subject,
+			Resources:      resources,
+			ExpirationTime: metav1.NewTime(time.Now().Add(expiration)),
+			TokenHash:      hashBindTokenSecret(secret),
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", nil, err
+	}
+
+	return bt.Name + "." + secret, bt, nil
+}
+
+// Verify returns the BindToken of a valid token.
+func (t *BindTokens) Verify(ctx context.Context, token string) (*v1alpha1.BindToken, error) {
+	name, secret, found := strings.Cut(token, ".")
+	if !found || name == "" || secret == "" {
+		return nil, errInvalidBindToken
+	}
+
+	bt, err := t.bindClient.KubeBindV1alpha1().BindTokens().Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil, errInvalidBindToken
+	} else if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(hashBindTokenSecret(secret)), []byte(bt.Spec.TokenHash)) != 1 {
+		return nil, errInvalidBindToken
+	}
+	if !time.Now().Before(bt.Spec.ExpirationTime.Time) {
+		return nil, errInvalidBindToken
+	}
+
+	return bt, nil
+}
+
+// MarkUsed records the use of the token.
+func (t *BindTokens) MarkUsed(ctx context.Context, bt *v1alpha1.BindToken) error {
+	bt = bt.DeepCopy()
+	now := metav1.Now()
+	bt.Status.LastUsedTime = &now
+	_, err := t.bindClient.KubeBindV1alpha1().BindTokens().UpdateStatus(ctx, bt, metav1.UpdateOptions{})
+	return err
+}
+
+// AuthorizeMint checks that the bearer token of the request authenticates a
+// user of the service provider cluster who may create BindTokens.
+func (t *BindTokens) AuthorizeMint(ctx context.Context, r *http.Request) error {
+	token, found := bearerToken(r)
+	if !found {
+		return errors.New("missing bearer token")
+	}
+
+	review, err := t.kubeClient.AuthenticationV1().TokenReviews().Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to review token: %w", err)
+	}
+	if !review.Status.Authenticated {
+		return fmt.Errorf("unauthenticated: %s", review.Status.Error)
+	}
+
+	user := review.Status.User
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for k, v := range user.Extra {
+		extra[k] = authorizationv1.ExtraValue(v)
+	}
+	sar, err := t.kubeClient.AuthorizationV1().SubjectAccessReviews().Create(ctx, &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "create",
+				Group:    v1alpha1.GroupName,
+				Resource: v1alpha1.ResourceBindTokens,
+			},
+			User:   user.Username,
+			Groups: user.Groups,
+			UID:    user.UID,
+			Extra:  extra,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to review access: %w", err)
+	}
+	if !sar.Status.Allowed {
+		return fmt.Errorf("user %q may not create bindtokens", user.Username)
+	}
+
+	return nil
+}
+
+func hashBindTokenSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
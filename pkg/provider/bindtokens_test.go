@@ -0,0 +1,90 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindfake "go.bytebuilders.dev/kube-bind/client/clientset/versioned/fake"
+
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBindTokensVerify(t *testing.T) {
+	bindToken := func(name string, expiration time.Duration) *v1alpha1.BindToken {
+		return &v1alpha1.BindToken{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: v1alpha1.BindTokenSpec{
+				Subject:        "gitops",
+				Resources:      []v1alpha1.GroupResource{{Group: "mangodb.com", Resource: "mangodbs"}},
+				ExpirationTime: metav1.NewTime(time.Now().Add(expiration)),
+				TokenHash:      hashBindTokenSecret("secret"),
+			},
+		}
+	}
+	tokens := NewBindTokens(nil, bindfake.NewSimpleClientset(
+		bindToken("bt-valid", time.Hour),
+		bindToken("bt-expired", -time.Minute),
+	))
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"valid", "bt-valid.secret", false},
+		{"wrong secret", "bt-valid.other", true},
+		{"no secret", "bt-valid", true},
+		{"empty", "", true},
+		{"expired", "bt-expired.secret", true},
+		{"unknown", "bt-unknown.secret", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bt, err := tokens.Verify(context.Background(), tt.token)
+			if tt.wantErr {
+				require.ErrorIs(t, err, errInvalidBindToken)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "gitops", bt.Spec.Subject)
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	for header, want := range map[string]string{
+		"":               "",
+		"Bearer":         "",
+		"Basic abc":      "",
+		"Bearer abc.def": "abc.def",
+		"bearer abc.def": "abc.def",
+	} {
+		r := &http.Request{Header: http.Header{}}
+		if header != "" {
+			r.Header.Set("Authorization", header)
+		}
+		got, found := bearerToken(r)
+		require.Equal(t, want, got, "header %q", header)
+		require.Equal(t, want != "", found, "header %q", header)
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/provider/resources"
+
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apiextensionslisters "k8s.io/apiextensions-apiserver/pkg/client/listers/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/klog/v2"
+)
+
+type crdCatalog struct {
+	scope  v1alpha1.Scope
+	lister apiextensionslisters.CustomResourceDefinitionLister
+}
+
+// NewCRDCatalog returns a catalog of the CRDs labeled with
+// resources.ExportedCRDsLabel. Cluster-scoped CRDs are only listed for the
+// Cluster consumer scope.
+func NewCRDCatalog(scope v1alpha1.Scope, lister apiextensionslisters.CustomResourceDefinitionLister) Catalog {
+	return &crdCatalog{
+		scope:  scope,
+		lister: lister,
+	}
+}
+
+func (c *crdCatalog) Exports(ctx context.Context) ([]*apiextensionsv1.CustomResourceDefinition, error) {
+	labelSelector := labels.Set{
+		resources.ExportedCRDsLabel: "true",
+	}
+	crds, err := c.lister.List(labelSelector.AsSelector())
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(crds, func(i, j int) bool {
+		return crds[i].Name < crds[j].Name
+	})
+	rightScopedCRDs := []*apiextensionsv1.CustomResourceDefinition{}
+	for _, crd := range crds {
+		if c.scope == v1alpha1.ClusterScope || crd.Spec.Scope == apiextensionsv1.NamespaceScoped {
+			rightScopedCRDs = append(rightScopedCRDs, crd)
+		}
+	}
+	return rightScopedCRDs, nil
+}
+
+// exportCatalog returns the ExportCatalog of the exported CRDs. Invalid
+// parameter schemas are logged and left out, such that one misconfigured CRD
+// does not break the catalog of all others.
+func exportCatalog(ctx context.Context, crds []*apiextensionsv1.CustomResourceDefinition) *v1alpha1.ExportCatalog {
+	logger := klog.FromContext(ctx)
+
+	catalog := &v1alpha1.ExportCatalog{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "ExportCatalog",
+		},
+		Resources: []v1alpha1.ExportCatalogResource{},
+	}
+	for _, crd := range crds {
+		resource := v1alpha1.ExportCatalogResource{
+			GroupResource: v1alpha1.GroupResource{Group: crd.Spec.Group, Resource: crd.Spec.Names.Plural},
+			Kind:          crd.Spec.Names.Kind,
+			Scope:         crd.Spec.Scope,
+			Versions:      []string{},
+			Description:   crd.Annotations[v1alpha1.DescriptionAnnotation],
+		}
+		for _, v := range crd.Spec.Versions {
+			if !v.Served {
+				continue
+			}
+			resource.Versions = append(resource.Versions, v.Name)
+			if v.Storage && resource.Description == "" && v.Schema != nil && v.Schema.OpenAPIV3Schema != nil {
+				resource.Description = v.Schema.OpenAPIV3Schema.Description
+			}
+		}
+		if schema, found := crd.Annotations[v1alpha1.ParametersSchemaAnnotation]; found {
+			var parameters apiextensionsv1.JSONSchemaProps
+			if err := json.Unmarshal([]byte(schema), &parameters); err != nil {
+				logger.Error(err, "skipping invalid parameters schema", "annotation", v1alpha1.ParametersSchemaAnnotation, "crd", crd.Name)
+			} else {
+				resource.Parameters = &parameters
+			}
+		}
+		catalog.Resources = append(catalog.Resources, resource)
+	}
+	return catalog
+}
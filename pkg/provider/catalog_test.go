@@ -0,0 +1,85 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestExportCatalog(t *testing.T) {
+	crd := func(annotations map[string]string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			ObjectMeta: metav1.ObjectMeta{Name: "mangodbs.mangodb.com", Annotations: annotations},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "mangodb.com",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{Plural: "mangodbs", Kind: "MangoDB"},
+				Scope: apiextensionsv1.NamespaceScoped,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: false},
+					{Name: "v1beta1", Served: true},
+					{Name: "v1", Served: true, Storage: true, Schema: &apiextensionsv1.CustomResourceValidation{
+						OpenAPIV3Schema: &apiextensionsv1.JSONSchemaProps{Description: "MangoDB is a database."},
+					}},
+				},
+			},
+		}
+	}
+
+	ctx := context.Background()
+	catalog := exportCatalog(ctx, []*apiextensionsv1.CustomResourceDefinition{crd(nil)})
+	require.Equal(t, v1alpha1.SchemeGroupVersion.String(), catalog.APIVersion)
+	require.Equal(t, "ExportCatalog", catalog.Kind)
+	require.Equal(t, []v1alpha1.ExportCatalogResource{{
+		GroupResource: v1alpha1.GroupResource{Group: "mangodb.com", Resource: "mangodbs"},
+		Kind:          "MangoDB",
+		Scope:         apiextensionsv1.NamespaceScoped,
+		Versions:      []string{"v1beta1", "v1"},
+		Description:   "MangoDB is a database.",
+	}}, catalog.Resources)
+
+	catalog = exportCatalog(ctx, []*apiextensionsv1.CustomResourceDefinition{crd(map[string]string{
+		v1alpha1.DescriptionAnnotation:      "Managed MangoDB.",
+		v1alpha1.ParametersSchemaAnnotation: `{"type":"object","properties":{"size":{"type":"string"}}}`,
+	})})
+	require.Equal(t, "Managed MangoDB.", catalog.Resources[0].Description)
+	require.Equal(t, "object", catalog.Resources[0].Parameters.Type)
+	require.Equal(t, "string", catalog.Resources[0].Parameters.Properties["size"].Type)
+
+	// an invalid parameters schema does not hide the resource or break the catalog
+	catalog = exportCatalog(ctx, []*apiextensionsv1.CustomResourceDefinition{crd(map[string]string{
+		v1alpha1.DescriptionAnnotation:      "Managed MangoDB.",
+		v1alpha1.ParametersSchemaAnnotation: "not json",
+	})})
+	require.Len(t, catalog.Resources, 1)
+	require.Equal(t, "Managed MangoDB.", catalog.Resources[0].Description)
+	require.Nil(t, catalog.Resources[0].Parameters)
+}
+
+func TestParseResources(t *testing.T) {
+	require.Equal(t, []v1alpha1.GroupResource{
+		{Group: "mangodb.com", Resource: "mangodbs"},
+		{Group: "", Resource: "configmaps"},
+	}, ParseResources([]string{"mangodbs.mangodb.com", "", "configmaps"}))
+	require.Nil(t, ParseResources(nil))
+}
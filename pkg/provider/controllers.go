@@ -0,0 +1,119 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions"
+	"go.bytebuilders.dev/kube-bind/pkg/provider/controllers/clusterbinding"
+	"go.bytebuilders.dev/kube-bind/pkg/provider/controllers/serviceexport"
+	"go.bytebuilders.dev/kube-bind/pkg/provider/controllers/serviceexportrequest"
+	"go.bytebuilders.dev/kube-bind/pkg/provider/controllers/servicenamespace"
+
+	apiextensionsinformers "k8s.io/apiextensions-apiserver/pkg/client/informers/externalversions"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/rest"
+)
+
+// Controllers reconcile the kube-bind objects of the consumers in the service
+// provider cluster.
+type Controllers struct {
+	ClusterBinding       *clusterbinding.Controller
+	ServiceNamespace     *servicenamespace.Controller
+	ServiceExport        *serviceexport.Controller
+	ServiceExportRequest *serviceexportrequest.Controller
+}
+
+// NewControllers returns the controllers of a service provider backend. The
+// token expiration is the lifetime of the tokens issued when the konnector
+// requests new credentials, and the maximum credential lifetime bounds these
+// renewals for bindings created by earlier versions.
+func NewControllers(
+	config *rest.Config,
+	scope v1alpha1.Scope,
+	isolation v1alpha1.Isolation,
+	tokenExpiration, maxCredentialLifetime time.Duration,
+	kubeInformers kubeinformers.SharedInformerFactory,
+	bindInformers bindinformers.SharedInformerFactory,
+	apiextensionsInformers apiextensionsinformers.SharedInformerFactory,
+) (*Controllers, error) {
+	var c Controllers
+	var err error
+
+	c.ClusterBinding, err = clusterbinding.NewController(
+		config,
+		scope,
+		tokenExpiration,
+		maxCredentialLifetime,
+		bindInformers.KubeBind().V1alpha1().ClusterBindings(),
+		bindInformers.KubeBind().V1alpha1().APIServiceExports(),
+		kubeInformers.Rbac().V1().ClusterRoles(),
+		kubeInformers.Rbac().V1().ClusterRoleBindings(),
+		kubeInformers.Rbac().V1().Roles(),
+		kubeInformers.Rbac().V1().RoleBindings(),
+		kubeInformers.Core().V1().Namespaces(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up ClusterBinding Controller: %v", err)
+	}
+	c.ServiceNamespace, err = servicenamespace.NewController(
+		config,
+		scope,
+		bindInformers.KubeBind().V1alpha1().APIServiceNamespaces(),
+		bindInformers.KubeBind().V1alpha1().ClusterBindings(),
+		bindInformers.KubeBind().V1alpha1().APIServiceExports(),
+		kubeInformers.Core().V1().Namespaces(),
+		kubeInformers.Rbac().V1().Roles(),
+		kubeInformers.Rbac().V1().RoleBindings(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up APIServiceNamespace Controller: %w", err)
+	}
+	c.ServiceExport, err = serviceexport.NewController(
+		config,
+		bindInformers.KubeBind().V1alpha1().APIServiceExports(),
+		apiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up APIServiceExport Controller: %w", err)
+	}
+	c.ServiceExportRequest, err = serviceexportrequest.NewController(
+		config,
+		scope,
+		isolation,
+		bindInformers.KubeBind().V1alpha1().APIServiceExportRequests(),
+		bindInformers.KubeBind().V1alpha1().APIServiceExports(),
+		apiextensionsInformers.Apiextensions().V1().CustomResourceDefinitions(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up ServiceExportRequest Controller: %w", err)
+	}
+
+	return &c, nil
+}
+
+// Start starts the controllers, which stop when ctx.Done() is closed.
+func (c *Controllers) Start(ctx context.Context) {
+	go c.ServiceExport.Start(ctx, 1)
+	go c.ServiceNamespace.Start(ctx, 1)
+	go c.ClusterBinding.Start(ctx, 1)
+	go c.ServiceExportRequest.Start(ctx, 1)
+}
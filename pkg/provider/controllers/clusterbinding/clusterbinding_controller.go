@@ -26,7 +26,9 @@ import (
 	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions/kubebind/v1alpha1"
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
 	"go.bytebuilders.dev/kube-bind/pkg/committer"
+	kuberesources "go.bytebuilders.dev/kube-bind/pkg/provider/resources"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -47,17 +49,19 @@ import (
 )
 
 const (
-	controllerName = "kube-bind-example-backend-clusterbinding"
+	controllerName = "kube-bind-provider-clusterbinding"
 )
 
 // NewController returns a new controller to reconcile ClusterBindings.
 func NewController(
 	config *rest.Config,
 	scope v1alpha1.Scope,
+	tokenExpiration, maxCredentialLifetime time.Duration,
 	clusterBindingInformer bindinformers.ClusterBindingInformer,
 	serviceExportInformer bindinformers.APIServiceExportInformer,
 	clusterRoleInformer rbacinformers.ClusterRoleInformer,
 	clusterRoleBindingInformer rbacinformers.ClusterRoleBindingInformer,
+	roleInformer rbacinformers.RoleInformer,
 	roleBindingInformer rbacinformers.RoleBindingInformer,
 	namespaceInformer kubeinformers.NamespaceInformer,
 ) (*Controller, error) {
@@ -99,6 +103,10 @@ func NewController(
 
 		reconciler: reconciler{
 			scope: scope,
+
+			tokenExpiration:       tokenExpiration,
+			maxCredentialLifetime: maxCredentialLifetime,
+
 			listServiceExports: func(ns string) ([]*v1alpha1.APIServiceExport, error) {
 				return serviceExportInformer.Lister().APIServiceExports(ns).List(labels.Everything())
 			},
@@ -126,6 +134,21 @@ func NewController(
 			getNamespace: func(name string) (*v1.Namespace, error) {
 				return namespaceInformer.Lister().Get(name)
 			},
+			getRole: func(ns, name string) (*rbacv1.Role, error) {
+				return roleInformer.Lister().Roles(ns).Get(name)
+			},
+			listRoles: func(selector labels.Selector) ([]*rbacv1.Role, error) {
+				return roleInformer.Lister().List(selector)
+			},
+			deleteRole: func(ctx context.Context, ns, name string) error {
+				return kubeClient.RbacV1().Roles(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+			createRole: func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error) {
+				return kubeClient.RbacV1().Roles(role.Namespace).Create(ctx, role, metav1.CreateOptions{})
+			},
+			updateRole: func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error) {
+				return kubeClient.RbacV1().Roles(role.Namespace).Update(ctx, role, metav1.UpdateOptions{})
+			},
 			createRoleBinding: func(ctx context.Context, ns string, binding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error) {
 				return kubeClient.RbacV1().RoleBindings(ns).Create(ctx, binding, metav1.CreateOptions{})
 			},
@@ -135,6 +158,36 @@ func NewController(
 			getRoleBinding: func(ns, name string) (*rbacv1.RoleBinding, error) {
 				return roleBindingInformer.Lister().RoleBindings(ns).Get(name)
 			},
+			deleteRoleBinding: func(ctx context.Context, ns, name string) error {
+				return kubeClient.RbacV1().RoleBindings(ns).Delete(ctx, name, metav1.DeleteOptions{})
+			},
+			renewKubeconfig: func(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding, expiration time.Duration) (*authenticationv1.TokenRequest, error) {
+				ns, ref := clusterBinding.Namespace, clusterBinding.Spec.KubeconfigSecretRef
+				secret, err := kubeClient.CoreV1().Secrets(ns).Get(ctx, ref.Name, metav1.GetOptions{})
+				if err != nil {
+					return nil, err
+				}
+				if _, found := secret.Data[ref.Key]; !found {
+					return nil, fmt.Errorf("kubeconfig secret %s/%s is missing %q key", ns, ref.Name, ref.Key)
+				}
+				token, err := kuberesources.RequestServiceAccountToken(ctx, kubeClient, ns, kuberesources.ServiceAccountName, expiration)
+				if err != nil {
+					return nil, err
+				}
+				kubeconfig, err := kuberesources.RenewKubeconfigToken(secret.Data[ref.Key], token.Status.Token)
+				if err != nil {
+					return nil, err
+				}
+				secret = secret.DeepCopy()
+				secret.Data[ref.Key] = kubeconfig
+				if _, err := kubeClient.CoreV1().Secrets(ns).Update(ctx, secret, metav1.UpdateOptions{}); err != nil {
+					return nil, err
+				}
+				if err := kuberesources.DeleteLegacySASecret(ctx, kubeClient, ns, kuberesources.ServiceAccountName); err != nil {
+					return nil, err
+				}
+				return token, nil
+			},
 		},
 
 		commit: committer.NewCommitter[*v1alpha1.ClusterBinding, *v1alpha1.ClusterBindingSpec, *v1alpha1.ClusterBindingStatus](
@@ -0,0 +1,612 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterbinding
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	kuberesources "go.bytebuilders.dev/kube-bind/pkg/provider/resources"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	conditionsapi "kmodules.xyz/client-go/api/v1"
+	"kmodules.xyz/client-go/conditions"
+)
+
+type reconciler struct {
+	scope v1alpha1.Scope
+
+	tokenExpiration       time.Duration
+	maxCredentialLifetime time.Duration
+
+	listServiceExports func(ns string) ([]*v1alpha1.APIServiceExport, error)
+
+	getClusterRole    func(name string) (*rbacv1.ClusterRole, error)
+	createClusterRole func(ctx context.Context, binding *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error)
+	updateClusterRole func(ctx context.Context, binding *rbacv1.ClusterRole) (*rbacv1.ClusterRole, error)
+
+	getClusterRoleBinding    func(name string) (*rbacv1.ClusterRoleBinding, error)
+	createClusterRoleBinding func(ctx context.Context, binding *rbacv1.ClusterRoleBinding) (*rbacv1.ClusterRoleBinding, error)
+	updateClusterRoleBinding func(ctx context.Context, binding *rbacv1.ClusterRoleBinding) (*rbacv1.ClusterRoleBinding, error)
+	deleteClusterRoleBinding func(ctx context.Context, name string) error
+
+	getRole    func(ns, name string) (*rbacv1.Role, error)
+	listRoles  func(selector labels.Selector) ([]*rbacv1.Role, error)
+	createRole func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error)
+	updateRole func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error)
+	deleteRole func(ctx context.Context, ns, name string) error
+
+	getRoleBinding    func(ns, name string) (*rbacv1.RoleBinding, error)
+	createRoleBinding func(ctx context.Context, ns string, binding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
+	updateRoleBinding func(ctx context.Context, ns string, binding *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
+	deleteRoleBinding func(ctx context.Context, ns, name string) error
+
+	getNamespace func(name string) (*corev1.Namespace, error)
+
+	// renewKubeconfig issues a new token with the given lifetime and writes it
+	// into the kubeconfig secret.
+	renewKubeconfig func(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding, expiration time.Duration) (*authenticationv1.TokenRequest, error)
+}
+
+func (r *reconciler) reconcile(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
+	var errs []error
+
+	r.ensureClusterBindingConditions(clusterBinding)
+	if err := r.ensureKubeconfigRenewal(ctx, clusterBinding); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.ensureRBACRoleBinding(ctx, clusterBinding); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.ensureRBACEvents(ctx, clusterBinding); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.ensureRBACIsolatedExports(ctx, clusterBinding); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.ensureRBACConversionWebhooks(ctx, clusterBinding); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.ensureRBACClusterRole(ctx, clusterBinding); err != nil {
+		errs = append(errs, err)
+	}
+	if err := r.ensureRBACClusterRoleBinding(ctx, clusterBinding); err != nil {
+		errs = append(errs, err)
+	}
+
+	conditions.SetSummary(clusterBinding)
+
+	return utilerrors.NewAggregate(errs)
+}
+
+func (r *reconciler) ensureClusterBindingConditions(clusterBinding *v1alpha1.ClusterBinding) {
+	if clusterBinding.Status.LastHeartbeatTime.IsZero() {
+		conditions.MarkFalse(clusterBinding,
+			v1alpha1.ClusterBindingConditionHealthy,
+			"FirstHeartbeatPending",
+			conditionsapi.ConditionSeverityInfo,
+			"Waiting for first heartbeat",
+		)
+	} else if clusterBinding.Status.HeartbeatInterval.Duration == 0 {
+		conditions.MarkFalse(clusterBinding,
+			v1alpha1.ClusterBindingConditionHealthy,
+			"HeartbeatIntervalMissing",
+			conditionsapi.ConditionSeverityInfo,
+			"Waiting for consumer cluster reporting its heartbeat interval",
+		)
+	} else if ago := time.Since(clusterBinding.Status.LastHeartbeatTime.Time); ago > clusterBinding.Status.HeartbeatInterval.Duration*2 {
+		conditions.MarkFalse(clusterBinding,
+			v1alpha1.ClusterBindingConditionHealthy,
+			"HeartbeatTimeout",
+			conditionsapi.ConditionSeverityError,
+			"Heartbeat timeout: expected heartbeat within %s, but last one has been at %s",
+			clusterBinding.Status.HeartbeatInterval.Duration,
+			clusterBinding.Status.LastHeartbeatTime.Time, // do not put "ago" here. It will hotloop.
+		)
+	} else if ago < time.Second*10 {
+		conditions.MarkFalse(clusterBinding,
+			v1alpha1.ClusterBindingConditionHealthy,
+			"HeartbeatTimeDrift",
+			conditionsapi.ConditionSeverityWarning,
+			"Clocks of consumer cluster and service account cluster seem to be off by more than 10s",
+		)
+	} else {
+		conditions.MarkTrue(clusterBinding,
+			v1alpha1.ClusterBindingConditionHealthy,
+		)
+	}
+}
+
+// ensureKubeconfigRenewal issues new credentials when the konnector requests
+// them, and replaces non-expiring credentials of earlier versions. Credentials
+// are not renewed beyond the renewal deadline, such that a leaked kubeconfig
+// cannot keep itself alive forever. After that, the consumer has to bind again.
+//
+// The deadline is read from the spec, which the konnector cannot write.
+// Bindings of earlier versions without a deadline are bounded by their
+// creation time.
+func (r *reconciler) ensureKubeconfigRenewal(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
+	logger := klog.FromContext(ctx)
+
+	status := &clusterBinding.Status
+	now := time.Now()
+	deadline := clusterBinding.CreationTimestamp.Add(r.maxCredentialLifetime)
+	if clusterBinding.Spec.KubeconfigRenewalDeadline != nil {
+		deadline = clusterBinding.Spec.KubeconfigRenewalDeadline.Time
+	}
+	left := deadline.Sub(now)
+	if left < kuberesources.MinTokenExpiration {
+		conditions.MarkFalse(clusterBinding,
+			v1alpha1.ClusterBindingConditionKubeconfigRenewable,
+			"RenewalDeadlineExceeded",
+			conditionsapi.ConditionSeverityError,
+			"Credentials are not renewed beyond %s. Bind again to get new credentials.",
+			deadline,
+		)
+		return nil
+	}
+	conditions.MarkTrue(clusterBinding, v1alpha1.ClusterBindingConditionKubeconfigRenewable)
+
+	if status.KubeconfigRenewalTime != nil {
+		requested := status.KubeconfigRenewalRequestTime
+		if requested == nil || requested.Before(status.KubeconfigRenewalTime) {
+			return nil
+		}
+	}
+
+	expiration := r.tokenExpiration
+	if left < expiration {
+		expiration = left
+	}
+	token, err := r.renewKubeconfig(ctx, clusterBinding, expiration)
+	if errors.IsNotFound(err) {
+		return nil // kubeconfig not written yet
+	} else if err != nil {
+		return fmt.Errorf("failed to renew kubeconfig: %w", err)
+	}
+	logger.Info("Renewed kubeconfig", "expiration", token.Status.ExpirationTimestamp, "deadline", deadline)
+
+	status.KubeconfigExpirationTime = token.Status.ExpirationTimestamp.DeepCopy()
+	status.KubeconfigRenewalTime = &metav1.Time{Time: kuberesources.TokenRenewalTime(token, now)}
+	return nil
+}
+
+func (r *reconciler) ensureRBACClusterRole(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
+	name := "kube-binder-" + clusterBinding.Namespace
+	role, err := r.getClusterRole(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get ClusterRole %s: %w", name, err)
+	}
+
+	ns, err := r.getNamespace(clusterBinding.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get Namespace %s: %w", clusterBinding.Namespace, err)
+	}
+
+	exports, err := r.listServiceExports(clusterBinding.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to list APIServiceExports: %w", err)
+	}
+	expected := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "Namespace",
+					Name:       clusterBinding.Namespace,
+					Controller: ptr.To(true),
+					UID:        ns.UID,
+				},
+			},
+		},
+	}
+	for _, export := range exports {
+		expected.Rules = append(expected.Rules, rbacv1.PolicyRule{
+			APIGroups: []string{export.Spec.Group},
+			Resources: []string{export.Spec.Names.Plural},
+			Verbs:     []string{"get", "list", "watch", "update", "patch", "delete", "create"},
+		})
+	}
+
+	if role == nil {
+		if _, err := r.createClusterRole(ctx, expected); err != nil {
+			return fmt.Errorf("failed to create ClusterRole %s: %w", expected.Name, err)
+		}
+	} else if !reflect.DeepEqual(role.Rules, expected.Rules) {
+		role = role.DeepCopy()
+		role.Rules = expected.Rules
+		if _, err := r.updateClusterRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to create ClusterRole %s: %w", role.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *reconciler) ensureRBACClusterRoleBinding(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
+	name := "kube-binder-" + clusterBinding.Namespace
+	binding, err := r.getClusterRoleBinding(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get ClusterRoleBinding %s: %w", name, err)
+	}
+
+	if r.scope != v1alpha1.ClusterScope {
+		if err := r.deleteClusterRoleBinding(ctx, name); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete ClusterRoleBinding %s: %w", name, err)
+		}
+	}
+
+	ns, err := r.getNamespace(clusterBinding.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get Namespace %s: %w", clusterBinding.Namespace, err)
+	}
+
+	expected := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "Namespace",
+					Name:       clusterBinding.Namespace,
+					Controller: ptr.To(true),
+					UID:        ns.UID,
+				},
+			},
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Namespace: clusterBinding.Namespace,
+				Name:      kuberesources.ServiceAccountName,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     name,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
+	}
+
+	if binding == nil {
+		if _, err := r.createClusterRoleBinding(ctx, expected); err != nil {
+			return fmt.Errorf("failed to create ClusterRoleBinding %s: %w", expected.Name, err)
+		}
+	} else if !reflect.DeepEqual(binding.Subjects, expected.Subjects) {
+		binding = binding.DeepCopy()
+		binding.Subjects = expected.Subjects
+		// roleRef is immutable
+		if _, err := r.updateClusterRoleBinding(ctx, binding); err != nil {
+			return fmt.Errorf("failed to create ClusterRoleBinding %s: %w", expected.Namespace, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *reconciler) ensureRBACRoleBinding(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
+	binding, err := r.getRoleBinding(clusterBinding.Namespace, "kube-binder")
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get RoleBinding \"kube-binder\": %w", err)
+	}
+
+	expected := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: kuberesources.ServiceAccountName,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      kuberesources.ServiceAccountName,
+				Namespace: clusterBinding.Namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     "kube-binder",
+		},
+	}
+
+	if binding == nil {
+		if _, err := r.createRoleBinding(ctx, clusterBinding.Namespace, expected); err != nil {
+			return fmt.Errorf("failed to create RoleBinding %s: %w", expected.Name, err)
+		}
+	} else if !reflect.DeepEqual(binding.Subjects, expected.Subjects) {
+		binding = binding.DeepCopy()
+		binding.Subjects = expected.Subjects
+		// roleRef is immutable
+		if _, err := r.updateRoleBinding(ctx, clusterBinding.Namespace, binding); err != nil {
+			return fmt.Errorf("failed to create RoleBinding %s: %w", expected.Namespace, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureRBACIsolatedExports lets the konnector manage the objects of exports
+// with Namespaced isolation, which are kept in the cluster namespace.
+func (r *reconciler) ensureRBACIsolatedExports(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
+	ns := clusterBinding.Namespace
+
+	exports, err := r.listServiceExports(ns)
+	if err != nil {
+		return fmt.Errorf("failed to list APIServiceExports: %w", err)
+	}
+	expectedRole := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kuberesources.IsolatedExportsRoleName,
+			Namespace: ns,
+		},
+	}
+	for _, export := range exports {
+		if export.Spec.ClusterScopedIsolation != v1alpha1.IsolationNamespaced {
+			continue
+		}
+		expectedRole.Rules = append(expectedRole.Rules, rbacv1.PolicyRule{
+			APIGroups: []string{export.Spec.Group},
+			Resources: []string{export.Spec.Names.Plural, export.Spec.Names.Plural + "/status"},
+			Verbs:     []string{"get", "list", "watch", "update", "patch", "delete", "create"},
+		})
+	}
+
+	role, err := r.getRole(ns, expectedRole.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Role %s/%s: %w", ns, expectedRole.Name, err)
+	}
+	if role == nil {
+		if _, err := r.createRole(ctx, expectedRole); err != nil {
+			return fmt.Errorf("failed to create Role %s/%s: %w", ns, expectedRole.Name, err)
+		}
+	} else if !reflect.DeepEqual(role.Rules, expectedRole.Rules) {
+		role = role.DeepCopy()
+		role.Rules = expectedRole.Rules
+		if _, err := r.updateRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to update Role %s/%s: %w", ns, expectedRole.Name, err)
+		}
+	}
+
+	expected := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kuberesources.IsolatedExportsRoleName,
+			Namespace: ns,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Namespace: ns,
+				Name:      kuberesources.ServiceAccountName,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     kuberesources.IsolatedExportsRoleName,
+		},
+	}
+	binding, err := r.getRoleBinding(ns, expected.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get RoleBinding %s/%s: %w", ns, expected.Name, err)
+	}
+	if binding == nil {
+		if _, err := r.createRoleBinding(ctx, ns, expected); err != nil {
+			return fmt.Errorf("failed to create RoleBinding %s/%s: %w", ns, expected.Name, err)
+		}
+	} else if !reflect.DeepEqual(binding.Subjects, expected.Subjects) {
+		binding = binding.DeepCopy()
+		binding.Subjects = expected.Subjects
+		// roleRef is immutable
+		if _, err := r.updateRoleBinding(ctx, ns, binding); err != nil {
+			return fmt.Errorf("failed to update RoleBinding %s/%s: %w", ns, expected.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// ensureRBACConversionWebhooks lets the konnector call the conversion webhooks
+// of the exported CRDs through the service proxy of the API server. Its own
+// conversion webhook delegates the conversion of the consumer objects to them.
+func (r *reconciler) ensureRBACConversionWebhooks(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
+	ns := clusterBinding.Namespace
+	name := "kube-binder-" + ns
+
+	exports, err := r.listServiceExports(ns)
+	if err != nil {
+		return fmt.Errorf("failed to list APIServiceExports: %w", err)
+	}
+	services := map[string]sets.Set[string]{}
+	for _, export := range exports {
+		conversion := export.Spec.Conversion
+		if conversion == nil || conversion.Strategy != apiextensionsv1.WebhookConverter || conversion.Webhook == nil || conversion.Webhook.ClientConfig == nil || conversion.Webhook.ClientConfig.Service == nil {
+			continue
+		}
+		svc := conversion.Webhook.ClientConfig.Service
+		port := int32(443)
+		if svc.Port != nil {
+			port = *svc.Port
+		}
+		if services[svc.Namespace] == nil {
+			services[svc.Namespace] = sets.New[string]()
+		}
+		services[svc.Namespace].Insert(fmt.Sprintf("https:%s:%d", svc.Name, port))
+	}
+
+	// remove the permissions for webhooks not used anymore
+	selector := labels.SelectorFromSet(labels.Set{kuberesources.ConversionWebhookConsumerLabel: ns})
+	roles, err := r.listRoles(selector)
+	if err != nil {
+		return fmt.Errorf("failed to list conversion webhook Roles: %w", err)
+	}
+	for _, role := range roles {
+		if _, found := services[role.Namespace]; found {
+			continue
+		}
+		if err := r.deleteRoleBinding(ctx, role.Namespace, role.Name); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete RoleBinding %s/%s: %w", role.Namespace, role.Name, err)
+		}
+		if err := r.deleteRole(ctx, role.Namespace, role.Name); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete Role %s/%s: %w", role.Namespace, role.Name, err)
+		}
+	}
+	if len(services) == 0 {
+		return nil
+	}
+
+	namespace, err := r.getNamespace(ns)
+	if err != nil {
+		return fmt.Errorf("failed to get Namespace %s: %w", ns, err)
+	}
+	meta := func(webhookNamespace string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{
+			Name:      name,
+			Namespace: webhookNamespace,
+			Labels: map[string]string{
+				kuberesources.ConversionWebhookConsumerLabel: ns,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "v1",
+					Kind:       "Namespace",
+					Name:       ns,
+					Controller: ptr.To(true),
+					UID:        namespace.UID,
+				},
+			},
+		}
+	}
+
+	for _, webhookNamespace := range sets.List(sets.KeySet(services)) {
+		expectedRole := &rbacv1.Role{
+			ObjectMeta: meta(webhookNamespace),
+			Rules: []rbacv1.PolicyRule{
+				{
+					APIGroups:     []string{""},
+					Resources:     []string{"services/proxy"},
+					ResourceNames: sets.List(services[webhookNamespace]),
+					Verbs:         []string{"create"},
+				},
+			},
+		}
+		role, err := r.getRole(webhookNamespace, name)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get Role %s/%s: %w", webhookNamespace, name, err)
+		}
+		if role == nil {
+			if _, err := r.createRole(ctx, expectedRole); err != nil {
+				return fmt.Errorf("failed to create Role %s/%s: %w", webhookNamespace, name, err)
+			}
+		} else if !reflect.DeepEqual(role.Rules, expectedRole.Rules) {
+			role = role.DeepCopy()
+			role.Rules = expectedRole.Rules
+			if _, err := r.updateRole(ctx, role); err != nil {
+				return fmt.Errorf("failed to update Role %s/%s: %w", webhookNamespace, name, err)
+			}
+		}
+
+		expected := &rbacv1.RoleBinding{
+			ObjectMeta: meta(webhookNamespace),
+			Subjects: []rbacv1.Subject{
+				{
+					Kind:      "ServiceAccount",
+					Namespace: ns,
+					Name:      kuberesources.ServiceAccountName,
+				},
+			},
+			RoleRef: rbacv1.RoleRef{
+				APIGroup: "rbac.authorization.k8s.io",
+				Kind:     "Role",
+				Name:     name,
+			},
+		}
+		binding, err := r.getRoleBinding(webhookNamespace, name)
+		if err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get RoleBinding %s/%s: %w", webhookNamespace, name, err)
+		}
+		if binding == nil {
+			if _, err := r.createRoleBinding(ctx, webhookNamespace, expected); err != nil {
+				return fmt.Errorf("failed to create RoleBinding %s/%s: %w", webhookNamespace, name, err)
+			}
+		} else if !reflect.DeepEqual(binding.Subjects, expected.Subjects) {
+			binding = binding.DeepCopy()
+			binding.Subjects = expected.Subjects
+			// roleRef is immutable
+			if _, err := r.updateRoleBinding(ctx, webhookNamespace, binding); err != nil {
+				return fmt.Errorf("failed to update RoleBinding %s/%s: %w", webhookNamespace, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ensureRBACEvents lets the konnector read the events in the cluster namespace,
+// where the events of cluster-scoped objects are mirrored from.
+func (r *reconciler) ensureRBACEvents(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding) error {
+	ns := clusterBinding.Namespace
+
+	expectedRole := kuberesources.EventsRole(ns)
+	role, err := r.getRole(ns, expectedRole.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get Role %s/%s: %w", ns, expectedRole.Name, err)
+	}
+	if role == nil {
+		if _, err := r.createRole(ctx, expectedRole); err != nil {
+			return fmt.Errorf("failed to create Role %s/%s: %w", ns, expectedRole.Name, err)
+		}
+	} else if !reflect.DeepEqual(role.Rules, expectedRole.Rules) {
+		role = role.DeepCopy()
+		role.Rules = expectedRole.Rules
+		if _, err := r.updateRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to update Role %s/%s: %w", ns, expectedRole.Name, err)
+		}
+	}
+
+	expected := kuberesources.EventsRoleBinding(ns, ns)
+	binding, err := r.getRoleBinding(ns, expected.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get RoleBinding %s/%s: %w", ns, expected.Name, err)
+	}
+	if binding == nil {
+		if _, err := r.createRoleBinding(ctx, ns, expected); err != nil {
+			return fmt.Errorf("failed to create RoleBinding %s/%s: %w", ns, expected.Name, err)
+		}
+	} else if !reflect.DeepEqual(binding.Subjects, expected.Subjects) {
+		binding = binding.DeepCopy()
+		binding.Subjects = expected.Subjects
+		// roleRef is immutable
+		if _, err := r.updateRoleBinding(ctx, ns, binding); err != nil {
+			return fmt.Errorf("failed to update RoleBinding %s/%s: %w", ns, expected.Name, err)
+		}
+	}
+
+	return nil
+}
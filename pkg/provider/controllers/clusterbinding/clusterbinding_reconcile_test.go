@@ -0,0 +1,73 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clusterbinding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"kmodules.xyz/client-go/conditions"
+)
+
+func TestEnsureKubeconfigRenewalDeadline(t *testing.T) {
+	var issued []time.Duration
+	r := &reconciler{
+		tokenExpiration:       time.Hour,
+		maxCredentialLifetime: 24 * time.Hour,
+		renewKubeconfig: func(ctx context.Context, clusterBinding *v1alpha1.ClusterBinding, expiration time.Duration) (*authenticationv1.TokenRequest, error) {
+			issued = append(issued, expiration)
+			return &authenticationv1.TokenRequest{
+				Status: authenticationv1.TokenRequestStatus{ExpirationTimestamp: metav1.NewTime(time.Now().Add(expiration))},
+			}, nil
+		},
+	}
+	ctx := context.Background()
+	now := time.Now()
+
+	// legacy bindings are bounded by their creation time
+	binding := &v1alpha1.ClusterBinding{}
+	binding.CreationTimestamp = metav1.NewTime(now.Add(-time.Hour))
+	require.NoError(t, r.ensureKubeconfigRenewal(ctx, binding))
+	require.Equal(t, []time.Duration{time.Hour}, issued)
+	require.True(t, conditions.IsTrue(binding, v1alpha1.ClusterBindingConditionKubeconfigRenewable))
+
+	binding.CreationTimestamp = metav1.NewTime(now.Add(-24 * time.Hour))
+	binding.Status.KubeconfigRenewalRequestTime = &metav1.Time{Time: now.Add(time.Hour)}
+	require.NoError(t, r.ensureKubeconfigRenewal(ctx, binding))
+	require.Len(t, issued, 1)
+	require.True(t, conditions.IsFalse(binding, v1alpha1.ClusterBindingConditionKubeconfigRenewable))
+
+	// tokens do not outlive the deadline in the spec
+	binding.Spec.KubeconfigRenewalDeadline = &metav1.Time{Time: now.Add(30 * time.Minute)}
+	require.NoError(t, r.ensureKubeconfigRenewal(ctx, binding))
+	require.Len(t, issued, 2)
+	require.InDelta(t, float64(30*time.Minute), float64(issued[1]), float64(time.Minute))
+	require.True(t, conditions.IsTrue(binding, v1alpha1.ClusterBindingConditionKubeconfigRenewable))
+
+	// after the deadline, the consumer has to bind again
+	binding.Spec.KubeconfigRenewalDeadline = &metav1.Time{Time: now.Add(time.Minute)}
+	binding.Status.KubeconfigRenewalRequestTime = &metav1.Time{Time: now.Add(2 * time.Hour)}
+	require.NoError(t, r.ensureKubeconfigRenewal(ctx, binding))
+	require.Len(t, issued, 2)
+	require.True(t, conditions.IsFalse(binding, v1alpha1.ClusterBindingConditionKubeconfigRenewable))
+}
@@ -43,7 +43,7 @@ import (
 )
 
 const (
-	controllerName = "kube-bind-example-backend-serviceexport"
+	controllerName = "kube-bind-provider-serviceexport"
 )
 
 // NewController returns a new controller to reconcile ServiceExports.
@@ -81,6 +81,11 @@ func (r *reconciler) ensureSchema(ctx context.Context, export *kubebindv1alpha1.
 		// both exist, update APIServiceExport
 		logger.V(1).Info("Updating APIServiceExport")
 		export.Spec.APIServiceExportCRDSpec = *expected
+		if expected.Scope == apiextensionsv1.NamespaceScoped {
+			export.Spec.ClusterScopedIsolation = ""
+		} else if export.Spec.ClusterScopedIsolation == "" || crd.Spec.Scope == apiextensionsv1.NamespaceScoped {
+			export.Spec.ClusterScopedIsolation = kubebindhelpers.ClusterScopedIsolation(crd, export.Spec.ClusterScopedIsolation)
+		}
 		if export.Annotations == nil {
 			export.Annotations = map[string]string{}
 		}
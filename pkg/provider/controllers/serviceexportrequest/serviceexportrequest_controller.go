@@ -44,7 +44,7 @@ import (
 )
 
 const (
-	controllerName = "kube-bind-example-backend-serviceexportrequest"
+	controllerName = "kube-bind-provider-serviceexportrequest"
 )
 
 // NewController returns a new controller to reconcile APIServiceExportRequests by
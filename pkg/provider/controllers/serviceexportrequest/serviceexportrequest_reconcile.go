@@ -114,7 +114,7 @@ func (r *reconciler) ensureExports(ctx context.Context, req *v1alpha1.APIService
 				},
 			}
 			if exportSpec.Scope == apiextensionsv1.ClusterScoped {
-				export.Spec.ClusterScopedIsolation = r.clusterScopedIsolation
+				export.Spec.ClusterScopedIsolation = helpers.ClusterScopedIsolation(crd, r.clusterScopedIsolation)
 			}
 
 			logger.V(1).Info("Creating APIServiceExport", "name", export.Name, "namespace", export.Namespace)
@@ -48,7 +48,7 @@ import (
 )
 
 const (
-	controllerName = "kube-bind-example-backend-servicenamespace"
+	controllerName = "kube-bind-provider-servicenamespace"
 )
 
 // NewController returns a new controller for ServiceNamespaces.
@@ -115,6 +115,16 @@ func NewController(
 				return kubeClient.CoreV1().Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
 			},
 
+			getRole: func(ns, name string) (*rbacv1.Role, error) {
+				return roleInformer.Lister().Roles(ns).Get(name)
+			},
+			createRole: func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error) {
+				return kubeClient.RbacV1().Roles(role.Namespace).Create(ctx, role, metav1.CreateOptions{})
+			},
+			updateRole: func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error) {
+				return kubeClient.RbacV1().Roles(role.Namespace).Update(ctx, role, metav1.UpdateOptions{})
+			},
+
 			getRoleBinding: func(ns, name string) (*rbacv1.RoleBinding, error) {
 				return roleBindingInformer.Lister().RoleBindings(ns).Get(name)
 			},
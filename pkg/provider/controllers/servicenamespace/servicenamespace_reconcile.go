@@ -22,7 +22,7 @@ import (
 	"reflect"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
-	kuberesources "go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
+	kuberesources "go.bytebuilders.dev/kube-bind/pkg/provider/resources"
 
 	corev1 "k8s.io/api/core/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
@@ -37,6 +37,10 @@ type reconciler struct {
 	createNamespace func(ctx context.Context, ns *corev1.Namespace) (*corev1.Namespace, error)
 	deleteNamespace func(ctx context.Context, name string) error
 
+	getRole    func(ns, name string) (*rbacv1.Role, error)
+	createRole func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error)
+	updateRole func(ctx context.Context, role *rbacv1.Role) (*rbacv1.Role, error)
+
 	getRoleBinding    func(ns, name string) (*rbacv1.RoleBinding, error)
 	createRoleBinding func(ctx context.Context, crb *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
 	updateRoleBinding func(ctx context.Context, cr *rbacv1.RoleBinding) (*rbacv1.RoleBinding, error)
@@ -69,6 +73,10 @@ func (c *reconciler) reconcile(ctx context.Context, sns *v1alpha1.APIServiceName
 		}
 	}
 
+	if err := c.ensureRBACEvents(ctx, nsName, sns); err != nil {
+		return fmt.Errorf("failed to ensure RBAC for events: %w", err)
+	}
+
 	if sns.Status.Namespace != nsName {
 		sns.Status.Namespace = nsName
 	}
@@ -117,3 +125,44 @@ func (c *reconciler) ensureRBACRoleBinding(ctx context.Context, ns string, sns *
 
 	return nil
 }
+
+// ensureRBACEvents lets the konnector read the events in the service namespace
+// in all scopes. Events are not granted cluster-wide.
+func (c *reconciler) ensureRBACEvents(ctx context.Context, ns string, sns *v1alpha1.APIServiceNamespace) error {
+	expectedRole := kuberesources.EventsRole(ns)
+	role, err := c.getRole(ns, expectedRole.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get role %s/%s: %w", ns, expectedRole.Name, err)
+	}
+	if role == nil {
+		if _, err := c.createRole(ctx, expectedRole); err != nil {
+			return fmt.Errorf("failed to create role %s/%s: %w", ns, expectedRole.Name, err)
+		}
+	} else if !reflect.DeepEqual(role.Rules, expectedRole.Rules) {
+		role = role.DeepCopy()
+		role.Rules = expectedRole.Rules
+		if _, err := c.updateRole(ctx, role); err != nil {
+			return fmt.Errorf("failed to update role %s/%s: %w", ns, expectedRole.Name, err)
+		}
+	}
+
+	expected := kuberesources.EventsRoleBinding(ns, sns.Namespace)
+	binding, err := c.getRoleBinding(ns, expected.Name)
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get role binding %s/%s: %w", ns, expected.Name, err)
+	}
+	if binding == nil {
+		if _, err := c.createRoleBinding(ctx, expected); err != nil {
+			return fmt.Errorf("failed to create role binding %s/%s: %w", ns, expected.Name, err)
+		}
+	} else if !reflect.DeepEqual(binding.Subjects, expected.Subjects) {
+		binding = binding.DeepCopy()
+		binding.Subjects = expected.Subjects
+		// roleRef is immutable
+		if _, err := c.updateRoleBinding(ctx, binding); err != nil {
+			return fmt.Errorf("failed to update role binding %s/%s: %w", ns, expected.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,261 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	deviceCodeLifetime = 10 * time.Minute
+	devicePollInterval = 5 * time.Second
+
+	// userCodeAlphabet avoids vowels and ambiguous characters, see RFC 8628 section 6.1.
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+
+	// maxPendingDeviceAuthorizations bounds the memory used by flows started
+	// through the unauthenticated device authorization endpoint.
+	maxPendingDeviceAuthorizations = 1000
+	// maxDeviceIDLength and maxDeviceResources bound the size of a flow.
+	maxDeviceIDLength  = 256
+	maxDeviceResources = 100
+)
+
+// errTooManyDeviceAuthorizations is returned when no further flows can be
+// started until pending ones complete or expire.
+var errTooManyDeviceAuthorizations = errors.New("too many pending device authorizations, try again later")
+
+// deviceAuthorization is a pending flow of the OAuth2 device authorization grant.
+type deviceAuthorization struct {
+	deviceCode string
+	userCode   string
+	sessionID  string
+	clusterID  string
+	// resources are the resources preselected by kubectl-bind, passed on to
+	// the authenticator.
+	resources []string
+
+	// redirectURL is the redirect url of the session authenticated for this
+	// flow. Only sessions with this url complete the flow.
+	redirectURL string
+
+	expiresAt time.Time
+	lastPoll  time.Time
+	response  []byte
+}
+
+// deviceAuthorizations keeps the pending device authorizations in memory. They
+// are short-lived, hence a restart of the backend only fails the flows in
+// progress. As they are not shared between processes, the device flow requires
+// a single backend replica, or sticky sessions routing the requests of a flow
+// to the same replica.
+type deviceAuthorizations struct {
+	lock         sync.Mutex
+	byDeviceCode map[string]*deviceAuthorization
+	byUserCode   map[string]*deviceAuthorization
+	bySessionID  map[string]*deviceAuthorization
+}
+
+func newDeviceAuthorizations() *deviceAuthorizations {
+	return &deviceAuthorizations{
+		byDeviceCode: map[string]*deviceAuthorization{},
+		byUserCode:   map[string]*deviceAuthorization{},
+		bySessionID:  map[string]*deviceAuthorization{},
+	}
+}
+
+// start starts a flow for the given kubectl-bind session. The user completes it
+// at the verification uri below baseURL.
+func (d *deviceAuthorizations) start(sessionID, clusterID string, resources []string, baseURL string, now time.Time) (*deviceAuthorization, error) {
+	if sessionID == "" || clusterID == "" {
+		return nil, errors.New("missing session id or cluster id")
+	}
+	if len(sessionID) > maxDeviceIDLength || len(clusterID) > maxDeviceIDLength {
+		return nil, errors.New("session id or cluster id too long")
+	}
+	if len(resources) > maxDeviceResources {
+		return nil, errors.New("too many resources")
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.expire(now)
+
+	if _, found := d.bySessionID[sessionID]; found {
+		return nil, errors.New("session id is already in use")
+	}
+	if len(d.byDeviceCode) >= maxPendingDeviceAuthorizations {
+		return nil, errTooManyDeviceAuthorizations
+	}
+
+	deviceCode, err := randomDeviceCode()
+	if err != nil {
+		return nil, err
+	}
+	var userCode string
+	for {
+		if userCode, err = randomUserCode(); err != nil {
+			return nil, err
+		}
+		if _, found := d.byUserCode[userCode]; !found {
+			break
+		}
+	}
+
+	a := &deviceAuthorization{
+		deviceCode:  deviceCode,
+		userCode:    userCode,
+		sessionID:   sessionID,
+		clusterID:   clusterID,
+		resources:   resources,
+		redirectURL: verificationURIComplete(baseURL, userCode),
+		expiresAt:   now.Add(deviceCodeLifetime),
+	}
+	d.byDeviceCode[a.deviceCode] = a
+	d.byUserCode[a.userCode] = a
+	d.bySessionID[a.sessionID] = a
+	return a, nil
+}
+
+// lookup returns a copy of the pending flow with the given user code as typed
+// by the user.
+func (d *deviceAuthorizations) lookup(userCode string, now time.Time) (deviceAuthorization, bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.expire(now)
+
+	a, found := d.byUserCode[normalizeUserCode(userCode)]
+	if !found || a.response != nil {
+		return deviceAuthorization{}, false
+	}
+	return *a, true
+}
+
+// pending returns true if the session authenticates a pending flow.
+func (d *deviceAuthorizations) pending(sessionID, redirectURL string, now time.Time) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.expire(now)
+
+	a, found := d.bySessionID[sessionID]
+	return found && a.redirectURL == redirectURL && a.response == nil
+}
+
+// complete stores the BindingResponse of the session for the next poll. It
+// returns false if the session does not authenticate a pending flow.
+func (d *deviceAuthorizations) complete(sessionID, redirectURL string, response []byte, now time.Time) bool {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.expire(now)
+
+	a, found := d.bySessionID[sessionID]
+	if !found || a.redirectURL != redirectURL || a.response != nil {
+		return false
+	}
+	a.response = response
+	return true
+}
+
+// poll returns the BindingResponse of the flow with the given device code, or
+// the RFC 8628 error code if there is none.
+func (d *deviceAuthorizations) poll(deviceCode string, now time.Time) ([]byte, string) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.expire(now)
+
+	a, found := d.byDeviceCode[deviceCode]
+	if !found {
+		return nil, "expired_token"
+	}
+	if a.response != nil {
+		d.delete(a)
+		return a.response, ""
+	}
+
+	// be tolerant about timer jitter of the client
+	tooFast := !a.lastPoll.IsZero() && now.Sub(a.lastPoll) < devicePollInterval/2
+	a.lastPoll = now
+	if tooFast {
+		return nil, "slow_down"
+	}
+	return nil, "authorization_pending"
+}
+
+func (d *deviceAuthorizations) expire(now time.Time) {
+	for _, a := range d.byDeviceCode {
+		if !now.Before(a.expiresAt) {
+			d.delete(a)
+		}
+	}
+}
+
+func (d *deviceAuthorizations) delete(a *deviceAuthorization) {
+	delete(d.byDeviceCode, a.deviceCode)
+	delete(d.byUserCode, a.userCode)
+	delete(d.bySessionID, a.sessionID)
+}
+
+func verificationURI(baseURL string) string {
+	return baseURL + "/device"
+}
+
+func verificationURIComplete(baseURL, userCode string) string {
+	return verificationURI(baseURL) + "?" + url.Values{"user_code": {formatUserCode(userCode)}}.Encode()
+}
+
+func randomDeviceCode() (string, error) {
+	var b [32]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b[:]), nil
+}
+
+func randomUserCode() (string, error) {
+	var sb strings.Builder
+	for i := 0; i < userCodeLength; i++ {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(userCodeAlphabet))))
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return sb.String(), nil
+}
+
+// formatUserCode splits the user code in two halves for readability.
+func formatUserCode(userCode string) string {
+	return userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:]
+}
+
+// normalizeUserCode strips what users add or change when typing a user code.
+func normalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(userCode))
+}
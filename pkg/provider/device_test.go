@@ -0,0 +1,101 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDeviceAuthorizations(t *testing.T) {
+	now := time.Now()
+	d := newDeviceAuthorizations()
+
+	_, err := d.start("", "cluster", nil, "https://backend", now)
+	require.Error(t, err, "session id is required")
+
+	a, err := d.start("sid", "cluster", []string{"mangodbs.mangodb.com"}, "https://backend", now)
+	require.NoError(t, err)
+	require.Len(t, a.userCode, userCodeLength)
+	require.Equal(t, "https://backend/device?user_code="+formatUserCode(a.userCode), a.redirectURL)
+
+	_, err = d.start("sid", "cluster", nil, "https://backend", now)
+	require.Error(t, err, "session id must be unique")
+
+	// the user types the code sloppily
+	typed := " " + formatUserCode(a.userCode)[:5] + " " + formatUserCode(a.userCode)[5:]
+	found, ok := d.lookup(strings.ToLower(typed), now)
+	require.True(t, ok)
+	require.Equal(t, "sid", found.sessionID)
+	require.Equal(t, "cluster", found.clusterID)
+	require.Equal(t, []string{"mangodbs.mangodb.com"}, found.resources)
+	require.Equal(t, a.redirectURL, found.redirectURL)
+
+	_, errCode := d.poll(a.deviceCode, now)
+	require.Equal(t, "authorization_pending", errCode)
+	_, errCode = d.poll(a.deviceCode, now.Add(time.Second))
+	require.Equal(t, "slow_down", errCode)
+	_, errCode = d.poll("unknown", now)
+	require.Equal(t, "expired_token", errCode)
+
+	// a session of another flow with the same id does not complete it
+	require.False(t, d.pending("sid", "http://localhost:1234/callback", now))
+	require.False(t, d.complete("sid", "http://localhost:1234/callback", []byte("wrong"), now))
+
+	require.True(t, d.pending("sid", a.redirectURL, now))
+	require.True(t, d.complete("sid", a.redirectURL, []byte("response"), now))
+	require.False(t, d.complete("sid", a.redirectURL, []byte("again"), now))
+
+	response, errCode := d.poll(a.deviceCode, now.Add(devicePollInterval))
+	require.Empty(t, errCode)
+	require.Equal(t, []byte("response"), response)
+	_, errCode = d.poll(a.deviceCode, now.Add(2*devicePollInterval))
+	require.Equal(t, "expired_token", errCode, "the response is returned only once")
+
+	// expiry
+	b, err := d.start("sid2", "cluster", nil, "https://backend", now)
+	require.NoError(t, err)
+	_, ok = d.lookup(b.userCode, now.Add(deviceCodeLifetime))
+	require.False(t, ok)
+	_, errCode = d.poll(b.deviceCode, now.Add(deviceCodeLifetime))
+	require.Equal(t, "expired_token", errCode)
+}
+
+func TestDeviceAuthorizationsLimits(t *testing.T) {
+	now := time.Now()
+	d := newDeviceAuthorizations()
+
+	_, err := d.start(strings.Repeat("s", maxDeviceIDLength+1), "cluster", nil, "https://backend", now)
+	require.Error(t, err, "session id is too long")
+	_, err = d.start("sid", "cluster", make([]string, maxDeviceResources+1), "https://backend", now)
+	require.Error(t, err, "too many resources")
+
+	for i := 0; i < maxPendingDeviceAuthorizations; i++ {
+		_, err := d.start(fmt.Sprintf("sid-%d", i), "cluster", nil, "https://backend", now)
+		require.NoError(t, err)
+	}
+	_, err = d.start("sid", "cluster", nil, "https://backend", now)
+	require.ErrorIs(t, err, errTooManyDeviceAuthorizations)
+
+	// expired flows make room again
+	_, err = d.start("sid", "cluster", nil, "https://backend", now.Add(deviceCodeLifetime))
+	require.NoError(t, err)
+}
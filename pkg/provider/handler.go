@@ -0,0 +1,694 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+	"go.bytebuilders.dev/kube-bind/pkg/provider/template"
+	bindversion "go.bytebuilders.dev/kube-bind/pkg/version"
+
+	"github.com/gorilla/mux"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	componentbaseversion "k8s.io/component-base/version"
+	"k8s.io/klog/v2"
+)
+
+var (
+	resourcesTemplate = htmltemplate.Must(htmltemplate.New("resource").Parse(mustRead(template.Files.ReadFile, "resources.gohtml")))
+	deviceTemplate    = htmltemplate.Must(htmltemplate.New("device").Parse(mustRead(template.Files.ReadFile, "device.gohtml")))
+)
+
+// See https://developers.google.com/web/fundamentals/performance/optimizing-content-efficiency/http-caching?hl=en
+var noCacheHeaders = map[string]string{
+	"Expires":         time.Unix(0, 0).Format(time.RFC1123),
+	"Cache-Control":   "no-cache, no-store, must-revalidate, max-age=0",
+	"X-Accel-Expires": "0", // https://www.nginx.com/resources/wiki/start/topics/examples/x-accel/
+}
+
+type handler struct {
+	authenticator Authenticator
+	catalog       Catalog
+	provisioner   Provisioner
+	tokens        *BindTokens
+	hooks         []PostBindHook
+	devices       *deviceAuthorizations
+
+	testingAutoSelect string
+}
+
+// NewHandler returns the HTTP handler serving the kubectl-bind flow. If tokens
+// is not nil, consumers can also bind with bind tokens.
+func NewHandler(
+	authenticator Authenticator,
+	catalog Catalog,
+	provisioner Provisioner,
+	tokens *BindTokens,
+	hooks []PostBindHook,
+	testingAutoSelect string,
+) (*handler, error) {
+	return &handler{
+		authenticator:     authenticator,
+		catalog:           catalog,
+		provisioner:       provisioner,
+		tokens:            tokens,
+		hooks:             hooks,
+		devices:           newDeviceAuthorizations(),
+		testingAutoSelect: testingAutoSelect,
+	}, nil
+}
+
+func (h *handler) AddRoutes(mux *mux.Router) {
+	mux.HandleFunc("/export", h.handleServiceExport).Methods("GET")
+	mux.HandleFunc("/catalog", h.handleCatalog).Methods("GET")
+	mux.HandleFunc("/resources", h.handleResources).Methods("GET")
+	mux.HandleFunc("/bind", h.handleBind).Methods("GET")
+	mux.HandleFunc("/device", h.handleDevice).Methods("GET")
+	mux.HandleFunc("/device/authorize", h.handleDeviceAuthorize).Methods("POST")
+	mux.HandleFunc("/device/token", h.handleDeviceToken).Methods("POST")
+	if h.tokens != nil {
+		mux.HandleFunc("/token", h.handleBindTokenExchange).Methods("POST")
+		mux.HandleFunc("/admin/bindtokens", h.handleMintBindToken).Methods("POST")
+	}
+	h.authenticator.AddRoutes(mux)
+}
+
+func (h *handler) handleServiceExport(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	// the device grant authenticates through the code grant in the browser of
+	// any device, hence it is offered whenever the code grant is.
+	methods := h.authenticator.AuthenticationMethods(r)
+	if codeGrant(methods) != nil {
+		methods = append(methods, v1alpha1.AuthenticationMethod{
+			Method: "OAuth2DeviceGrant",
+			OAuth2DeviceGrant: &v1alpha1.OAuth2DeviceGrant{
+				DeviceAuthorizationURL: baseURL(r) + "/device/authorize",
+				TokenURL:               baseURL(r) + "/device/token",
+			},
+		})
+	}
+
+	if h.tokens != nil {
+		methods = append(methods, v1alpha1.AuthenticationMethod{
+			Method: "Token",
+			Token: &v1alpha1.TokenExchange{
+				ExchangeURL: baseURL(r) + "/token",
+			},
+		})
+	}
+
+	ver := bindversion.BinaryVersion(componentbaseversion.Get().GitVersion)
+	provider := &v1alpha1.BindingProvider{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.GroupVersion,
+			Kind:       "BindingProvider",
+		},
+		Version:               ver,
+		AuthenticationMethods: methods,
+		CatalogURL:            baseURL(r) + "/catalog",
+	}
+
+	bs, err := json.Marshal(provider)
+	if err != nil {
+		logger.Error(err, "failed to marshal provider")
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs) // nolint:errcheck
+}
+
+// prepareNoCache prepares headers for preventing browser caching.
+func prepareNoCache(w http.ResponseWriter) {
+	// Set NoCache headers
+	for k, v := range noCacheHeaders {
+		w.Header().Set(k, v)
+	}
+}
+
+// handleCatalog serves the ExportCatalog of the resources that can be bound.
+func (h *handler) handleCatalog(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	crds, err := h.catalog.Exports(r.Context())
+	if err != nil {
+		logger.Error(err, "failed to list exports")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	catalog := exportCatalog(klog.NewContext(r.Context(), logger), crds)
+
+	bs, err := json.Marshal(catalog)
+	if err != nil {
+		logger.Error(err, "failed to marshal export catalog")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs) // nolint:errcheck
+}
+
+func (h *handler) handleResources(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	if h.testingAutoSelect != "" {
+		parts := strings.SplitN(h.testingAutoSelect, ".", 2)
+		http.Redirect(w, r, "/resources/"+parts[0]+"/"+parts[1], http.StatusFound)
+		return
+	}
+
+	// skip the picker if kubectl-bind preselected the resources
+	if session, err := h.authenticator.Session(r); err == nil && len(session.Resources) > 0 {
+		http.Redirect(w, r, "/bind?s="+url.QueryEscape(session.SessionID), http.StatusFound)
+		return
+	}
+
+	crds, err := h.catalog.Exports(r.Context())
+	if err != nil {
+		logger.Error(err, "failed to list exports")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	bs := bytes.Buffer{}
+	if err := resourcesTemplate.Execute(&bs, struct {
+		SessionID string
+		CRDs      []*apiextensionsv1.CustomResourceDefinition
+	}{
+		SessionID: r.URL.Query().Get("s"),
+		CRDs:      crds,
+	}); err != nil {
+		logger.Error(err, "failed to execute template")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Write(bs.Bytes()) // nolint:errcheck
+}
+
+func (h *handler) handleBind(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	session, err := h.authenticator.Session(r)
+	if err != nil {
+		logger.Error(err, "failed to authenticate")
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	resources := []v1alpha1.GroupResource{{Group: r.URL.Query().Get("group"), Resource: r.URL.Query().Get("resource")}}
+	if resources[0].Resource == "" && len(session.Resources) > 0 {
+		resources = session.Resources
+		if err := h.exported(r.Context(), resources); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	kfg, requests, err := h.bind(r.Context(), session, resources)
+	if err != nil {
+		logger.Error(err, "failed to bind")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	// callback response
+	authentication := v1alpha1.BindingResponseAuthentication{
+		OAuth2CodeGrant: &v1alpha1.BindingResponseAuthenticationOAuth2CodeGrant{
+			SessionID: session.SessionID,
+			ID:        session.Issuer + "/" + session.Subject,
+		},
+	}
+	device := h.devices.pending(session.SessionID, session.RedirectURL, time.Now())
+	if device {
+		authentication = v1alpha1.BindingResponseAuthentication{
+			OAuth2DeviceGrant: &v1alpha1.BindingResponseAuthenticationOAuth2DeviceGrant{
+				SessionID: session.SessionID,
+				ID:        session.Issuer + "/" + session.Subject,
+			},
+		}
+	}
+	response := v1alpha1.BindingResponse{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "BindingResponse",
+		},
+		Authentication: authentication,
+		Kubeconfig:     kfg,
+		Requests:       requests,
+	}
+	payload, err := json.Marshal(&response)
+	if err != nil {
+		logger.Error(err, "failed to marshal auth response")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	if device {
+		// kubectl-bind picks up the response with the next poll
+		if !h.devices.complete(session.SessionID, session.RedirectURL, payload, time.Now()) {
+			http.Error(w, "device code expired", http.StatusBadRequest)
+			return
+		}
+		h.renderDevicePage(w, r, deviceTemplateData{Done: true})
+		return
+	}
+
+	encoded := base64.URLEncoding.EncodeToString(payload)
+
+	parsedAuthURL, err := url.Parse(session.RedirectURL)
+	if err != nil {
+		logger.Error(err, "failed to parse redirect url")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	values := parsedAuthURL.Query()
+	values.Add("response", encoded)
+
+	parsedAuthURL.RawQuery = values.Encode()
+
+	logger.V(1).Info("redirecting to auth callback", "url", session.RedirectURL+"?response=<redacted>")
+	http.Redirect(w, r, parsedAuthURL.String(), http.StatusFound)
+}
+
+// bind provisions the consumer of the session for the resources, and returns
+// the kubeconfig and the requests of the BindingResponse.
+func (h *handler) bind(ctx context.Context, session *Session, resources []v1alpha1.GroupResource) ([]byte, []runtime.RawExtension, error) {
+	var kfg []byte
+	var requests []runtime.RawExtension
+	for _, gr := range resources {
+		var err error
+		kfg, err = h.provisioner.Provision(ctx, session.Identity(), gr.Resource, gr.Group)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to provision %s.%s: %w", gr.Resource, gr.Group, err)
+		}
+
+		request := v1alpha1.APIServiceExportRequestResponse{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: v1alpha1.SchemeGroupVersion.String(),
+				Kind:       "APIServiceExportRequest",
+			},
+			ObjectMeta: v1alpha1.NameObjectMeta{
+				// this is good for one resource per request. If there are more (in the future),
+				// we need a better name heuristic. Note: it does not have to be unique.
+				// But pretty is better.
+				Name: gr.Resource + "." + gr.Group,
+			},
+			Spec: v1alpha1.APIServiceExportRequestSpec{
+				Resources: []v1alpha1.APIServiceExportRequestResource{
+					{GroupResource: gr},
+				},
+			},
+		}
+
+		for _, hook := range h.hooks {
+			if err := hook.AfterBind(ctx, session, &request); err != nil {
+				return nil, nil, fmt.Errorf("post-bind hook failed: %w", err)
+			}
+		}
+
+		requestBytes, err := json.Marshal(&request)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		requests = append(requests, runtime.RawExtension{Raw: requestBytes})
+	}
+
+	return kfg, requests, nil
+}
+
+// handleDeviceAuthorize starts the OAuth2 device authorization grant flow for
+// the kubectl-bind session in the "s" and cluster in the "c" form values.
+func (h *handler) handleDeviceAuthorize(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	a, err := h.devices.start(r.Form.Get("s"), r.Form.Get("c"), r.Form["r"], baseURL(r), time.Now())
+	if errors.Is(err, errTooManyDeviceAuthorizations) {
+		logger.Info("failed to start device authorization", "error", err)
+		w.Header().Set("Retry-After", strconv.Itoa(int(devicePollInterval.Seconds())))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	} else if err != nil {
+		logger.Info("failed to start device authorization", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	bs, err := json.Marshal(&v1alpha1.OAuth2DeviceAuthorization{
+		DeviceCode:              a.deviceCode,
+		UserCode:                formatUserCode(a.userCode),
+		VerificationURI:         verificationURI(baseURL(r)),
+		VerificationURIComplete: a.redirectURL,
+		ExpiresIn:               int(deviceCodeLifetime.Seconds()),
+		Interval:                int(devicePollInterval.Seconds()),
+	})
+	if err != nil {
+		logger.Error(err, "failed to marshal device authorization")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs) // nolint:errcheck
+}
+
+// handleDevice is the verification uri the user visits in the browser. It
+// starts the code grant flow for the session of the entered user code.
+func (h *handler) handleDevice(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	userCode := r.URL.Query().Get("user_code")
+	if userCode == "" {
+		h.renderDevicePage(w, r, deviceTemplateData{})
+		return
+	}
+	a, found := h.devices.lookup(userCode, time.Now())
+	if !found {
+		h.renderDevicePage(w, r, deviceTemplateData{UserCode: userCode, Error: "The code is invalid or has expired."})
+		return
+	}
+
+	grant := codeGrant(h.authenticator.AuthenticationMethods(r))
+	if grant == nil {
+		logger.Error(nil, "authenticator does not support the OAuth2 code grant")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	authURL, err := url.Parse(grant.AuthenticatedURL)
+	if err != nil {
+		logger.Error(err, "failed to parse auth url")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	values := authURL.Query()
+	values.Add("u", a.redirectURL)
+	values.Add("s", a.sessionID)
+	values.Add("c", a.clusterID)
+	for _, resource := range a.resources {
+		values.Add("r", resource)
+	}
+	authURL.RawQuery = values.Encode()
+
+	http.Redirect(w, r, authURL.String(), http.StatusFound)
+}
+
+// handleDeviceToken is polled by kubectl-bind with the device code. It returns
+// the BindingResponse once the user has bound a resource.
+func (h *handler) handleDeviceToken(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	response, errCode := h.devices.poll(r.Form.Get("device_code"), time.Now())
+
+	w.Header().Set("Content-Type", "application/json")
+	if errCode == "" {
+		w.Write(response) // nolint:errcheck
+		return
+	}
+
+	bs, err := json.Marshal(&v1alpha1.OAuth2DeviceTokenError{Error: errCode})
+	if err != nil {
+		logger.Error(err, "failed to marshal device token error")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(bs) // nolint:errcheck
+}
+
+// bindTokenRequest is the body of a request to mint a bind token.
+type bindTokenRequest struct {
+	Subject    string                   `json:"subject"`
+	Resources  []v1alpha1.GroupResource `json:"resources"`
+	Expiration metav1.Duration          `json:"expiration,omitempty"`
+}
+
+// bindTokenResponse is returned when a bind token is minted.
+type bindTokenResponse struct {
+	Name           string      `json:"name"`
+	Token          string      `json:"token"`
+	ExpirationTime metav1.Time `json:"expirationTime"`
+}
+
+const defaultBindTokenExpiration = 24 * time.Hour
+
+// handleMintBindToken mints a bind token for an administrator of the service
+// provider cluster, authenticated by the bearer token of the request.
+func (h *handler) handleMintBindToken(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	if err := h.tokens.AuthorizeMint(r.Context(), r); err != nil {
+		logger.Info("failed to authorize minting a bind token", "error", err)
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var req bindTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, fmt.Sprintf("failed to decode request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if req.Subject == "" || len(req.Resources) == 0 {
+		http.Error(w, "subject and resources are required", http.StatusBadRequest)
+		return
+	}
+	if req.Expiration.Duration < 0 {
+		http.Error(w, "expiration must be positive", http.StatusBadRequest)
+		return
+	} else if req.Expiration.Duration == 0 {
+		req.Expiration.Duration = defaultBindTokenExpiration
+	}
+	if err := h.exported(r.Context(), req.Resources); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	token, bt, err := h.tokens.Mint(r.Context(), req.Subject, req.Resources, req.Expiration.Duration)
+	if err != nil {
+		logger.Error(err, "failed to mint bind token")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	logger.Info("minted bind token", "name", bt.Name, "subject", req.Subject)
+
+	bs, err := json.Marshal(&bindTokenResponse{
+		Name:           bt.Name,
+		Token:          token,
+		ExpirationTime: bt.Spec.ExpirationTime,
+	})
+	if err != nil {
+		logger.Error(err, "failed to marshal bind token")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs) // nolint:errcheck
+}
+
+// handleBindTokenExchange returns the BindingResponse for the bind token in the
+// bearer token of the request, binding the kubectl-bind session in the "s"
+// and cluster in the "c" form values. The optional "r" form values restrict
+// the bound resources to a subset of those of the token.
+func (h *handler) handleBindTokenExchange(w http.ResponseWriter, r *http.Request) {
+	logger := klog.FromContext(r.Context()).WithValues("method", r.Method, "url", r.URL.String())
+
+	prepareNoCache(w)
+
+	token, found := bearerToken(r)
+	if !found {
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.Form.Get("s") == "" || r.Form.Get("c") == "" {
+		http.Error(w, "missing session id or cluster id", http.StatusBadRequest)
+		return
+	}
+
+	bt, err := h.tokens.Verify(r.Context(), token)
+	if errors.Is(err, errInvalidBindToken) {
+		logger.Info("rejected bind token", "error", err)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	} else if err != nil {
+		logger.Error(err, "failed to verify bind token")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	resources := bt.Spec.Resources
+	if selected := ParseResources(r.Form["r"]); len(selected) > 0 {
+		granted := sets.New[v1alpha1.GroupResource](resources...)
+		for _, gr := range selected {
+			if !granted.Has(gr) {
+				http.Error(w, fmt.Sprintf("resource %s.%s is not granted by the bind token", gr.Resource, gr.Group), http.StatusForbidden)
+				return
+			}
+		}
+		resources = selected
+	}
+	if err := h.exported(r.Context(), resources); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	session := &Session{
+		SessionID: r.Form.Get("s"),
+		ClusterID: r.Form.Get("c"),
+		// keep token subjects apart from the users of the authenticator
+		Subject: "bindtoken:" + bt.Spec.Subject,
+		Issuer:  bindTokenIssuer,
+	}
+	kfg, requests, err := h.bind(r.Context(), session, resources)
+	if err != nil {
+		logger.Error(err, "failed to bind")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	if err := h.tokens.MarkUsed(r.Context(), bt); err != nil {
+		logger.Error(err, "failed to record bind token usage", "name", bt.Name)
+	}
+
+	bs, err := json.Marshal(&v1alpha1.BindingResponse{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "BindingResponse",
+		},
+		Authentication: v1alpha1.BindingResponseAuthentication{
+			Token: &v1alpha1.BindingResponseAuthenticationToken{
+				SessionID: session.SessionID,
+				ID:        bt.Name,
+			},
+		},
+		Kubeconfig: kfg,
+		Requests:   requests,
+	})
+	if err != nil {
+		logger.Error(err, "failed to marshal binding response")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(bs) // nolint:errcheck
+}
+
+// exported checks that the resources are in the catalog.
+func (h *handler) exported(ctx context.Context, resources []v1alpha1.GroupResource) error {
+	crds, err := h.catalog.Exports(ctx)
+	if err != nil {
+		return err
+	}
+	exports := sets.New[v1alpha1.GroupResource]()
+	for _, crd := range crds {
+		exports.Insert(v1alpha1.GroupResource{Group: crd.Spec.Group, Resource: crd.Spec.Names.Plural})
+	}
+	for _, gr := range resources {
+		if !exports.Has(gr) {
+			return fmt.Errorf("resource %s.%s is not exported", gr.Resource, gr.Group)
+		}
+	}
+	return nil
+}
+
+type deviceTemplateData struct {
+	UserCode string
+	Error    string
+	Done     bool
+}
+
+func (h *handler) renderDevicePage(w http.ResponseWriter, r *http.Request, data deviceTemplateData) {
+	logger := klog.FromContext(r.Context())
+
+	bs := bytes.Buffer{}
+	if err := deviceTemplate.Execute(&bs, data); err != nil {
+		logger.Error(err, "failed to execute template")
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html")
+	w.Write(bs.Bytes()) // nolint:errcheck
+}
+
+// codeGrant returns the OAuth2 code grant of the methods, or nil.
+func codeGrant(methods []v1alpha1.AuthenticationMethod) *v1alpha1.OAuth2CodeGrant {
+	for _, m := range methods {
+		if m.Method == "OAuth2CodeGrant" && m.OAuth2CodeGrant != nil {
+			return m.OAuth2CodeGrant
+		}
+	}
+	return nil
+}
+
+// baseURL returns the url of the backend as seen by the client of the request.
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https" {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
+
+func mustRead(f func(name string) ([]byte, error), name string) string {
+	bs, err := f(name)
+	if err != nil {
+		panic(err)
+	}
+	return string(bs)
+}
@@ -17,7 +17,7 @@ limitations under the License.
 package kubernetes
 
 import (
-	"go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
+	"go.bytebuilders.dev/kube-bind/pkg/provider/resources"
 
 	corev1 "k8s.io/api/core/v1"
 )
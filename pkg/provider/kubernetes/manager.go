@@ -19,12 +19,13 @@ package kubernetes
 import (
 	"context"
 	"fmt"
+	"time"
 
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 	bindinformers "go.bytebuilders.dev/kube-bind/client/informers/externalversions/kubebind/v1alpha1"
 	bindlisters "go.bytebuilders.dev/kube-bind/client/listers/kubebind/v1alpha1"
-	kuberesources "go.bytebuilders.dev/kube-bind/contrib/example-backend/kubernetes/resources"
 	"go.bytebuilders.dev/kube-bind/pkg/indexers"
+	kuberesources "go.bytebuilders.dev/kube-bind/pkg/provider/resources"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -45,6 +46,8 @@ type Manager struct {
 	externalAddress       string
 	externalCA            []byte
 	externalTLSServerName string
+	tokenExpiration       time.Duration
+	maxCredentialLifetime time.Duration
 
 	kubeClient kubeclient.Interface
 	bindClient bindclient.Interface
@@ -62,11 +65,12 @@ func NewKubernetesManager(
 	externalAddress string,
 	externalCA []byte,
 	externalTLSServerName string,
+	tokenExpiration, maxCredentialLifetime time.Duration,
 	namespaceInformer corev1informers.NamespaceInformer,
 	exportInformer bindinformers.APIServiceExportInformer,
 ) (*Manager, error) {
 	config = rest.CopyConfig(config)
-	config = rest.AddUserAgent(config, "kube-bind-example-backend-kubernetes-manager")
+	config = rest.AddUserAgent(config, "kube-bind-provider-kubernetes-manager")
 
 	kubeClient, err := kubeclient.NewForConfig(config)
 	if err != nil {
@@ -85,6 +89,8 @@ func NewKubernetesManager(
 		externalAddress:       externalAddress,
 		externalCA:            externalCA,
 		externalTLSServerName: externalTLSServerName,
+		tokenExpiration:       tokenExpiration,
+		maxCredentialLifetime: maxCredentialLifetime,
 
 		kubeClient: kubeClient,
 		bindClient: bindClient,
@@ -103,7 +109,10 @@ func NewKubernetesManager(
 	return m, nil
 }
 
-func (m *Manager) HandleResources(ctx context.Context, identity, resource, group string) ([]byte, error) {
+// Provision creates the namespace, ClusterBinding and service account of the
+// consumer with the given identity, and returns a kubeconfig with an expiring
+// token of the service account.
+func (m *Manager) Provision(ctx context.Context, identity, resource, group string) ([]byte, error) {
 	logger := klog.FromContext(ctx).WithValues("identity", identity, "resource", resource, "group", group)
 	ctx = klog.NewContext(ctx, logger)
 
@@ -149,15 +158,25 @@ func (m *Manager) HandleResources(ctx context.Context, identity, resource, group
 		return nil, err
 	}
 
-	saSecret, err := kuberesources.CreateSASecret(ctx, m.kubeClient, ns, sa.Name)
+	token, err := kuberesources.RequestServiceAccountToken(ctx, m.kubeClient, ns, sa.Name, m.tokenExpiration)
 	if err != nil {
 		return nil, err
 	}
 
-	kfgSecret, err := kuberesources.GenerateKubeconfig(ctx, m.kubeClient, m.clusterConfig, m.externalAddress, m.externalCA, m.externalTLSServerName, saSecret.Name, ns, kubeconfigSecretName)
+	kfgSecret, err := kuberesources.GenerateKubeconfig(ctx, m.kubeClient, m.clusterConfig, m.externalAddress, m.externalCA, m.externalTLSServerName, token.Status.Token, ns, kubeconfigSecretName)
 	if err != nil {
 		return nil, err
 	}
 
+	// the konnector requests new credentials through the ClusterBinding before they expire,
+	// until the consumer has to bind again after the maximum credential lifetime.
+	now := time.Now()
+	if err := kuberesources.UpdateKubeconfigExpiration(ctx, m.bindClient, ns, token.Status.ExpirationTimestamp.Time, kuberesources.TokenRenewalTime(token, now), now.Add(m.maxCredentialLifetime)); err != nil {
+		return nil, err
+	}
+	if err := kuberesources.DeleteLegacySASecret(ctx, m.kubeClient, ns, sa.Name); err != nil {
+		return nil, err
+	}
+
 	return kfgSecret.Data["kubeconfig"], nil
 }
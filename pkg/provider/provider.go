@@ -0,0 +1,109 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package provider is a library to build kube-bind service provider backends.
+// A backend plugs its authentication, catalog, consumer provisioning and
+// post-bind hooks into the HTTP handler serving the kubectl-bind flow, and runs
+// the controllers reconciling the kube-bind objects of the consumers.
+package provider
+
+import (
+	"context"
+	"net/http"
+
+	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
+
+	"github.com/gorilla/mux"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// Session is an authenticated kubectl-bind session.
+type Session struct {
+	// SessionID is the id kubectl-bind started the flow with.
+	SessionID string
+	// ClusterID identifies the consumer cluster.
+	ClusterID string
+	// RedirectURL is where the BindingResponse is sent to.
+	RedirectURL string
+
+	// Subject and Issuer identify the authenticated user.
+	Subject, Issuer string
+
+	// Resources are the resources preselected by kubectl-bind with the "r"
+	// parameters of the authorize url. If not empty, the resource picker is
+	// skipped.
+	Resources []v1alpha1.GroupResource
+}
+
+// Identity returns the identity of the consumer, i.e. the user in the consumer cluster.
+func (s *Session) Identity() string {
+	return s.Subject + "#" + s.ClusterID
+}
+
+// Authenticator authenticates the users binding services.
+type Authenticator interface {
+	// AuthenticationMethods returns the methods advertised to kubectl-bind.
+	AuthenticationMethods(r *http.Request) []v1alpha1.AuthenticationMethod
+	// AddRoutes adds the routes of the authentication flow, which ends with a
+	// redirect to the resources page with the session id in the "s" parameter.
+	// The "r" parameters of the authorize url are to be kept in the session,
+	// see ParseResources.
+	AddRoutes(router *mux.Router)
+	// Session returns the authenticated session of the request.
+	Session(r *http.Request) (*Session, error)
+}
+
+// ParseResources parses the resources preselected by kubectl-bind, of the
+// form <resource>.<group>.
+func ParseResources(values []string) []v1alpha1.GroupResource {
+	var resources []v1alpha1.GroupResource
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		gr := schema.ParseGroupResource(v)
+		resources = append(resources, v1alpha1.GroupResource{Group: gr.Group, Resource: gr.Resource})
+	}
+	return resources
+}
+
+// Catalog lists the resources that can be bound.
+type Catalog interface {
+	// Exports returns the CRDs of the resources that can be bound.
+	Exports(ctx context.Context) ([]*apiextensionsv1.CustomResourceDefinition, error)
+}
+
+// Provisioner provisions consumers in the service provider cluster.
+type Provisioner interface {
+	// Provision prepares the service provider cluster for the consumer with the
+	// given identity binding the resource, and returns the kubeconfig for the
+	// konnector.
+	Provision(ctx context.Context, identity, resource, group string) ([]byte, error)
+}
+
+// PostBindHook is called after a consumer has been provisioned and before the
+// BindingResponse is sent. An error fails the binding.
+type PostBindHook interface {
+	AfterBind(ctx context.Context, session *Session, request *v1alpha1.APIServiceExportRequestResponse) error
+}
+
+// PostBindHookFunc is a function implementing PostBindHook.
+type PostBindHookFunc func(ctx context.Context, session *Session, request *v1alpha1.APIServiceExportRequestResponse) error
+
+func (f PostBindHookFunc) AfterBind(ctx context.Context, session *Session, request *v1alpha1.APIServiceExportRequestResponse) error {
+	return f(ctx, session, request)
+}
@@ -18,11 +18,13 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"go.bytebuilders.dev/kube-bind/apis/kubebind/v1alpha1"
 	bindclient "go.bytebuilders.dev/kube-bind/client/clientset/versioned"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 )
 
@@ -47,3 +49,30 @@ func CreateClusterBinding(ctx context.Context, client bindclient.Interface, ns,
 	_, err := client.KubeBindV1alpha1().ClusterBindings(ns).Create(ctx, clusterBinding, metav1.CreateOptions{})
 	return err
 }
+
+// UpdateKubeconfigExpiration records the renewal deadline of the credentials
+// in the kubeconfig secret in the ClusterBinding spec, out of reach of the
+// konnector, and their expiration and renewal time in the status.
+func UpdateKubeconfigExpiration(ctx context.Context, client bindclient.Interface, ns string, expiration, renewal, deadline time.Time) error {
+	logger := klog.FromContext(ctx)
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		clusterBinding, err := client.KubeBindV1alpha1().ClusterBindings(ns).Get(ctx, ClusterBindingName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		clusterBinding.Spec.KubeconfigRenewalDeadline = &metav1.Time{Time: deadline}
+
+		logger.V(3).Info("Updating ClusterBinding kubeconfig renewal deadline", "deadline", deadline)
+		clusterBinding, err = client.KubeBindV1alpha1().ClusterBindings(ns).Update(ctx, clusterBinding, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		clusterBinding.Status.KubeconfigExpirationTime = &metav1.Time{Time: expiration}
+		clusterBinding.Status.KubeconfigRenewalTime = &metav1.Time{Time: renewal}
+
+		logger.V(3).Info("Updating ClusterBinding kubeconfig expiration", "expiration", expiration)
+		_, err = client.KubeBindV1alpha1().ClusterBindings(ns).UpdateStatus(ctx, clusterBinding, metav1.UpdateOptions{})
+		return err
+	})
+}
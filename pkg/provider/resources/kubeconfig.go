@@ -19,12 +19,10 @@ package resources
 import (
 	"context"
 	"fmt"
-	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -33,13 +31,15 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// GenerateKubeconfig writes a kubeconfig with the given token for the external
+// address of the cluster into the kubeconfig secret.
 func GenerateKubeconfig(ctx context.Context,
 	client kubernetes.Interface,
 	clusterConfig *rest.Config,
 	externalAddress string,
 	externalCA []byte,
 	externalTLSServerName string,
-	saSecretName, ns, kubeconfigSecretName string,
+	token, ns, kubeconfigSecretName string,
 ) (*corev1.Secret, error) {
 	logger := klog.FromContext(ctx)
 
@@ -50,20 +50,6 @@ func GenerateKubeconfig(ctx context.Context,
 		externalCA = clusterConfig.CAData
 	}
 
-	var saSecret *corev1.Secret
-	logger.V(2).Info("Waiting for service account secret to be updated with a token", "name", saSecretName)
-	if err := wait.PollUntilContextTimeout(ctx, 500*time.Millisecond, 10*time.Second, true, func(ctx context.Context) (done bool, err error) {
-		saSecret, err = client.CoreV1().Secrets(ns).Get(ctx, saSecretName, v1.GetOptions{})
-		if err != nil && !errors.IsNotFound(err) {
-			return false, err
-		} else if errors.IsNotFound(err) {
-			return false, nil
-		}
-		return saSecret.Data["token"] != nil && saSecret.Data["ca.crt"] != nil, nil
-	}); err != nil {
-		return nil, err
-	}
-
 	cfg := clientcmdapi.Config{
 		Clusters: map[string]*clientcmdapi.Cluster{
 			"default": {
@@ -81,7 +67,7 @@ func GenerateKubeconfig(ctx context.Context,
 		},
 		AuthInfos: map[string]*clientcmdapi.AuthInfo{
 			"default": {
-				Token: string(saSecret.Data["token"]),
+				Token: token,
 			},
 		},
 		CurrentContext: "default",
@@ -124,3 +110,20 @@ func GenerateKubeconfig(ctx context.Context,
 	}
 	return updated, nil
 }
+
+// RenewKubeconfigToken returns the kubeconfig with the token of all users
+// replaced.
+func RenewKubeconfigToken(kubeconfig []byte, token string) ([]byte, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode kubeconfig: %w", err)
+	}
+	for _, authInfo := range cfg.AuthInfos {
+		authInfo.Token = token
+	}
+	renewed, err := clientcmd.Write(*cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode kubeconfig: %w", err)
+	}
+	return renewed, nil
+}
@@ -0,0 +1,105 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeclient "k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+)
+
+func CreateServiceAccount(ctx context.Context, client kubeclient.Interface, ns, name string) (*corev1.ServiceAccount, error) {
+	logger := klog.FromContext(ctx)
+
+	sa, err := client.CoreV1().ServiceAccounts(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			sa = &corev1.ServiceAccount{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: ns,
+				},
+			}
+
+			logger.Info("Creating service account", "name", sa.Name)
+			return client.CoreV1().ServiceAccounts(ns).Create(ctx, sa, metav1.CreateOptions{})
+		}
+	}
+
+	return sa, err
+}
+
+// EventsRoleName is the name of the Role and RoleBinding that let the konnector
+// read the events of a cluster namespace or service namespace. Events are not
+// granted cluster-wide, such that consumers cannot read the events of others.
+const EventsRoleName = "kube-binder-events"
+
+// EventsRole returns the Role granting read access to the events of namespace ns.
+func EventsRole(ns string) *rbacv1.Role {
+	return &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EventsRoleName,
+			Namespace: ns,
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{""},
+				Resources: []string{"events"},
+				Verbs:     []string{"get", "list", "watch"},
+			},
+		},
+	}
+}
+
+// EventsRoleBinding returns the RoleBinding of the EventsRole in namespace ns to
+// the service account of the consumer with the given cluster namespace.
+func EventsRoleBinding(ns, clusterNs string) *rbacv1.RoleBinding {
+	return &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      EventsRoleName,
+			Namespace: ns,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Namespace: clusterNs,
+				Name:      ServiceAccountName,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "Role",
+			Name:     EventsRoleName,
+		},
+	}
+}
+
+// IsolatedExportsRoleName is the name of the Role and RoleBinding that let the
+// konnector manage the objects of cluster-scoped APIs with Namespaced isolation
+// in its cluster namespace.
+const IsolatedExportsRoleName = "kube-binder-exports"
+
+// ConversionWebhookConsumerLabel holds the cluster namespace on the Roles and
+// RoleBindings that let the konnector of that consumer call the conversion
+// webhooks of the exported CRDs through the service proxy. They are named after
+// the cluster namespace, in the namespaces of the webhook services.
+const ConversionWebhookConsumerLabel = "kube-bind.appscode.com/conversion-webhook-consumer"
@@ -0,0 +1,75 @@
+/*
+Copyright AppsCode Inc. and Contributors
+
+Licensed under the AppsCode Community License 1.0.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://github.com/appscode/licenses/raw/1.0.0/AppsCode-Community-1.0.0.md
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resources
+
+import (
+	"context"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+)
+
+// MinTokenExpiration is the shortest lifetime of a service account token the
+// API server issues.
+const MinTokenExpiration = 10 * time.Minute
+
+// RequestServiceAccountToken issues a bound token for the service account that
+// expires after the given duration.
+func RequestServiceAccountToken(ctx context.Context, client kubernetes.Interface, ns, saName string, expiration time.Duration) (*authenticationv1.TokenRequest, error) {
+	logger := klog.FromContext(ctx)
+
+	logger.V(1).Info("Requesting service account token", "name", saName, "expiration", expiration)
+	return client.CoreV1().ServiceAccounts(ns).CreateToken(ctx, saName, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{
+			ExpirationSeconds: ptr.To(int64(expiration / time.Second)),
+		},
+	}, metav1.CreateOptions{})
+}
+
+// TokenRenewalTime returns the time after which the token should be renewed,
+// i.e. when a third of its lifetime is left.
+func TokenRenewalTime(token *authenticationv1.TokenRequest, now time.Time) time.Time {
+	expiration := token.Status.ExpirationTimestamp.Time
+	return expiration.Add(-expiration.Sub(now) / 3)
+}
+
+// DeleteLegacySASecret deletes the non-expiring service account token secret
+// created by earlier versions, invalidating its token.
+func DeleteLegacySASecret(ctx context.Context, client kubernetes.Interface, ns, saName string) error {
+	logger := klog.FromContext(ctx)
+
+	secret, err := client.CoreV1().Secrets(ns).Get(ctx, saName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if secret.Type != ServiceAccountTokenType || secret.Annotations[ServiceAccountTokenAnnotation] != saName {
+		return nil
+	}
+
+	logger.V(1).Info("Deleting legacy service account secret", "name", secret.Name)
+	if err := client.CoreV1().Secrets(ns).Delete(ctx, secret.Name, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
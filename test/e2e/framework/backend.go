@@ -65,6 +65,7 @@ func StartBackendWithoutDefaultArgs(t *testing.T, clientConfig *rest.Config, arg
 		kubebindv1alpha1.APIServiceExport{}.CustomResourceDefinition(),
 		kubebindv1alpha1.APIServiceNamespace{}.CustomResourceDefinition(),
 		kubebindv1alpha1.APIServiceExportRequest{}.CustomResourceDefinition(),
+		kubebindv1alpha1.BindToken{}.CustomResourceDefinition(),
 	})
 	require.NoError(t, err)
 
@@ -40,11 +40,14 @@ func StartKonnector(t *testing.T, clientConfig *rest.Config, args ...string) *ko
 	require.NoError(t, err)
 	err = apiextensions.RegisterCRDs(crdClient, []*apiextensions.CustomResourceDefinition{
 		kubebindv1alpha1.APIServiceBinding{}.CustomResourceDefinition(),
+		kubebindv1alpha1.BindingRequest{}.CustomResourceDefinition(),
 	})
 	require.NoError(t, err)
 
 	fs := pflag.NewFlagSet("konnector", pflag.ContinueOnError)
 	options := options.NewOptions()
+	// the API server cannot reach an in-process conversion webhook
+	options.ConversionWebhookBindAddress = "0"
 	options.AddFlags(fs)
 	err = fs.Parse(args)
 	require.NoError(t, err)
@@ -55,7 +58,7 @@ func StartKonnector(t *testing.T, clientConfig *rest.Config, args ...string) *ko
 	config, err := konnector.NewConfig(completed)
 	require.NoError(t, err)
 
-	server, err := konnector.NewServer(config)
+	server, err := konnector.NewServer(ctx, config)
 	require.NoError(t, err)
 	prepared, err := server.PrepareRun(ctx)
 	require.NoError(t, err)